@@ -344,19 +344,61 @@ type Map[K comparable, V any] interface {
 	// Clone returns a new hash map with copy of current map data.
 	Clone(safe ...bool) Map[K, V]
 
-	// Compute attempts to compute a mapping for the specified `key` and its current mapped value
-	// (or empty if there is no current mapping).
-	// For example, to either create or append a String msg to a value mapping:
-	//
-	// If the function `f` returns nil, the mapping is removed (or remains absent if initially absent).
-	// If the function itself returns an error, the error is rethrown, and the current mapping is left unchanged.
-	// todo implements me
-	//Compute(key K, f func(key K, value V) (V, error)) error
-
 	// String returns the map as a string.
 	String() string
 }
 
+// ExtendedList is a List with the convenient functions implemented by ArrayList.
+// They are not added to List, so that the implementations of List outside this package are not broken.
+type ExtendedList[T any] interface {
+	List[T]
+
+	// JoinFunc joins array elements with a string `glue`, each of which is formatted by `format`.
+	JoinFunc(glue string, format func(value T) string) string
+
+	// Page returns the elements of the `page`-th page of array, in which each page has `size` elements,
+	// and the page number `page` starts from 1. The last page may contain less than `size` elements.
+	// It returns nil if `page` or `size` is out of range.
+	Page(page, size int) []T
+
+	// PageCount returns the number of pages of array, in which each page has `size` elements.
+	PageCount(size int) int
+}
+
+// ExtendedMap is a Map with the convenient functions implemented by all the maps in this package.
+// They are not added to Map, so that the implementations of Map outside this package are not broken.
+type ExtendedMap[K comparable, V any] interface {
+	Map[K, V]
+
+	// PutAll batch sets key-values to the map, which is alias of Puts.
+	PutAll(data map[K]V)
+
+	// PutAllFrom batch sets the key-value pairs of `other` to the map, which can be any implementation of Map.
+	PutAllFrom(other Map[K, V])
+
+	// GetOrDefault returns the value by given `key`, or `defaultValue` if key is not found in the map.
+	GetOrDefault(key K, defaultValue V) V
+
+	// MustGet returns the value by given `key`, and panics if key is not found in the map.
+	MustGet(key K) V
+
+	// ForEachEntry iterates all entries in the map readonly like ForEach, with each key-value pair passed as an Entry.
+	ForEachEntry(f func(entry Entry[K, V]) bool)
+
+	// Entries returns all key-value pairs of the map as a slice of Entry, in the same order as ForEach.
+	Entries() []Entry[K, V]
+
+	// IterKeys iterates all keys in the map readonly like ForEach, without allocating a slice of them.
+	IterKeys(f func(key K) bool)
+
+	// IterValues iterates all values in the map readonly like ForEach, without allocating a slice of them.
+	IterValues(f func(value V) bool)
+
+	// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+	// all done under one lock.
+	ReplaceAll(f func(key K, value V) V)
+}
+
 // SortedMap is a Map that further provides a total ordering on its keys. The map is ordered according to
 // the natural ordering of its keys, or by a Comparator typically provided at sorted map creation time.
 // This order is reflected when iterating over the sorted map's collection views (returned by the entrySet,
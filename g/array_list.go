@@ -8,12 +8,18 @@ package g
 
 import (
 	"bytes"
+	"encoding/gob"
 	json2 "encoding/json"
 	"errors"
 	"fmt"
+	"hash/maphash"
 	"math"
+	"math/rand"
+	"runtime"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/wesleywu/gcontainer/internal/deepcopy"
 	"github.com/wesleywu/gcontainer/internal/json"
@@ -29,8 +35,69 @@ import (
 // It contains a concurrent-safe/unsafe switch, which should be set
 // when its initialization and cannot be changed then.
 type ArrayList[T any] struct {
-	mu    rwmutex.RWMutex
-	array []T
+	mu          rwmutex.RWMutex
+	array       []T
+	capPolicy   CapPolicy                                  // Growth policy of the underlying slice when appending items.
+	sortedBy    func(v1, v2 T) int                         // Comparator by which the array is declared sorted, nil if not declared.
+	observers   []func(op ChangeOp, index int, old, new T) // Observers registered by OnChange.
+	changes     []arrayListChange[T]                       // Changes not yet notified to the observers.
+	notifying   bool                                       // Whether the changes are being notified by a goroutine.
+	random      *lockedRand                                // Random source set by SetRandSource, nil for the global source.
+	jsonOptions *JSONOptions                               // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// lockedRand is a *rand.Rand guarded by a mutex, as *rand.Rand is not concurrent-safe
+// but it is used by read-locked methods like Rand.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// CapPolicy controls how the underlying slice of ArrayList grows when items are appended
+// beyond its capacity. The zero value uses the builtin growth of golang append.
+type CapPolicy struct {
+	// GrowthFactor is the factor the current capacity is multiplied by when the array grows.
+	// A factor of 1 allocates exactly the required capacity, and a factor less than 1 is treated as 2.
+	GrowthFactor float64
+
+	// MaxOverAlloc is the max number of extra slots allocated beyond the required length
+	// when the array grows. It is unlimited if it is <= 0.
+	MaxOverAlloc int
+}
+
+var (
+	// CapPolicyDefault uses the builtin growth of golang append.
+	CapPolicyDefault = CapPolicy{}
+
+	// CapPolicyExact never over-allocates, which uses the least memory but reallocates on every growth.
+	CapPolicyExact = CapPolicy{GrowthFactor: 1}
+
+	// CapPolicyCompact grows slowly and limits the over-allocation of large arrays.
+	CapPolicyCompact = CapPolicy{GrowthFactor: 1.25, MaxOverAlloc: 4096}
+
+	// CapPolicyAggressive always doubles the capacity, which reallocates the least.
+	CapPolicyAggressive = CapPolicy{GrowthFactor: 2}
+)
+
+// isDefault checks whether the policy uses the builtin growth of golang append.
+func (p CapPolicy) isDefault() bool {
+	return p.GrowthFactor <= 0 && p.MaxOverAlloc <= 0
+}
+
+// newCap calculates the new capacity for growing from capacity `oldCap` to hold `required` items.
+func (p CapPolicy) newCap(oldCap, required int) int {
+	factor := p.GrowthFactor
+	if factor < 1 {
+		factor = 2
+	}
+	newCap := int(float64(oldCap) * factor)
+	if newCap < required {
+		newCap = required
+	}
+	if p.MaxOverAlloc > 0 && newCap-required > p.MaxOverAlloc {
+		newCap = required + p.MaxOverAlloc
+	}
+	return newCap
 }
 
 // NewArrayList creates and returns an empty array.
@@ -74,6 +141,24 @@ func NewArrayListFrom[T any](array []T, safe ...bool) *ArrayList[T] {
 	}
 }
 
+// NewArrayListSorted creates and returns an array with given slice `array`, which is declared sorted
+// in the order of `comparator`, so that searching uses binary search. See SetSorted.
+// Note that `array` must already be sorted by `comparator`, it is not sorted again.
+func NewArrayListSorted[T any](array []T, comparator func(v1, v2 T) int, safe ...bool) *ArrayList[T] {
+	return &ArrayList[T]{
+		mu:       rwmutex.Create(safe...),
+		array:    array,
+		sortedBy: comparator,
+	}
+}
+
+// WrapArrayList creates and returns an array adopting the slice `array` without copying, which is the same as
+// NewArrayListFrom. It is used to pass existing slices to functions accepting arrays in O(1),
+// and the array shares the underlying memory with `array` until it grows.
+func WrapArrayList[T any](array []T, safe ...bool) *ArrayList[T] {
+	return NewArrayListFrom[T](array, safe...)
+}
+
 // NewArrayListFromCopy is alias of NewArrayFromCopy.
 // See NewArrayFromCopy.
 func NewArrayListFromCopy[T any](array []T, safe ...bool) *ArrayList[T] {
@@ -85,6 +170,22 @@ func NewArrayListFromCopy[T any](array []T, safe ...bool) *ArrayList[T] {
 	}
 }
 
+// FlattenArrayList creates and returns an array by concatenating all given `chunks` in order,
+// which is the inverse operation of Chunk.
+// The parameter `safe` is used to specify whether using array in concurrent-safety,
+// which is false in default.
+func FlattenArrayList[T any](chunks [][]T, safe ...bool) *ArrayList[T] {
+	length := 0
+	for _, chunk := range chunks {
+		length += len(chunk)
+	}
+	array := make([]T, 0, length)
+	for _, chunk := range chunks {
+		array = append(array, chunk...)
+	}
+	return NewArrayListFrom[T](array, safe...)
+}
+
 // MustGet returns the value by the specified index.
 // If the given `index` is out of range of the array, it returns empty value of type T.
 func (a *ArrayList[T]) MustGet(index int) (value T) {
@@ -104,14 +205,44 @@ func (a *ArrayList[T]) Get(index int) (value T, found bool) {
 	return a.array[index], true
 }
 
+// SetCapPolicy sets the growth policy of the underlying slice, which takes effect on the next growth.
+// See CapPolicyDefault, CapPolicyExact, CapPolicyCompact and CapPolicyAggressive for the presets.
+func (a *ArrayList[T]) SetCapPolicy(policy CapPolicy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.capPolicy = policy
+}
+
+// CapPolicy returns the growth policy of the underlying slice.
+func (a *ArrayList[T]) CapPolicy() CapPolicy {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.capPolicy
+}
+
+// grow ensures the underlying slice has capacity for another `n` items, following the growth policy.
+// It does nothing for the default policy, leaving the growth to golang append.
+func (a *ArrayList[T]) grow(n int) {
+	required := len(a.array) + n
+	if a.capPolicy.isDefault() || required <= cap(a.array) {
+		return
+	}
+	array := make([]T, len(a.array), a.capPolicy.newCap(cap(a.array), required))
+	copy(array, a.array)
+	a.array = array
+}
+
 // Set sets value to specified index.
 func (a *ArrayList[T]) Set(index int, value T) error {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if index < 0 || index >= len(a.array) {
 		return errors.New(fmt.Sprintf("index %d out of array range %d", index, len(a.array)))
 	}
+	old := a.array[index]
 	a.array[index] = value
+	a.sortedBy = nil
+	a.notifyWithoutLock(ChangeOpSet, index, old, value)
 	return nil
 }
 
@@ -128,43 +259,149 @@ func (a *ArrayList[T]) Sum() (sum int) {
 // Sort sorts the array by custom function `less`.
 func (a *ArrayList[T]) Sort(less func(v1, v2 T) bool) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	sort.Slice(a.array, func(i, j int) bool {
 		return less(a.array[i], a.array[j])
 	})
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
+}
+
+// SortBy stably sorts the array by the first comparator of `comparators`, and breaks ties with the
+// subsequent ones in order, e.g. sorting by priority first and then by timestamp.
+// Elements equal by all the comparators keep their original order.
+func (a *ArrayList[T]) SortBy(comparators ...func(v1, v2 T) int) List[T] {
+	if len(comparators) == 0 {
+		return a
+	}
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	slices.SortStableFunc(a.array, func(v1, v2 T) int {
+		for _, comparator := range comparators {
+			if c := comparator(v1, v2); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
+	return a
+}
+
+// SetSorted declares that the array is sorted in the order of `comparator`, so that Search, Contains,
+// RemoveValue and Remove use binary search instead of linear scan, and elements are considered equal
+// if `comparator` returns 0. It clears the declaration if `comparator` is nil.
+//
+// Note that the array is not sorted by SetSorted, and it is not kept sorted by methods like Add,
+// which still appends. It is the caller's responsibility to keep the array sorted while appending.
+// The declaration is cleared by the other methods which may break the order, like Set, Sort, InsertBefore,
+// PushLeft, Shuffle, Reverse and Walk, so that the searching never returns wrong results after them.
+func (a *ArrayList[T]) SetSorted(comparator func(v1, v2 T) int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sortedBy = comparator
+}
+
+// IsSorted returns true if the array is declared sorted by SetSorted or NewArrayListSorted.
+func (a *ArrayList[T]) IsSorted() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.sortedBy != nil
 }
 
 // InsertBefore inserts the `values` to the front of `index`.
 func (a *ArrayList[T]) InsertBefore(index int, values ...T) error {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if index < 0 || index >= len(a.array) {
 		return errors.New(fmt.Sprintf("index %d out of array range %d", index, len(a.array)))
 	}
 	rear := append([]T{}, a.array[index:]...)
+	a.grow(len(values))
 	a.array = append(a.array[0:index], values...)
 	a.array = append(a.array, rear...)
+	a.sortedBy = nil
+	a.notifyInsertWithoutLock(index, len(values))
 	return nil
 }
 
 // InsertAfter inserts the `values` to the back of `index`.
 func (a *ArrayList[T]) InsertAfter(index int, values ...T) error {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if index < 0 || index >= len(a.array) {
 		return errors.New(fmt.Sprintf("index %d out of array range %d", index, len(a.array)))
 	}
 	rear := append([]T{}, a.array[index+1:]...)
+	a.grow(len(values))
 	a.array = append(a.array[0:index+1], values...)
 	a.array = append(a.array, rear...)
+	a.sortedBy = nil
+	a.notifyInsertWithoutLock(index+1, len(values))
 	return nil
 }
 
+// InsertAllBefore inserts all the `values` to the front of `index` with a single move of the elements after it.
+func (a *ArrayList[T]) InsertAllBefore(index int, values []T) error {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	if index < 0 || index >= len(a.array) {
+		return errors.New(fmt.Sprintf("index %d out of array range %d", index, len(a.array)))
+	}
+	a.doInsertWithoutLock(index, values)
+	return nil
+}
+
+// InsertAllAfter inserts all the `values` to the back of `index` with a single move of the elements after it.
+func (a *ArrayList[T]) InsertAllAfter(index int, values []T) error {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	if index < 0 || index >= len(a.array) {
+		return errors.New(fmt.Sprintf("index %d out of array range %d", index, len(a.array)))
+	}
+	a.doInsertWithoutLock(index+1, values)
+	return nil
+}
+
+// doInsertWithoutLock inserts `values` at `index` without lock, which is in range [0, len].
+func (a *ArrayList[T]) doInsertWithoutLock(index int, values []T) {
+	if len(values) == 0 {
+		return
+	}
+	a.grow(len(values))
+	a.array = slices.Insert(a.array, index, values...)
+	a.sortedBy = nil
+	a.notifyInsertWithoutLock(index, len(values))
+}
+
+// RemoveRange removes the elements in range [`start`, `end`) with a single move of the elements after it,
+// and returns the removed elements. The range is truncated to the bounds of the array like Range,
+// and it returns nil if the range is empty.
+func (a *ArrayList[T]) RemoveRange(start, end int) []T {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	if start < 0 {
+		start = 0
+	}
+	if end > len(a.array) {
+		end = len(a.array)
+	}
+	if start >= end {
+		return nil
+	}
+	removed := make([]T, end-start)
+	copy(removed, a.array[start:end])
+	a.array = slices.Delete(a.array, start, end)
+	a.notifyRemoveWithoutLock(start, removed)
+	return removed
+}
+
 // RemoveAt removes an item by index.
 // If the given `index` is out of range of the array, the `found` is false.
 func (a *ArrayList[T]) RemoveAt(index int) (value T, found bool) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	return a.doRemoveWithoutLock(index)
 }
 
@@ -174,6 +411,11 @@ func (a *ArrayList[T]) doRemoveWithoutLock(index int) (value T, found bool) {
 		found = false
 		return
 	}
+	if len(a.observers) > 0 {
+		defer func() {
+			a.notifyRemoveWithoutLock(index, []T{value})
+		}()
+	}
 	// Determine array boundaries when deleting to improve deletion efficiency.
 	if index == 0 {
 		value := a.array[0]
@@ -196,7 +438,7 @@ func (a *ArrayList[T]) doRemoveWithoutLock(index int) (value T, found bool) {
 // It returns true if value is found in the array, or else false if not found.
 func (a *ArrayList[T]) RemoveValue(value T) bool {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if i := a.doSearchWithoutLock(value); i != -1 {
 		a.doRemoveWithoutLock(i)
 		return true
@@ -207,7 +449,7 @@ func (a *ArrayList[T]) RemoveValue(value T) bool {
 // Remove removes multiple items by `values`.
 func (a *ArrayList[T]) Remove(values ...T) bool {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	changed := false
 	for _, value := range values {
 		if i := a.doSearchWithoutLock(value); i != -1 {
@@ -223,7 +465,7 @@ func (a *ArrayList[T]) Remove(values ...T) bool {
 // RemoveAll removes multiple items by `values`.
 func (a *ArrayList[T]) RemoveAll(values Collection[T]) bool {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	changed := false
 	values.ForEach(func(value T) bool {
 		if i := a.doSearchWithoutLock(value); i != -1 {
@@ -240,8 +482,17 @@ func (a *ArrayList[T]) RemoveAll(values Collection[T]) bool {
 // PushLeft pushes one or multiple items to the beginning of array.
 func (a *ArrayList[T]) PushLeft(value ...T) List[T] {
 	a.mu.Lock()
-	a.array = append(value, a.array...)
-	a.mu.Unlock()
+	if a.capPolicy.isDefault() {
+		a.array = append(value, a.array...)
+	} else {
+		required := len(value) + len(a.array)
+		array := make([]T, 0, a.capPolicy.newCap(cap(a.array), required))
+		array = append(array, value...)
+		a.array = append(array, a.array...)
+	}
+	a.sortedBy = nil
+	a.notifyInsertWithoutLock(0, len(value))
+	a.unlockAndNotify()
 	return a
 }
 
@@ -249,8 +500,10 @@ func (a *ArrayList[T]) PushLeft(value ...T) List[T] {
 // It equals to Append.
 func (a *ArrayList[T]) PushRight(value ...T) List[T] {
 	a.mu.Lock()
+	a.grow(len(value))
 	a.array = append(a.array, value...)
-	a.mu.Unlock()
+	a.notifyInsertWithoutLock(len(a.array)-len(value), len(value))
+	a.unlockAndNotify()
 	return a
 }
 
@@ -258,24 +511,51 @@ func (a *ArrayList[T]) PushRight(value ...T) List[T] {
 // Note that if the array is empty, the `found` is false.
 func (a *ArrayList[T]) PopRand() (value T, found bool) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
-	return a.doRemoveWithoutLock(grand.Intn(len(a.array)))
+	defer a.unlockAndNotify()
+	return a.doRemoveWithoutLock(a.randIntn(len(a.array)))
 }
 
-// PopRands randomly pops and returns `size` items out of array.
+// PopRandWeighted randomly pops and returns an item out of array, with probability proportional
+// to its weight returned by `weight`. Items of non-positive weight are never picked.
+// Note that if the array is empty or there's no item of positive weight, the `found` is false.
+func (a *ArrayList[T]) PopRandWeighted(weight func(value T) int) (value T, found bool) {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	index := a.doRandWeightedIndexWithoutLock(weight)
+	if index < 0 {
+		return
+	}
+	return a.doRemoveWithoutLock(index)
+}
+
+// PopRands randomly pops and returns `size` distinct items out of array,
+// or all the items in random order if `size` >= length of array.
+// The remaining items keep their order, and it costs O(n) in total.
 func (a *ArrayList[T]) PopRands(size int) []T {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if size <= 0 || len(a.array) == 0 {
 		return nil
 	}
-	if size >= len(a.array) {
-		size = len(a.array)
+	var (
+		indices = randIndices(len(a.array), size, a.randIntn)
+		picked  = make([]bool, len(a.array))
+		array   = make([]T, len(indices))
+	)
+	for i, index := range indices {
+		array[i] = a.array[index]
+		picked[index] = true
 	}
-	array := make([]T, size)
-	for i := 0; i < size; i++ {
-		array[i], _ = a.doRemoveWithoutLock(grand.Intn(len(a.array)))
+	remaining := a.array[:0]
+	for i, v := range a.array {
+		if !picked[i] {
+			remaining = append(remaining, v)
+		} else {
+			a.notifyRemoveWithoutLock(len(remaining), []T{v})
+		}
 	}
+	clear(a.array[len(remaining):])
+	a.array = remaining
 	return array
 }
 
@@ -283,13 +563,14 @@ func (a *ArrayList[T]) PopRands(size int) []T {
 // Note that if the array is empty, the `found` is false.
 func (a *ArrayList[T]) PopLeft() (value T, found bool) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if len(a.array) == 0 {
 		found = false
 		return
 	}
 	value = a.array[0]
 	a.array = a.array[1:]
+	a.notifyRemoveWithoutLock(0, []T{value})
 	return value, true
 }
 
@@ -297,7 +578,7 @@ func (a *ArrayList[T]) PopLeft() (value T, found bool) {
 // Note that if the array is empty, the `found` is false.
 func (a *ArrayList[T]) PopRight() (value T, found bool) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	index := len(a.array) - 1
 	if index < 0 {
 		found = false
@@ -305,30 +586,33 @@ func (a *ArrayList[T]) PopRight() (value T, found bool) {
 	}
 	value = a.array[index]
 	a.array = a.array[:index]
+	a.notifyRemoveWithoutLock(index, []T{value})
 	return value, true
 }
 
 // PopLefts pops and returns `size` items from the beginning of array.
 func (a *ArrayList[T]) PopLefts(size int) []T {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if size <= 0 || len(a.array) == 0 {
 		return nil
 	}
 	if size >= len(a.array) {
 		array := a.array
 		a.array = a.array[:0]
+		a.notifyRemoveWithoutLock(0, array)
 		return array
 	}
 	value := a.array[0:size]
 	a.array = a.array[size:]
+	a.notifyRemoveWithoutLock(0, value)
 	return value
 }
 
 // PopRights pops and returns `size` items from the end of array.
 func (a *ArrayList[T]) PopRights(size int) []T {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if size <= 0 || len(a.array) == 0 {
 		return nil
 	}
@@ -336,10 +620,12 @@ func (a *ArrayList[T]) PopRights(size int) []T {
 	if index <= 0 {
 		array := a.array
 		a.array = a.array[:0]
+		a.notifyRemoveWithoutLock(0, array)
 		return array
 	}
 	value := a.array[index:]
 	a.array = a.array[:index]
+	a.notifyRemoveWithoutLock(index, value)
 	return value
 }
 
@@ -373,6 +659,50 @@ func (a *ArrayList[T]) Range(start int, end ...int) []T {
 	return array
 }
 
+// Page returns the elements of the `page`-th page of array, in which each page has `size` elements,
+// and the page number `page` starts from 1. The last page may contain less than `size` elements.
+// It returns nil if `page` < 1, `size` <= 0 or `page` > PageCount(size).
+// Notice, if in concurrent-safe usage, it returns a copy of slice;
+// else a pointer to the underlying data.
+func (a *ArrayList[T]) Page(page, size int) []T {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if page < 1 || size <= 0 || page > pageCount(len(a.array), size) {
+		return nil
+	}
+	var (
+		start = (page - 1) * size
+		end   = start + min(size, len(a.array)-start)
+	)
+	if a.mu.IsSafe() {
+		array := make([]T, end-start)
+		copy(array, a.array[start:end])
+		return array
+	}
+	return a.array[start:end]
+}
+
+// PageCount returns the number of pages of array, in which each page has `size` elements.
+// It returns 0 if `size` <= 0.
+func (a *ArrayList[T]) PageCount(size int) int {
+	if size <= 0 {
+		return 0
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return pageCount(len(a.array), size)
+}
+
+// pageCount returns the number of pages of `length` elements, in which each page has `size` elements.
+// It doesn't compute length+size-1, which overflows for large `size`.
+func pageCount(length, size int) int {
+	count := length / size
+	if length%size != 0 {
+		count++
+	}
+	return count
+}
+
 // SubSlice returns a slice of elements from the array as specified
 // by the `offset` and `size` parameters.
 // If in concurrent safe usage, it returns a copy of the slice; else a pointer.
@@ -474,8 +804,9 @@ func (a *ArrayList[T]) Clone() (newArray Collection[T]) {
 	a.mu.RLock()
 	array := make([]T, len(a.array))
 	copy(array, a.array)
+	sortedBy := a.sortedBy
 	a.mu.RUnlock()
-	return NewArrayListFrom[T](array, a.mu.IsSafe())
+	return NewArrayListSorted[T](array, sortedBy, a.mu.IsSafe())
 }
 
 // Clear deletes all items of current array.
@@ -483,8 +814,9 @@ func (a *ArrayList[T]) Clear() {
 	a.mu.Lock()
 	if len(a.array) > 0 {
 		a.array = make([]T, 0)
+		a.notifyResetWithoutLock()
 	}
-	a.mu.Unlock()
+	a.unlockAndNotify()
 }
 
 // Contains checks whether a value exists in the array.
@@ -530,6 +862,49 @@ func (a *ArrayList[T]) Equals(another Collection[T]) bool {
 	return true
 }
 
+// Compare compares the array with `another` lexicographically, in which the elements are compared
+// in order by `cmp` and the first unequal pair decides the result. If all the elements of the shorter one
+// are equal to the other, the shorter one is less.
+// It returns -1 if the array is less than `another`, 1 if greater, or 0 if they are equal.
+func (a *ArrayList[T]) Compare(another Collection[T], cmp func(v1, v2 T) int) int {
+	anotherArray := another.Slice()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for i := 0; i < len(a.array) && i < len(anotherArray); i++ {
+		if c := cmp(a.array[i], anotherArray[i]); c != 0 {
+			if c < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a.array) < len(anotherArray):
+		return -1
+	case len(a.array) > len(anotherArray):
+		return 1
+	}
+	return 0
+}
+
+// EqualsFunc checks whether the array and `another` have the same length,
+// and `eq` returns true for each pair of elements in the same position.
+// Unlike Equals, `another` can be any collection, e.g. a LinkedList.
+func (a *ArrayList[T]) EqualsFunc(another Collection[T], eq func(v1, v2 T) bool) bool {
+	anotherArray := another.Slice()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if len(a.array) != len(anotherArray) {
+		return false
+	}
+	for i, v := range a.array {
+		if !eq(v, anotherArray[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // ContainsI checks whether a value exists in the array with case-insensitively.
 // Note that it internally iterates the whole array to do the comparison with case-insensitively.
 func (a *ArrayList[T]) ContainsI(value T) bool {
@@ -561,6 +936,9 @@ func (a *ArrayList[T]) doSearchWithoutLock(value T) int {
 	if len(a.array) == 0 {
 		return -1
 	}
+	if a.sortedBy != nil {
+		return a.doBinarySearchWithoutLock(value)
+	}
 	result := -1
 	for index, v := range a.array {
 		if equal.Equals(v, value) {
@@ -571,11 +949,23 @@ func (a *ArrayList[T]) doSearchWithoutLock(value T) int {
 	return result
 }
 
+// doBinarySearchWithoutLock returns the index of the first element equal to `value`
+// by binary search with the declared comparator, or -1 if not found.
+func (a *ArrayList[T]) doBinarySearchWithoutLock(value T) int {
+	index := sort.Search(len(a.array), func(i int) bool {
+		return a.sortedBy(a.array[i], value) >= 0
+	})
+	if index < len(a.array) && a.sortedBy(a.array[index], value) == 0 {
+		return index
+	}
+	return -1
+}
+
 // Unique uniques the array, clear repeated items.
 // Example: [1,1,2,3,2] -> [1,2,3]
 func (a *ArrayList[T]) Unique() List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if len(a.array) == 0 {
 		return a
 	}
@@ -594,14 +984,42 @@ func (a *ArrayList[T]) Unique() List[T] {
 		uniqueArray = append(uniqueArray, temp)
 	}
 	a.array = uniqueArray
+	a.notifyResetWithoutLock()
+	return a
+}
+
+// UniqueFunc removes the elements for which `eq` returns true with any previous element,
+// keeping the first-seen ones in their order. It costs O(n^2), use UniqueArrayListBy if a comparable key is available.
+// Example: UniqueFunc(strings.EqualFold) on [a,B,A,b,c] -> [a,B,c]
+func (a *ArrayList[T]) UniqueFunc(eq func(v1, v2 T) bool) List[T] {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	uniqueArray := a.array[:0]
+	for _, v := range a.array {
+		found := false
+		for _, u := range uniqueArray {
+			if eq(u, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			uniqueArray = append(uniqueArray, v)
+		}
+	}
+	clear(a.array[len(uniqueArray):])
+	a.array = uniqueArray
+	a.notifyResetWithoutLock()
 	return a
 }
 
 // LockFunc locks writing by callback function `f`.
 func (a *ArrayList[T]) LockFunc(f func(array []T)) {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	f(a.array)
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
 }
 
 // RLockFunc locks reading by callback function `f`.
@@ -615,15 +1033,22 @@ func (a *ArrayList[T]) RLockFunc(f func(array []T)) {
 // keys starting at the `startIndex` parameter.
 func (a *ArrayList[T]) Fill(startIndex int, num int, value T) error {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if startIndex < 0 || startIndex > len(a.array) {
 		return errors.New(fmt.Sprintf("index %d out of array range %d", startIndex, len(a.array)))
 	}
+	if n := startIndex + num - len(a.array); n > 0 {
+		a.grow(n)
+	}
 	for i := startIndex; i < startIndex+num; i++ {
 		if i > len(a.array)-1 {
 			a.array = append(a.array, value)
+			a.notifyInsertWithoutLock(i, 1)
 		} else {
+			old := a.array[i]
 			a.array[i] = value
+			a.sortedBy = nil
+			a.notifyWithoutLock(ChangeOpSet, i, old, value)
 		}
 	}
 	return nil
@@ -652,13 +1077,57 @@ func (a *ArrayList[T]) Chunk(size int) [][]T {
 	return n
 }
 
+// ChunkEach splits an array into chunks of `size` elements like Chunk, but calls `f` with the chunks
+// one at a time instead of building all of them up front.
+// If `f` returns true, then it continues iterating; or false to stop.
+//
+// Note that the array is read-locked until it returns, and the chunks share the underlying memory
+// of array, so they must not be modified or retained after `f` returns.
+func (a *ArrayList[T]) ChunkEach(size int, f func(chunk []T) bool) {
+	if size < 1 {
+		return
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for start := 0; start < len(a.array); start += size {
+		end := min(start+size, len(a.array))
+		if !f(a.array[start:end:end]) {
+			return
+		}
+	}
+}
+
+// Interleave returns a new array with elements taken alternately from current array and `other`,
+// starting with current array. The remaining elements of the longer one are appended to the end.
+// Example: [1,2,3] interleaved with [a,b] -> [1,a,2,b,3]
+func (a *ArrayList[T]) Interleave(other Collection[T]) *ArrayList[T] {
+	var otherArray = other.Slice()
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var (
+		i, j  int
+		array = make([]T, 0, len(a.array)+len(otherArray))
+	)
+	for i < len(a.array) || j < len(otherArray) {
+		if i < len(a.array) {
+			array = append(array, a.array[i])
+			i++
+		}
+		if j < len(otherArray) {
+			array = append(array, otherArray[j])
+			j++
+		}
+	}
+	return NewArrayListFrom[T](array, a.mu.IsSafe())
+}
+
 // Pad pads array to the specified length with `value`.
 // If size is positive then the array is padded on the right, or negative on the left.
 // If the absolute value of `size` is less than or equal to the length of the array
 // then no padding takes place.
 func (a *ArrayList[T]) Pad(size int, val T) List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if size == 0 || (size > 0 && size < len(a.array)) || (size < 0 && size > -len(a.array)) {
 		return a
 	}
@@ -672,13 +1141,62 @@ func (a *ArrayList[T]) Pad(size int, val T) List[T] {
 		tmp[i] = val
 	}
 	if size > 0 {
+		a.grow(n)
 		a.array = append(a.array, tmp...)
+		a.notifyInsertWithoutLock(len(a.array)-n, n)
 	} else {
 		a.array = append(tmp, a.array...)
+		a.sortedBy = nil
+		a.notifyInsertWithoutLock(0, n)
+	}
+	return a
+}
+
+// Resize changes the length of array to `n`, appending `fill` to grow it,
+// or removing the items from the end to truncate it like Truncate.
+// It treats negative `n` as 0.
+func (a *ArrayList[T]) Resize(n int, fill T) List[T] {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	if n <= len(a.array) {
+		a.doTruncateWithoutLock(n)
+		return a
 	}
+	length := len(a.array)
+	a.grow(n - length)
+	for len(a.array) < n {
+		a.array = append(a.array, fill)
+	}
+	a.notifyInsertWithoutLock(length, n-length)
+	return a
+}
+
+// Truncate removes the items from the end of array, keeping at most `n` items.
+// Unlike PopRights, it keeps the capacity of array and clears the removed slots,
+// so that a fixed-size buffer can be reused without reallocation.
+// It treats negative `n` as 0.
+func (a *ArrayList[T]) Truncate(n int) List[T] {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	a.doTruncateWithoutLock(n)
 	return a
 }
 
+// doTruncateWithoutLock keeps at most `n` items of array without lock.
+func (a *ArrayList[T]) doTruncateWithoutLock(n int) {
+	if n < 0 {
+		n = 0
+	}
+	if n >= len(a.array) {
+		return
+	}
+	if len(a.observers) > 0 {
+		a.notifyRemoveWithoutLock(n, slices.Clone(a.array[n:]))
+	}
+	clear(a.array[n:])
+	a.array = a.array[:n]
+}
+
 // Rand randomly returns one item from array(no deleting).
 func (a *ArrayList[T]) Rand() (value T, found bool) {
 	a.mu.RLock()
@@ -687,10 +1205,50 @@ func (a *ArrayList[T]) Rand() (value T, found bool) {
 		found = false
 		return
 	}
-	return a.array[grand.Intn(len(a.array))], true
+	return a.array[a.randIntn(len(a.array))], true
+}
+
+// RandWeighted randomly returns one item from array(no deleting), with probability proportional
+// to its weight returned by `weight`. Items of non-positive weight are never picked.
+// Note that if the array is empty or there's no item of positive weight, the `found` is false.
+func (a *ArrayList[T]) RandWeighted(weight func(value T) int) (value T, found bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	index := a.doRandWeightedIndexWithoutLock(weight)
+	if index < 0 {
+		return
+	}
+	return a.array[index], true
+}
+
+// doRandWeightedIndexWithoutLock returns a random index of array picked by weights,
+// or -1 if the total weight is not positive. It calls `weight` once for each item.
+func (a *ArrayList[T]) doRandWeightedIndexWithoutLock(weight func(value T) int) int {
+	var (
+		total   int
+		weights = make([]int, len(a.array))
+	)
+	for i, v := range a.array {
+		if w := weight(v); w > 0 {
+			weights[i] = w
+			total += w
+		}
+	}
+	if total <= 0 {
+		return -1
+	}
+	r := a.randIntn(total)
+	for i, w := range weights {
+		if r < w {
+			return i
+		}
+		r -= w
+	}
+	return -1
 }
 
 // Rands randomly returns `size` items from array(no deleting).
+// Note that it samples with replacement, so an item may be returned more than once, see RandsUnique.
 func (a *ArrayList[T]) Rands(size int) []T {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -699,28 +1257,109 @@ func (a *ArrayList[T]) Rands(size int) []T {
 	}
 	array := make([]T, size)
 	for i := 0; i < size; i++ {
-		array[i] = a.array[grand.Intn(len(a.array))]
+		array[i] = a.array[a.randIntn(len(a.array))]
 	}
 	return array
 }
 
+// RandsUnique randomly returns `size` items at distinct positions of array(no deleting),
+// or all the items in random order if `size` >= length of array.
+// Unlike Rands, which samples with replacement, it never picks an item twice, and it costs O(size).
+func (a *ArrayList[T]) RandsUnique(size int) []T {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if size <= 0 || len(a.array) == 0 {
+		return nil
+	}
+	indices := randIndices(len(a.array), size, a.randIntn)
+	array := make([]T, len(indices))
+	for i, index := range indices {
+		array[i] = a.array[index]
+	}
+	return array
+}
+
+// randIndices returns min(`k`, `n`) distinct random indices in [0, `n`) in random order generated by `intn`,
+// using a partial Fisher–Yates shuffle on a sparse permutation, which costs O(k).
+func randIndices(n, k int, intn func(n int) int) []int {
+	if k > n {
+		k = n
+	}
+	var (
+		indices = make([]int, k)
+		swapped = make(map[int]int, 2*k)
+		valueAt = func(i int) int {
+			if v, ok := swapped[i]; ok {
+				return v
+			}
+			return i
+		}
+	)
+	for i := 0; i < k; i++ {
+		j := i + intn(n-i)
+		vi, vj := valueAt(i), valueAt(j)
+		swapped[j] = vi
+		indices[i] = vj
+	}
+	return indices
+}
+
+// SetRandSource sets the random source used by the random methods of array, like Shuffle, Rand and PopRand,
+// so that the results are reproducible with a seeded source, e.g. rand.New(rand.NewSource(1)) in tests.
+// It restores the global random source if `r` is nil.
+//
+// Note that `r` should not be used elsewhere after it is set, and it is not copied by Clone.
+func (a *ArrayList[T]) SetRandSource(r *rand.Rand) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if r == nil {
+		a.random = nil
+		return
+	}
+	a.random = &lockedRand{r: r}
+}
+
+// randIntn returns a random int in [0, `n`) from the random source of array.
+func (a *ArrayList[T]) randIntn(n int) int {
+	if a.random == nil || n <= 0 {
+		return grand.Intn(n)
+	}
+	a.random.mu.Lock()
+	defer a.random.mu.Unlock()
+	return a.random.r.Intn(n)
+}
+
+// randPerm returns a random permutation of [0, `n`) from the random source of array.
+func (a *ArrayList[T]) randPerm(n int) []int {
+	if a.random == nil {
+		return grand.Perm(n)
+	}
+	a.random.mu.Lock()
+	defer a.random.mu.Unlock()
+	return a.random.r.Perm(n)
+}
+
 // Shuffle randomly shuffles the array.
 func (a *ArrayList[T]) Shuffle() List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
-	for i, v := range grand.Perm(len(a.array)) {
+	defer a.unlockAndNotify()
+	for i, v := range a.randPerm(len(a.array)) {
 		a.array[i], a.array[v] = a.array[v], a.array[i]
 	}
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
 	return a
 }
 
 // Reverse makes array with elements in reverse order.
 func (a *ArrayList[T]) Reverse() List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	for i, j := 0, len(a.array)-1; i < j; i, j = i+1, j-1 {
 		a.array[i], a.array[j] = a.array[j], a.array[i]
 	}
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
 	return a
 }
 
@@ -741,6 +1380,21 @@ func (a *ArrayList[T]) Join(glue string) string {
 	return buffer.String()
 }
 
+// JoinFunc joins array elements with a string `glue`, each of which is formatted by `format`
+// instead of gconv.String, e.g. quoting or truncating the elements.
+func (a *ArrayList[T]) JoinFunc(glue string, format func(value T) string) string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	buffer := bytes.NewBuffer(nil)
+	for k, v := range a.array {
+		if k > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(format(v))
+	}
+	return buffer.String()
+}
+
 // CountValues counts the number of occurrences of all values in the array.
 func (a *ArrayList[T]) CountValues() map[any]int {
 	m := make(map[any]int)
@@ -788,6 +1442,28 @@ func (a *ArrayList[T]) ForEachDesc(f func(k int, v T) bool) {
 	}
 }
 
+// ForEachRange iterates the items within index range [start, end) readonly in ascending order
+// with given callback function `f`, like array[start:end] without copying the underlying data.
+// If `f` returns true, then it continues iterating; or false to stop.
+//
+// The `start` is adjusted to 0 if it is negative, and the `end` is adjusted to the length
+// of array if it exceeds the array boundary.
+func (a *ArrayList[T]) ForEachRange(start, end int, f func(index int, value T) bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if start < 0 {
+		start = 0
+	}
+	if end > len(a.array) {
+		end = len(a.array)
+	}
+	for i := start; i < end; i++ {
+		if !f(i, a.array[i]) {
+			break
+		}
+	}
+}
+
 // String returns current array as a string, which implements like json.Marshal does.
 func (a *ArrayList[T]) String() string {
 	if a == nil {
@@ -813,12 +1489,38 @@ func (a *ArrayList[T]) String() string {
 	return buffer.String()
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// `%#v` prints the pretty form annotated with type, length and capacity for debugging,
+// and the other verbs print the elements formatted with the verb, like `[001 002]` for `%03d`.
+func (a *ArrayList[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, a)
+}
+
+// formatEntries implements the interface formattable.
+func (a *ArrayList[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = a.Size()
+	return collectFormatValues(a.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is sensitive to the order of elements.
+func (a *ArrayList[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashOrdered(seed, a.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (a *ArrayList[T]) SetMarshalOptions(options JSONOptions) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 // Note that do not use pointer as its receiver here.
 func (a ArrayList[T]) MarshalJSON() ([]byte, error) {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
-	return json.Marshal(a.array)
+	return marshalJSONSlice(a.jsonOptions, a.array)
 }
 
 // UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
@@ -827,17 +1529,54 @@ func (a *ArrayList[T]) UnmarshalJSON(b []byte) error {
 		a.array = make([]T, 0)
 	}
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	if err := json.UnmarshalUseNumber(b, &a.array); err != nil {
 		return err
 	}
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
 	return nil
 }
 
+// MarshalBinary implements the interface encoding.BinaryMarshaler, which encodes the elements with gob,
+// so that the array can be used in gob streams and binary caches directly.
+func (a *ArrayList[T]) MarshalBinary() ([]byte, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	buffer := bytes.NewBuffer(nil)
+	if err := gob.NewEncoder(buffer).Encode(a.array); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which replaces the elements of array with the ones decoded from `data` encoded by MarshalBinary.
+func (a *ArrayList[T]) UnmarshalBinary(data []byte) error {
+	var array []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&array); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	a.array = array
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
+	return nil
+}
+
+// RegisterArrayListGob registers type *ArrayList[T] to gob, which is required when arrays of type T
+// are encoded as interface values, e.g. elements of []any or fields of interface type.
+func RegisterArrayListGob[T any]() {
+	gob.Register(&ArrayList[T]{})
+}
+
 // UnmarshalValue is an interface implement which sets any type of value for array.
 func (a *ArrayList[T]) UnmarshalValue(value interface{}) error {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
+	defer a.notifyResetWithoutLock()
+	a.sortedBy = nil
 	switch value.(type) {
 	case string, []byte, json2.Number:
 		return json.UnmarshalUseNumber(gconv.Bytes(value), &a.array)
@@ -852,10 +1591,12 @@ func (a *ArrayList[T]) UnmarshalValue(value interface{}) error {
 // it or else does nothing and continues iterating.
 func (a *ArrayList[T]) Filter(filter func(index int, value T) bool) List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	for i := 0; i < len(a.array); {
 		if filter(i, a.array[i]) {
+			value := a.array[i]
 			a.array = append(a.array[:i], a.array[i+1:]...)
+			a.notifyRemoveWithoutLock(i, []T{value})
 		} else {
 			i++
 		}
@@ -866,10 +1607,12 @@ func (a *ArrayList[T]) Filter(filter func(index int, value T) bool) List[T] {
 // FilterNil removes all nil value of the array.
 func (a *ArrayList[T]) FilterNil() List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	for i := 0; i < len(a.array); {
 		if empty.IsNil(a.array[i]) {
+			value := a.array[i]
 			a.array = append(a.array[:i], a.array[i+1:]...)
+			a.notifyRemoveWithoutLock(i, []T{value})
 		} else {
 			i++
 		}
@@ -881,10 +1624,12 @@ func (a *ArrayList[T]) FilterNil() List[T] {
 // Values like: 0, nil, false, "", len(slice/map/chan) == 0 are considered empty.
 func (a *ArrayList[T]) FilterEmpty() List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
 	for i := 0; i < len(a.array); {
 		if empty.IsEmpty(a.array[i]) {
+			value := a.array[i]
 			a.array = append(a.array[:i], a.array[i+1:]...)
+			a.notifyRemoveWithoutLock(i, []T{value})
 		} else {
 			i++
 		}
@@ -895,13 +1640,339 @@ func (a *ArrayList[T]) FilterEmpty() List[T] {
 // Walk applies a user supplied function `f` to every item of array.
 func (a *ArrayList[T]) Walk(f func(value T) T) List[T] {
 	a.mu.Lock()
-	defer a.mu.Unlock()
+	defer a.unlockAndNotify()
+	a.sortedBy = nil
 	for i, v := range a.array {
 		a.array[i] = f(v)
+		a.notifyWithoutLock(ChangeOpSet, i, v, a.array[i])
 	}
 	return a
 }
 
+// ParallelForEach calls `f` on every element of the array concurrently, by partitioning the array
+// into `workers` contiguous chunks processed by separate goroutines.
+// The number of CPUs is used if `workers` <= 0.
+// It waits for all the calls, and returns the errors returned by `f` joined by errors.Join, or nil if no error.
+// Note that the array is read-locked until it returns, and the order of calls is not determined.
+func (a *ArrayList[T]) ParallelForEach(workers int, f func(index int, value T) error) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	var (
+		mu   sync.Mutex
+		errs []error
+	)
+	a.doParallelWithoutLock(workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			if err := f(i, a.array[i]); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// ParallelWalk applies `f` to every element of the array concurrently like Walk, by partitioning the array
+// into `workers` contiguous chunks processed by separate goroutines.
+// The number of CPUs is used if `workers` <= 0.
+func (a *ArrayList[T]) ParallelWalk(workers int, f func(value T) T) List[T] {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	a.doParallelWithoutLock(workers, func(start, end int) {
+		for i := start; i < end; i++ {
+			a.array[i] = f(a.array[i])
+		}
+	})
+	a.sortedBy = nil
+	a.notifyResetWithoutLock()
+	return a
+}
+
+// doParallelWithoutLock partitions the array into at most `workers` contiguous ranges [start, end),
+// calls `f` with each range in a separate goroutine, and waits for all the calls.
+func (a *ArrayList[T]) doParallelWithoutLock(workers int, f func(start, end int)) {
+	length := len(a.array)
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > length {
+		workers = length
+	}
+	if workers <= 1 {
+		f(0, length)
+		return
+	}
+	var (
+		wg        sync.WaitGroup
+		chunkSize = (length + workers - 1) / workers
+	)
+	for start := 0; start < length; start += chunkSize {
+		end := start + chunkSize
+		if end > length {
+			end = length
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			f(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// Map returns a new array with the results of calling `f` on every element of current array.
+// Unlike Walk, it does not modify current array.
+func (a *ArrayList[T]) Map(f func(value T) T) *ArrayList[T] {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	array := make([]T, len(a.array))
+	for i, v := range a.array {
+		array[i] = f(v)
+	}
+	return NewArrayListFrom[T](array, a.mu.IsSafe())
+}
+
+// FlatMap returns a new array with the concatenated results of calling `f` on every element of current array.
+func (a *ArrayList[T]) FlatMap(f func(value T) []T) *ArrayList[T] {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	array := make([]T, 0, len(a.array))
+	for _, v := range a.array {
+		array = append(array, f(v)...)
+	}
+	return NewArrayListFrom[T](array, a.mu.IsSafe())
+}
+
+// Reduce reduces the array to a single value by calling `f` on every element from left to right,
+// which is passed the result of previous call as `acc`.
+// The first element is used as the initial `acc`, and it returns zero value of T if the array is empty.
+// Example: [1,2,3] reduced with sum -> 6
+func (a *ArrayList[T]) Reduce(f func(acc, value T) T) (result T) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if len(a.array) == 0 {
+		return
+	}
+	result = a.array[0]
+	for _, v := range a.array[1:] {
+		result = f(result, v)
+	}
+	return
+}
+
+// MapArrayList returns a new array of type U with the results of calling `f` on every element of `array`.
+func MapArrayList[T any, U any](array *ArrayList[T], f func(value T) U) *ArrayList[U] {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	newArray := make([]U, len(array.array))
+	for i, v := range array.array {
+		newArray[i] = f(v)
+	}
+	return NewArrayListFrom[U](newArray, array.mu.IsSafe())
+}
+
+// DiffArrayList returns a new array with the distinct elements of `array` not contained in `values`,
+// in the order of their first occurrence in `array`.
+// It uses a hash set internally, so it costs O(n+m).
+func DiffArrayList[T comparable](array *ArrayList[T], values []T) *ArrayList[T] {
+	exclude := make(map[T]struct{}, len(values))
+	for _, v := range values {
+		exclude[v] = struct{}{}
+	}
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	newArray := make([]T, 0, len(array.array))
+	for _, v := range array.array {
+		if _, ok := exclude[v]; !ok {
+			exclude[v] = struct{}{}
+			newArray = append(newArray, v)
+		}
+	}
+	return NewArrayListFrom[T](newArray, array.mu.IsSafe())
+}
+
+// DiffArrayListCollection is the same as DiffArrayList, but takes the elements of another collection
+// like ArrayList or LinkedList, in the iterating order of `other`.
+func DiffArrayListCollection[T comparable](array *ArrayList[T], other Collection[T]) *ArrayList[T] {
+	return DiffArrayList(array, other.Slice())
+}
+
+// IntersectArrayList returns a new array with the distinct elements of `array` also contained in `values`,
+// in the order of their first occurrence in `array`.
+// It uses a hash set internally, so it costs O(n+m).
+func IntersectArrayList[T comparable](array *ArrayList[T], values []T) *ArrayList[T] {
+	include := make(map[T]bool, len(values))
+	for _, v := range values {
+		include[v] = true
+	}
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	newArray := make([]T, 0)
+	for _, v := range array.array {
+		if include[v] {
+			// Marks it false to add it only once.
+			include[v] = false
+			newArray = append(newArray, v)
+		}
+	}
+	return NewArrayListFrom[T](newArray, array.mu.IsSafe())
+}
+
+// IntersectArrayListCollection is the same as IntersectArrayList, but takes the elements of another
+// collection like ArrayList or LinkedList.
+func IntersectArrayListCollection[T comparable](array *ArrayList[T], other Collection[T]) *ArrayList[T] {
+	return IntersectArrayList(array, other.Slice())
+}
+
+// UnionArrayList returns a new array with the distinct elements of `array` followed by the distinct elements
+// of `values` not contained in `array`, in the order of their first occurrence.
+// It uses a hash set internally, so it costs O(n+m).
+func UnionArrayList[T comparable](array *ArrayList[T], values []T) *ArrayList[T] {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	var (
+		seen     = make(map[T]struct{}, len(array.array)+len(values))
+		newArray = make([]T, 0, len(array.array)+len(values))
+	)
+	for _, items := range [][]T{array.array, values} {
+		for _, v := range items {
+			if _, ok := seen[v]; !ok {
+				seen[v] = struct{}{}
+				newArray = append(newArray, v)
+			}
+		}
+	}
+	return NewArrayListFrom[T](newArray, array.mu.IsSafe())
+}
+
+// UnionArrayListCollection is the same as UnionArrayList, but takes the elements of another collection
+// like ArrayList or LinkedList, in the iterating order of `other`.
+func UnionArrayListCollection[T comparable](array *ArrayList[T], other Collection[T]) *ArrayList[T] {
+	return UnionArrayList(array, other.Slice())
+}
+
+// GroupArrayListBy buckets the elements of `array` by the key computed by `keyFn` in one pass,
+// and returns a map from each key to a new array of the elements with that key in their original order.
+// The returned map and arrays are concurrent-safe if `array` is concurrent-safe.
+func GroupArrayListBy[T any, K comparable](array *ArrayList[T], keyFn func(value T) K) *HashMap[K, *ArrayList[T]] {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	var (
+		safe   = array.mu.IsSafe()
+		groups = make(map[K]*ArrayList[T])
+	)
+	for _, v := range array.array {
+		key := keyFn(v)
+		group, ok := groups[key]
+		if !ok {
+			group = NewArrayList[T](safe)
+			groups[key] = group
+		}
+		group.array = append(group.array, v)
+	}
+	return NewHashMapFrom[K, *ArrayList[T]](groups, safe)
+}
+
+// UniqueArrayListBy removes the elements of `array` having the same key computed by `keyFn` as any previous element,
+// keeping the first-seen ones in their order, and returns `array`.
+// It uses a hash set of keys internally, so it costs O(n).
+func UniqueArrayListBy[T any, K comparable](array *ArrayList[T], keyFn func(value T) K) *ArrayList[T] {
+	array.mu.Lock()
+	defer array.unlockAndNotify()
+	var (
+		seen        = make(map[K]struct{}, len(array.array))
+		uniqueArray = array.array[:0]
+	)
+	for _, v := range array.array {
+		key := keyFn(v)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		uniqueArray = append(uniqueArray, v)
+	}
+	clear(array.array[len(uniqueArray):])
+	array.array = uniqueArray
+	array.notifyResetWithoutLock()
+	return array
+}
+
+// Compact replaces consecutive runs of equal elements with a single copy, like slices.Compact.
+// Elements are compared with equal.Equals.
+func (a *ArrayList[T]) Compact() List[T] {
+	return a.CompactFunc(func(v1, v2 T) bool {
+		return equal.Equals(v1, v2)
+	})
+}
+
+// CompactFunc replaces consecutive runs of elements for which `eq` returns true with the first one,
+// like slices.CompactFunc.
+func (a *ArrayList[T]) CompactFunc(eq func(v1, v2 T) bool) List[T] {
+	a.mu.Lock()
+	defer a.unlockAndNotify()
+	a.array = slices.CompactFunc(a.array, eq)
+	a.notifyResetWithoutLock()
+	return a
+}
+
+// BinarySearchFunc searches for `target` in the array sorted in the order of `cmp`, like slices.BinarySearchFunc.
+// It returns the position where `target` is found, or the position where it would appear in the order,
+// and a bool value indicating whether it is found.
+func (a *ArrayList[T]) BinarySearchFunc(target T, cmp func(v1, v2 T) int) (index int, found bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return slices.BinarySearchFunc(a.array, target, cmp)
+}
+
+// Grow increases the capacity of the array, if necessary, to guarantee space for another `n` elements,
+// like slices.Grow. It panics if `n` is negative.
+func (a *ArrayList[T]) Grow(n int) List[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.array = slices.Grow(a.array, n)
+	return a
+}
+
+// Cap returns the capacity of the underlying slice of array.
+func (a *ArrayList[T]) Cap() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return cap(a.array)
+}
+
+// Reserve increases the capacity of the array to at least `n` elements in total, if necessary,
+// so that the array grows without reallocation until its length exceeds `n`.
+func (a *ArrayList[T]) Reserve(n int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if n > cap(a.array) {
+		array := make([]T, len(a.array), n)
+		copy(array, a.array)
+		a.array = array
+	}
+}
+
+// ShrinkToFit reallocates the underlying slice of array to exactly its length, so that the memory of the old underlying slice can be reclaimed, e.g. after popping lots of elements.
+// Unlike Clip, it also releases the memory before the first element left by PopLeft and PopLefts.
+func (a *ArrayList[T]) ShrinkToFit() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// It always reallocates, as the capacity of the slice left by PopLefts is equal to its length,
+	// while the elements popped before it are still held by the underlying array.
+	array := make([]T, len(a.array))
+	copy(array, a.array)
+	a.array = array
+}
+
+// Clip removes unused capacity from the array, like slices.Clip.
+func (a *ArrayList[T]) Clip() List[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.array = slices.Clip(a.array)
+	return a
+}
+
 // IsEmpty checks whether the array is empty.
 func (a *ArrayList[T]) IsEmpty() bool {
 	return a.Len() == 0
@@ -918,5 +1989,5 @@ func (a *ArrayList[T]) DeepCopy() Collection[T] {
 	for i, v := range a.array {
 		newSlice[i] = deepcopy.Copy(v).(T)
 	}
-	return NewArrayListFrom[T](newSlice, a.mu.IsSafe())
+	return NewArrayListSorted[T](newSlice, a.sortedBy, a.mu.IsSafe())
 }
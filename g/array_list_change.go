@@ -0,0 +1,134 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// ChangeOp is the kind of mutation of an ArrayList notified to the observers registered by OnChange.
+type ChangeOp int
+
+const (
+	// ChangeOpInsert notifies that `new` is inserted at `index`, and the elements from `index` are moved right.
+	ChangeOpInsert ChangeOp = iota + 1
+
+	// ChangeOpRemove notifies that `old` is removed from `index`, and the elements after it are moved left.
+	ChangeOpRemove
+
+	// ChangeOpSet notifies that the element at `index` is replaced from `old` to `new`.
+	ChangeOpSet
+
+	// ChangeOpReset notifies that the elements may be changed or reordered arbitrarily, e.g. by Sort or Clear,
+	// and the observers should read the whole array again. The `index` is -1 and `old`, `new` are empty.
+	ChangeOpReset
+)
+
+// String returns the name of the operation.
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeOpInsert:
+		return "insert"
+	case ChangeOpRemove:
+		return "remove"
+	case ChangeOpSet:
+		return "set"
+	case ChangeOpReset:
+		return "reset"
+	default:
+		return "unknown"
+	}
+}
+
+// OnChange registers observer `f`, which is called after each mutation of the array.
+// Mutations of multiple elements are notified element by element, in the order that replaying them
+// one by one on a copy of the array gives the same result.
+//
+// The observers are called after the array is unlocked, so they can call methods of the array,
+// but the array may have been changed again when they are called. The changes are notified by one
+// goroutine at a time in the order they are made, so a mutation may return before its changes are
+// notified if another goroutine is notifying, including the mutations made by the observers.
+// Note that the observers are not copied by Clone or DeepCopy.
+func (a *ArrayList[T]) OnChange(f func(op ChangeOp, index int, old, new T)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.observers = append(a.observers, f)
+}
+
+// arrayListChange is a change of ArrayList to be notified to the observers.
+type arrayListChange[T any] struct {
+	op       ChangeOp
+	index    int
+	old, new T
+}
+
+// notifyWithoutLock queues the change, which is notified to the observers by unlockAndNotify.
+func (a *ArrayList[T]) notifyWithoutLock(op ChangeOp, index int, old, new T) {
+	if len(a.observers) == 0 {
+		return
+	}
+	a.changes = append(a.changes, arrayListChange[T]{op: op, index: index, old: old, new: new})
+}
+
+// unlockAndNotify unlocks the array, and then calls the observers with the queued changes.
+// It must be used instead of Unlock by the mutations, which may queue changes.
+// If another goroutine is notifying, the changes are left to it, so that they are notified in order.
+func (a *ArrayList[T]) unlockAndNotify() {
+	if len(a.changes) == 0 || a.notifying {
+		a.mu.Unlock()
+		return
+	}
+	a.notifying = true
+	done := false
+	defer func() {
+		// The observer panics, the changes not yet notified are discarded.
+		if !done {
+			a.mu.Lock()
+			a.changes = nil
+			a.notifying = false
+			a.mu.Unlock()
+		}
+	}()
+	for len(a.changes) > 0 {
+		changes, observers := a.changes, a.observers
+		a.changes = nil
+		a.mu.Unlock()
+		for _, c := range changes {
+			for _, f := range observers {
+				f(c.op, c.index, c.old, c.new)
+			}
+		}
+		a.mu.Lock()
+	}
+	a.notifying = false
+	done = true
+	a.mu.Unlock()
+}
+
+// notifyInsertWithoutLock notifies the insertion of `n` elements starting at `index`.
+func (a *ArrayList[T]) notifyInsertWithoutLock(index, n int) {
+	if len(a.observers) == 0 {
+		return
+	}
+	var empty T
+	for i := index; i < index+n; i++ {
+		a.notifyWithoutLock(ChangeOpInsert, i, empty, a.array[i])
+	}
+}
+
+// notifyRemoveWithoutLock notifies the removal of `removed` elements, which were consecutive starting at `index`.
+func (a *ArrayList[T]) notifyRemoveWithoutLock(index int, removed []T) {
+	if len(a.observers) == 0 {
+		return
+	}
+	var empty T
+	for _, v := range removed {
+		a.notifyWithoutLock(ChangeOpRemove, index, v, empty)
+	}
+}
+
+// notifyResetWithoutLock notifies that the elements may be changed arbitrarily.
+func (a *ArrayList[T]) notifyResetWithoutLock() {
+	var empty T
+	a.notifyWithoutLock(ChangeOpReset, -1, empty, empty)
+}
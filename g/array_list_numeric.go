@@ -0,0 +1,73 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// Number is a constraint that permits any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumArrayList returns the sum of the elements of `array` in type T, without any type conversion
+// like ArrayList.Sum. Note that the sum may overflow for integer types.
+func SumArrayList[T Number](array *ArrayList[T]) (sum T) {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	for _, v := range array.array {
+		sum += v
+	}
+	return
+}
+
+// AverageArrayList returns the arithmetic mean of the elements of `array`, or 0 if `array` is empty.
+func AverageArrayList[T Number](array *ArrayList[T]) float64 {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	if len(array.array) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range array.array {
+		sum += float64(v)
+	}
+	return sum / float64(len(array.array))
+}
+
+// MinArrayList returns the numerically minimum element of `array`, and false if `array` is empty.
+// All the elements are checked even if `array` is declared sorted, as the comparator may be in other order.
+func MinArrayList[T Number](array *ArrayList[T]) (min T, found bool) {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	if len(array.array) == 0 {
+		return
+	}
+	min = array.array[0]
+	for _, v := range array.array[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min, true
+}
+
+// MaxArrayList returns the numerically maximum element of `array`, and false if `array` is empty.
+// All the elements are checked even if `array` is declared sorted, as the comparator may be in other order.
+func MaxArrayList[T Number](array *ArrayList[T]) (max T, found bool) {
+	array.mu.RLock()
+	defer array.mu.RUnlock()
+	if len(array.array) == 0 {
+		return
+	}
+	max = array.array[0]
+	for _, v := range array.array[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max, true
+}
@@ -26,3 +26,29 @@ func Benchmark_AnyArray_Add(b *testing.B) {
 		anyArray.Add(i)
 	}
 }
+
+func benchmarkArrayAppendWithCapPolicy(b *testing.B, policy g.CapPolicy) {
+	for i := 0; i < b.N; i++ {
+		array := g.NewArrayList[int]()
+		array.SetCapPolicy(policy)
+		for j := 0; j < 1000; j++ {
+			array.Add(j)
+		}
+	}
+}
+
+func Benchmark_AnyArray_Append_CapPolicyDefault(b *testing.B) {
+	benchmarkArrayAppendWithCapPolicy(b, g.CapPolicyDefault)
+}
+
+func Benchmark_AnyArray_Append_CapPolicyExact(b *testing.B) {
+	benchmarkArrayAppendWithCapPolicy(b, g.CapPolicyExact)
+}
+
+func Benchmark_AnyArray_Append_CapPolicyCompact(b *testing.B) {
+	benchmarkArrayAppendWithCapPolicy(b, g.CapPolicyCompact)
+}
+
+func Benchmark_AnyArray_Append_CapPolicyAggressive(b *testing.B) {
+	benchmarkArrayAppendWithCapPolicy(b, g.CapPolicyAggressive)
+}
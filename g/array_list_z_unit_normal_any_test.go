@@ -9,10 +9,19 @@
 package g_test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/gtype"
+	"github.com/wesleywu/gcontainer/utils/comparators"
 	"github.com/wesleywu/gcontainer/utils/empty"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 
@@ -362,6 +371,25 @@ func TestArray_Chunk(t *testing.T) {
 	})
 }
 
+func TestArray_Flatten(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom[int]([]int{1, 2, 3, 4, 5})
+		t.Assert(g.FlattenArrayList(array.Chunk(2)).Slice(), array.Slice())
+		t.Assert(g.FlattenArrayList[int](nil).Slice(), []int{})
+	})
+}
+
+func TestArray_Interleave(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		a1 := g.NewArrayListFrom[string]([]string{"1", "2", "3"})
+		a2 := g.NewArrayListFrom[string]([]string{"a", "b"})
+		t.Assert(a1.Interleave(a2).Slice(), []string{"1", "a", "2", "b", "3"})
+		t.Assert(a2.Interleave(a1).Slice(), []string{"a", "1", "b", "2", "3"})
+		t.Assert(a1.Interleave(g.NewArrayList[string]()).Slice(), a1.Slice())
+		t.Assert(a1.Slice(), []string{"1", "2", "3"})
+	})
+}
+
 func TestArray_Pad(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		a1 := []int{0}
@@ -458,6 +486,19 @@ func TestArray_Join(t *testing.T) {
 	})
 }
 
+func TestArray_JoinFunc(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array1 := g.NewArrayListFrom([]string{"a", "b'c"})
+		quote := func(v string) string { return "'" + strings.ReplaceAll(v, "'", "''") + "'" }
+		t.Assert(array1.JoinFunc(",", quote), `'a','b''c'`)
+		var list g.ExtendedList[string] = array1
+		t.Assert(list.JoinFunc("|", quote), `'a'|'b''c'`)
+		t.Assert(g.NewArrayList[string]().JoinFunc(",", quote), "")
+		t.Assert(array1.Freeze().JoinFunc(",", quote), `'a','b''c'`)
+		t.Assert(g.NewCopyOnWriteArrayListFrom(array1.Slice()).JoinFunc(",", quote), `'a','b''c'`)
+	})
+}
+
 func TestArray_String(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		a1 := []int{0, 1, 2, 3, 4, 5, 6}
@@ -716,6 +757,48 @@ func TestArray_Iterator(t *testing.T) {
 	})
 }
 
+func TestArray_ForEachRange(t *testing.T) {
+	slice := []string{"a", "b", "c", "d", "e"}
+	array := g.NewArrayListFrom[string](slice, true)
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			indexes []int
+			values  []string
+		)
+		array.ForEachRange(1, 4, func(k int, v string) bool {
+			indexes = append(indexes, k)
+			values = append(values, v)
+			return true
+		})
+		t.Assert(indexes, []int{1, 2, 3})
+		t.Assert(values, []string{"b", "c", "d"})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var values []string
+		array.ForEachRange(-1, 100, func(k int, v string) bool {
+			values = append(values, v)
+			return true
+		})
+		t.Assert(values, slice)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		count := 0
+		array.ForEachRange(3, 1, func(k int, v string) bool {
+			count++
+			return true
+		})
+		t.Assert(count, 0)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		count := 0
+		array.ForEachRange(0, 5, func(k int, v string) bool {
+			count++
+			return k < 1
+		})
+		t.Assert(count, 2)
+	})
+}
+
 func TestArray_RemoveValue(t *testing.T) {
 	slice := []string{"a", "b", "d", "c"}
 	array := g.NewArrayListFrom[string](slice)
@@ -888,3 +971,782 @@ func TestArray_Walk(t *testing.T) {
 		}), []string{"key-1", "key-2"})
 	})
 }
+
+func TestArray_Map(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3})
+		t.Assert(array.Map(func(value int) int {
+			return value * 2
+		}), []int{2, 4, 6})
+		t.Assert(array, []int{1, 2, 3})
+
+		strArray := g.MapArrayList(array, func(value int) string {
+			return "key-" + gconv.String(value)
+		})
+		t.Assert(strArray.Slice(), []string{"key-1", "key-2", "key-3"})
+
+		t.Assert(array.FlatMap(func(value int) []int {
+			return []int{value, value * 10}
+		}), []int{1, 10, 2, 20, 3, 30})
+		t.Assert(g.NewArrayList[int]().Map(func(value int) int { return value }).Len(), 0)
+	})
+}
+
+func TestArray_Reduce(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3, 4})
+		t.Assert(array.Reduce(func(acc, value int) int {
+			return acc * value
+		}), 24)
+		t.Assert(g.NewArrayListFrom([]int{5}).Reduce(func(acc, value int) int {
+			return acc + value
+		}), 5)
+		t.Assert(g.NewArrayList[int]().Reduce(func(acc, value int) int {
+			return acc + value
+		}), 0)
+	})
+}
+
+func TestArray_SetSorted(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 3, 3, 5, 7, 9})
+		t.Assert(array.IsSorted(), false)
+		array.SetSorted(comparators.ComparatorInt)
+		t.Assert(array.IsSorted(), true)
+		t.Assert(array.Search(1), 0)
+		t.Assert(array.Search(3), 1)
+		t.Assert(array.Search(9), 5)
+		t.Assert(array.Search(4), -1)
+		t.Assert(array.Search(10), -1)
+		t.Assert(array.Contains(7), true)
+		t.Assert(array.Contains(0), false)
+		t.Assert(array.RemoveValue(3), true)
+		t.Assert(array.Slice(), []int{1, 3, 5, 7, 9})
+		t.Assert(array.Clone().(*g.ArrayList[int]).IsSorted(), true)
+		t.Assert(array.DeepCopy().(*g.ArrayList[int]).IsSorted(), true)
+
+		// Add keeps appending.
+		array.Add(11)
+		t.Assert(array.Search(11), 5)
+
+		array.SetSorted(nil)
+		t.Assert(array.IsSorted(), false)
+		t.Assert(array.Search(5), 2)
+	})
+	// The declaration is cleared by the methods breaking the order.
+	gtest.C(t, func(t *gtest.T) {
+		values := make([]int, 100)
+		for i := range values {
+			values[i] = i
+		}
+		array := g.NewArrayListSorted(values, comparators.ComparatorInt)
+		array.SetRandSource(rand.New(rand.NewSource(1)))
+		array.Shuffle()
+		t.Assert(array.IsSorted(), false)
+		for i := range values {
+			t.Assert(array.Contains(i), true)
+		}
+		t.Assert(array.Contains(100), false)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		newSorted := func() *g.ArrayList[int] {
+			return g.NewArrayListSorted([]int{1, 2, 3}, comparators.ComparatorInt)
+		}
+		for _, f := range []func(array *g.ArrayList[int]){
+			func(array *g.ArrayList[int]) { array.Reverse() },
+			func(array *g.ArrayList[int]) { array.Sort(func(v1, v2 int) bool { return v1 > v2 }) },
+			func(array *g.ArrayList[int]) { array.PushLeft(9) },
+			func(array *g.ArrayList[int]) { _ = array.InsertBefore(1, 9) },
+			func(array *g.ArrayList[int]) { _ = array.InsertAllAfter(0, []int{9}) },
+			func(array *g.ArrayList[int]) { _ = array.Set(0, 9) },
+			func(array *g.ArrayList[int]) { _ = array.Fill(0, 1, 9) },
+			func(array *g.ArrayList[int]) { array.Walk(func(value int) int { return -value }) },
+		} {
+			array := newSorted()
+			f(array)
+			t.Assert(array.IsSorted(), false)
+		}
+		// Removing and appending keep the declaration.
+		array := newSorted()
+		array.RemoveAt(0)
+		array.Add(4)
+		t.Assert(array.IsSorted(), true)
+		t.Assert(array.Search(4), 2)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		// Only the sort key is compared.
+		type item struct {
+			key  int
+			tags []string
+		}
+		array := g.NewArrayListSorted([]item{{1, nil}, {2, []string{"a"}}, {4, nil}}, func(v1, v2 item) int {
+			return v1.key - v2.key
+		})
+		t.Assert(array.IsSorted(), true)
+		t.Assert(array.Search(item{key: 2}), 1)
+		t.Assert(array.Contains(item{key: 3}), false)
+	})
+}
+
+func TestArray_Numeric(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]float64{1.5, -2, 4, 0.5})
+		t.Assert(g.SumArrayList(array), 4.0)
+		t.Assert(g.AverageArrayList(array), 1.0)
+		min, found := g.MinArrayList(array)
+		t.Assert(min, -2)
+		t.Assert(found, true)
+		max, found := g.MaxArrayList(array)
+		t.Assert(max, 4)
+		t.Assert(found, true)
+
+		type myInt int8
+		ints := g.NewArrayListFrom([]myInt{3, 1, 2})
+		t.Assert(g.SumArrayList(ints), myInt(6))
+		t.Assert(g.AverageArrayList(ints), 2.0)
+
+		empty := g.NewArrayList[uint]()
+		t.Assert(g.SumArrayList(empty), 0)
+		t.Assert(g.AverageArrayList(empty), 0)
+		_, found = g.MinArrayList(empty)
+		t.Assert(found, false)
+		_, found = g.MaxArrayList(empty)
+		t.Assert(found, false)
+	})
+	// Declared sorted in descending order.
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListSorted([]int{9, 5, 3, 1}, func(v1, v2 int) int {
+			return v2 - v1
+		})
+		min, _ := g.MinArrayList(array)
+		max, _ := g.MaxArrayList(array)
+		t.Assert(min, 1)
+		t.Assert(max, 9)
+	})
+	// Declared sorted by a comparator not in numeric order.
+	gtest.C(t, func(t *gtest.T) {
+		abs := func(v int) int {
+			if v < 0 {
+				return -v
+			}
+			return v
+		}
+		array := g.NewArrayListSorted([]int{1, -2, 3, -4}, func(v1, v2 int) int {
+			return abs(v1) - abs(v2)
+		})
+		min, _ := g.MinArrayList(array)
+		max, _ := g.MaxArrayList(array)
+		t.Assert(min, -4)
+		t.Assert(max, 3)
+	})
+}
+
+func TestArray_SlicesInterop(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		slice := []int{1, 1, 2, 3, 3, 3, 4}
+		array := g.WrapArrayList(slice)
+		array.Set(0, 0)
+		t.Assert(slice[0], 0)
+
+		t.Assert(array.Compact(), []int{0, 1, 2, 3, 4})
+		index, found := array.BinarySearchFunc(3, comparators.ComparatorInt)
+		t.Assert(index, 3)
+		t.Assert(found, true)
+		index, found = array.BinarySearchFunc(5, comparators.ComparatorInt)
+		t.Assert(index, 5)
+		t.Assert(found, false)
+
+		array.Grow(100)
+		array.LockFunc(func(array []int) {
+			t.AssertGE(cap(array), 105)
+		})
+		array.Clip()
+		array.LockFunc(func(array []int) {
+			t.Assert(cap(array), 5)
+		})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"a", "A", "b", "B", "b"})
+		t.Assert(array.CompactFunc(strings.EqualFold), []string{"a", "b"})
+	})
+}
+
+func TestArray_InsertAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3})
+		t.AssertNil(array.InsertAllBefore(0, []int{-1, 0}))
+		t.Assert(array.Slice(), []int{-1, 0, 1, 2, 3})
+		t.AssertNil(array.InsertAllAfter(4, []int{4, 5}))
+		t.Assert(array.Slice(), []int{-1, 0, 1, 2, 3, 4, 5})
+		t.AssertNil(array.InsertAllBefore(3, []int{10, 11}))
+		t.Assert(array.Slice(), []int{-1, 0, 1, 10, 11, 2, 3, 4, 5})
+		t.AssertNil(array.InsertAllAfter(0, nil))
+		t.Assert(array.Len(), 9)
+
+		t.AssertNE(array.InsertAllBefore(9, []int{1}), nil)
+		t.AssertNE(array.InsertAllAfter(-1, []int{1}), nil)
+	})
+}
+
+func TestArray_RemoveRange(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{0, 1, 2, 3, 4, 5, 6})
+		t.Assert(array.RemoveRange(2, 4), []int{2, 3})
+		t.Assert(array.Slice(), []int{0, 1, 4, 5, 6})
+		t.Assert(array.RemoveRange(-1, 1), []int{0})
+		t.Assert(array.RemoveRange(3, 10), []int{6})
+		t.Assert(array.Slice(), []int{1, 4, 5})
+		t.Assert(array.RemoveRange(2, 2), nil)
+		t.Assert(array.RemoveRange(3, 1), nil)
+		t.Assert(array.Slice(), []int{1, 4, 5})
+	})
+}
+
+func TestArray_ParallelForEach(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListRange(1, 1000, 1, true)
+		sum := gtype.NewInt64()
+		err := array.ParallelForEach(4, func(index int, value int) error {
+			sum.Add(int64(value))
+			return nil
+		})
+		t.AssertNil(err)
+		t.Assert(sum.Val(), 500500)
+
+		err = array.ParallelForEach(0, func(index int, value int) error {
+			if value%500 == 0 {
+				return fmt.Errorf("invalid value %d at %d", value, index)
+			}
+			return nil
+		})
+		t.AssertNE(err, nil)
+		t.Assert(strings.Contains(err.Error(), "invalid value 500 at 499"), true)
+		t.Assert(strings.Contains(err.Error(), "invalid value 1000 at 999"), true)
+
+		t.AssertNil(g.NewArrayList[int]().ParallelForEach(4, func(index int, value int) error {
+			return errors.New("unexpected")
+		}))
+	})
+}
+
+func TestArray_ParallelWalk(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListRange(0, 99, 1)
+		array.ParallelWalk(8, func(value int) int {
+			return value * 2
+		})
+		t.Assert(array.Len(), 100)
+		array.ForEachAsc(func(index int, value int) bool {
+			t.Assert(value, index*2)
+			return true
+		})
+		t.Assert(g.NewArrayListFrom([]int{1}).ParallelWalk(0, func(value int) int {
+			return value + 1
+		}), []int{2})
+	})
+}
+
+func TestArray_Capacity(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayList[int]()
+		t.Assert(array.Cap(), 0)
+		array.Reserve(100)
+		t.Assert(array.Cap(), 100)
+		for i := 0; i < 100; i++ {
+			array.Add(i)
+		}
+		t.Assert(array.Cap(), 100)
+		array.Reserve(10)
+		t.Assert(array.Cap(), 100)
+
+		array.PopLefts(90)
+		t.Assert(array.Len(), 10)
+		array.ShrinkToFit()
+		t.Assert(array.Cap(), 10)
+		t.Assert(array.Slice(), []int{90, 91, 92, 93, 94, 95, 96, 97, 98, 99})
+
+		array.Clear()
+		array.ShrinkToFit()
+		t.Assert(array.Cap(), 0)
+	})
+	// The array left by PopLefts is reallocated, even if its capacity is equal to its length.
+	gtest.C(t, func(t *gtest.T) {
+		slice := make([]int, 1000)
+		for i := range slice {
+			slice[i] = i + 1
+		}
+		array := g.NewArrayListFrom(slice)
+		array.PopLefts(990)
+		before := array.Slice()
+		t.Assert(array.Cap(), array.Len())
+		array.ShrinkToFit()
+		after := array.Slice()
+		t.Assert(after, []int{991, 992, 993, 994, 995, 996, 997, 998, 999, 1000})
+		t.Assert(&before[0] == &after[0], false)
+	})
+}
+
+func TestArray_Binary(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int64{math.MaxInt64, -1, 0}, true)
+		b, err := array.MarshalBinary()
+		t.AssertNil(err)
+		array2 := g.NewArrayList[int64]()
+		t.AssertNil(array2.UnmarshalBinary(b))
+		t.Assert(array2.Slice(), []int64{math.MaxInt64, -1, 0})
+
+		empty, err := g.NewArrayList[string]().MarshalBinary()
+		t.AssertNil(err)
+		strArray := g.NewArrayListFrom([]string{"a"})
+		t.AssertNil(strArray.UnmarshalBinary(empty))
+		t.Assert(strArray.Len(), 0)
+		t.AssertNE(strArray.UnmarshalBinary([]byte("invalid")), nil)
+	})
+	// Gob streams.
+	gtest.C(t, func(t *gtest.T) {
+		type Payload struct {
+			Name   string
+			Values *g.ArrayList[float64]
+			Any    interface{}
+		}
+		g.RegisterArrayListGob[string]()
+		var (
+			buffer = bytes.NewBuffer(nil)
+			input  = Payload{
+				Name:   "p",
+				Values: g.NewArrayListFrom([]float64{0.1, math.Pi}),
+				Any:    g.NewArrayListFrom([]string{"x", "y"}),
+			}
+			output Payload
+		)
+		t.AssertNil(gob.NewEncoder(buffer).Encode(input))
+		t.AssertNil(gob.NewDecoder(buffer).Decode(&output))
+		t.Assert(output.Name, "p")
+		t.Assert(output.Values.Slice(), []float64{0.1, math.Pi})
+		t.Assert(output.Any.(*g.ArrayList[string]).Slice(), []string{"x", "y"})
+	})
+}
+
+func TestArray_Page(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var array g.ExtendedList[int] = g.NewArrayListRange(1, 7, 1, true)
+		t.Assert(array.PageCount(3), 3)
+		t.Assert(array.PageCount(7), 1)
+		t.Assert(array.PageCount(0), 0)
+		t.Assert(array.Page(1, 3), []int{1, 2, 3})
+		t.Assert(array.Page(2, 3), []int{4, 5, 6})
+		t.Assert(array.Page(3, 3), []int{7})
+		t.Assert(array.Page(4, 3), nil)
+		t.Assert(array.Page(0, 3), nil)
+		t.Assert(array.Page(1, 0), nil)
+		t.Assert(array.Page(1, 10), []int{1, 2, 3, 4, 5, 6, 7})
+		// The huge sizes don't overflow.
+		t.Assert(array.PageCount(math.MaxInt), 1)
+		t.Assert(array.Page(1, math.MaxInt), []int{1, 2, 3, 4, 5, 6, 7})
+		t.Assert(array.Page(2, math.MaxInt), nil)
+
+		empty := g.NewArrayList[int]()
+		t.Assert(empty.PageCount(10), 0)
+		t.Assert(empty.Page(1, 10), nil)
+	})
+}
+
+func TestArray_SetOperations(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{5, 1, 3, 1, 4, 2})
+		other := g.NewArrayListFrom([]int{4, 6, 1, 6})
+		t.Assert(g.DiffArrayList(array, other.Slice()), []int{5, 3, 2})
+		t.Assert(g.IntersectArrayList(array, other.Slice()), []int{1, 4})
+		t.Assert(g.UnionArrayList(array, other.Slice()), []int{5, 1, 3, 4, 2, 6})
+		t.Assert(g.UnionArrayList(g.NewArrayList[int](), []int{2, 2, 1}), []int{2, 1})
+		t.Assert(g.DiffArrayList(array, nil), []int{5, 1, 3, 4, 2})
+		t.Assert(g.IntersectArrayList(array, nil).Len(), 0)
+		// The source array is not changed.
+		t.Assert(array, []int{5, 1, 3, 1, 4, 2})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{5, 1, 3, 1, 4, 2}, true)
+		list := g.NewLinkedListFrom([]int{4, 6, 1, 6})
+		t.Assert(g.DiffArrayListCollection(array, list), []int{5, 3, 2})
+		t.Assert(g.IntersectArrayListCollection(array, list), []int{1, 4})
+		t.Assert(g.UnionArrayListCollection(array, list), []int{5, 1, 3, 4, 2, 6})
+		// With itself.
+		t.Assert(g.DiffArrayListCollection(array, array).Len(), 0)
+		t.Assert(g.UnionArrayListCollection(array, array), []int{5, 1, 3, 4, 2})
+	})
+}
+
+func TestArray_GroupBy(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"apple", "avocado", "banana", "blueberry", "cherry"})
+		groups := g.GroupArrayListBy(array, func(value string) byte {
+			return value[0]
+		})
+		t.Assert(groups.Size(), 3)
+		t.Assert(groups.Get('a'), []string{"apple", "avocado"})
+		t.Assert(groups.Get('b'), []string{"banana", "blueberry"})
+		t.Assert(groups.Get('c'), []string{"cherry"})
+
+		byLength := g.GroupArrayListBy(g.NewArrayList[string](), func(value string) int {
+			return len(value)
+		})
+		t.Assert(byLength.Size(), 0)
+	})
+}
+
+func TestArray_UniqueFunc(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"a", "B", "A", "b", "c"})
+		t.Assert(array.UniqueFunc(strings.EqualFold), []string{"a", "B", "c"})
+		t.Assert(array.Len(), 3)
+		t.Assert(g.NewArrayList[string]().UniqueFunc(strings.EqualFold).Size(), 0)
+	})
+}
+
+func TestArray_UniqueBy(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		type user struct {
+			ID   int
+			Name string
+		}
+		array := g.NewArrayListFrom([]user{{1, "a"}, {2, "b"}, {1, "c"}, {3, "d"}, {2, "e"}})
+		g.UniqueArrayListBy(array, func(value user) int {
+			return value.ID
+		})
+		t.Assert(array.Slice(), []user{{1, "a"}, {2, "b"}, {3, "d"}})
+	})
+}
+
+func TestArray_Compare(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3})
+		t.Assert(array.Compare(g.NewArrayListFrom([]int{1, 2, 3}), comparators.ComparatorInt), 0)
+		t.Assert(array.Compare(g.NewArrayListFrom([]int{1, 3}), comparators.ComparatorInt), -1)
+		t.Assert(array.Compare(g.NewArrayListFrom([]int{1, 2, 2, 9}), comparators.ComparatorInt), 1)
+		t.Assert(array.Compare(g.NewArrayListFrom([]int{1, 2}), comparators.ComparatorInt), 1)
+		t.Assert(array.Compare(g.NewLinkedListFrom([]int{1, 2, 3, 0}), comparators.ComparatorInt), -1)
+		t.Assert(g.NewArrayList[int]().Compare(g.NewArrayList[int](), comparators.ComparatorInt), 0)
+	})
+}
+
+func TestArray_EqualsFunc(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"a", "B"})
+		t.Assert(array.EqualsFunc(g.NewArrayListFrom([]string{"A", "b"}), strings.EqualFold), true)
+		t.Assert(array.EqualsFunc(g.NewLinkedListFrom([]string{"a", "b"}), strings.EqualFold), true)
+		t.Assert(array.EqualsFunc(g.NewArrayListFrom([]string{"a", "c"}), strings.EqualFold), false)
+		t.Assert(array.EqualsFunc(g.NewArrayListFrom([]string{"a"}), strings.EqualFold), false)
+	})
+}
+
+func TestArray_RandsUnique(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListRange(0, 99, 1)
+		for i := 0; i < 10; i++ {
+			values := array.RandsUnique(50)
+			t.Assert(len(values), 50)
+			t.Assert(g.NewHashSetFrom(values).Size(), 50)
+		}
+		values := array.RandsUnique(200)
+		t.Assert(len(values), 100)
+		t.Assert(g.NewHashSetFrom(values).Size(), 100)
+		t.Assert(array.Len(), 100)
+		t.Assert(array.RandsUnique(0), nil)
+		t.Assert(g.NewArrayList[int]().RandsUnique(1), nil)
+	})
+}
+
+func TestArray_PopRandsUnique(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListRange(0, 99, 1)
+		popped := array.PopRands(30)
+		t.Assert(len(popped), 30)
+		t.Assert(g.NewHashSetFrom(popped).Size(), 30)
+		t.Assert(array.Len(), 70)
+		// The remaining items keep their order.
+		remaining := array.Slice()
+		for i := 1; i < len(remaining); i++ {
+			t.Assert(remaining[i-1] < remaining[i], true)
+		}
+		for _, v := range popped {
+			t.Assert(array.Contains(v), false)
+		}
+		t.Assert(len(array.PopRands(100)), 70)
+		t.Assert(array.Len(), 0)
+	})
+}
+
+func TestArray_RandWeighted(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"a", "b", "c"})
+		weights := map[string]int{"a": 0, "b": 1, "c": 3}
+		counts := make(map[string]int)
+		for i := 0; i < 1000; i++ {
+			v, found := array.RandWeighted(func(value string) int { return weights[value] })
+			t.Assert(found, true)
+			counts[v]++
+		}
+		t.Assert(counts["a"], 0)
+		t.AssertGE(counts["c"], counts["b"])
+		t.Assert(array.Len(), 3)
+
+		_, found := array.RandWeighted(func(value string) int { return 0 })
+		t.Assert(found, false)
+		_, found = g.NewArrayList[string]().RandWeighted(func(value string) int { return 1 })
+		t.Assert(found, false)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3, 4})
+		weight := func(value int) int { return value % 2 }
+		v1, found := array.PopRandWeighted(weight)
+		t.Assert(found, true)
+		v2, found := array.PopRandWeighted(weight)
+		t.Assert(found, true)
+		t.Assert(v1+v2, 4)
+		_, found = array.PopRandWeighted(weight)
+		t.Assert(found, false)
+		t.Assert(array.Slice(), []int{2, 4})
+	})
+}
+
+func TestArray_SortBy(t *testing.T) {
+	type task struct {
+		Name     string
+		Priority int
+		Time     int
+	}
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]task{
+			{"a", 2, 3}, {"b", 1, 2}, {"c", 2, 1}, {"d", 1, 2}, {"e", 1, 1},
+		})
+		byPriority := func(v1, v2 task) int { return v2.Priority - v1.Priority }
+		byTime := func(v1, v2 task) int { return v1.Time - v2.Time }
+		names := func() string {
+			var s []string
+			array.ForEach(func(v task) bool {
+				s = append(s, v.Name)
+				return true
+			})
+			return strings.Join(s, "")
+		}
+		array.SortBy(byPriority, byTime)
+		t.Assert(names(), "caebd")
+		array.SortBy(byTime)
+		t.Assert(names(), "cebda")
+		array.SortBy()
+		t.Assert(names(), "cebda")
+	})
+}
+
+func TestArray_OnChange(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array   = g.NewArrayListFrom([]int{1, 2, 3}, true)
+			replica = []int{1, 2, 3}
+			events  []string
+		)
+		// Replaying the events on a copy gives the same result.
+		array.OnChange(func(op g.ChangeOp, index int, old, new int) {
+			events = append(events, fmt.Sprintf("%s %d %d %d", op, index, old, new))
+			switch op {
+			case g.ChangeOpInsert:
+				replica = append(replica[:index], append([]int{new}, replica[index:]...)...)
+			case g.ChangeOpRemove:
+				replica = append(replica[:index], replica[index+1:]...)
+			case g.ChangeOpSet:
+				replica[index] = new
+			}
+		})
+		array.Add(4, 5)
+		t.AssertNil(array.Set(0, 10))
+		t.AssertNil(array.InsertBefore(1, 6))
+		array.PushLeft(7)
+		array.RemoveValue(2)
+		array.PopRight()
+		array.PopLefts(2)
+		array.Filter(func(index int, value int) bool { return value%2 == 0 })
+		t.Assert(events, []string{
+			"insert 3 0 4", "insert 4 0 5",
+			"set 0 1 10",
+			"insert 1 0 6",
+			"insert 0 0 7",
+			"remove 3 2 0",
+			"remove 5 5 0",
+			"remove 0 7 0", "remove 0 10 0",
+			"remove 0 6 0", "remove 1 4 0",
+		})
+		t.Assert(array.Slice(), []int{3})
+		t.Assert(replica, array.Slice())
+
+		events = nil
+		array.PopRands(1)
+		array.Clear()
+		t.Assert(events, []string{"remove 0 3 0"})
+		array.Add(1)
+		array.Sort(func(v1, v2 int) bool { return v1 < v2 })
+		t.Assert(events[len(events)-1], "reset -1 0 0")
+	})
+	// The filtering notifies each removed element after the array is unlocked.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array  = g.NewArrayListFrom([]any{1, nil, 0, 2, nil})
+			events []string
+		)
+		array.OnChange(func(op g.ChangeOp, index int, old, new any) {
+			events = append(events, fmt.Sprintf("%s %d %v %v", op, index, old, array.Slice()))
+		})
+		array.FilterNil()
+		array.FilterEmpty()
+		array.Filter(func(index int, value any) bool { return value == 2 })
+		t.Assert(events, []string{
+			"remove 1 <nil> [1 0 2]", "remove 3 <nil> [1 0 2]",
+			"remove 1 0 [1 2]",
+			"remove 1 2 [1]",
+		})
+	})
+	// The observers can call methods of the safe array, including the mutations.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			array   = g.NewArrayList[int](true)
+			lengths []int
+			done    = make(chan struct{})
+		)
+		array.OnChange(func(op g.ChangeOp, index int, old, new int) {
+			lengths = append(lengths, array.Len())
+			if op == g.ChangeOpInsert && new == 1 {
+				array.Add(2)
+			}
+		})
+		go func() {
+			array.Add(1)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("observer calling the array deadlocks")
+		}
+		t.Assert(array.Slice(), []int{1, 2})
+		t.Assert(lengths, []int{1, 2})
+	})
+}
+
+func TestArray_Resize(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3})
+		array.Resize(5, 0)
+		t.Assert(array.Slice(), []int{1, 2, 3, 0, 0})
+		capacity := array.Cap()
+		array.Resize(2, 9)
+		t.Assert(array.Slice(), []int{1, 2})
+		t.Assert(array.Cap(), capacity)
+		array.Resize(2, 9)
+		t.Assert(array.Slice(), []int{1, 2})
+		array.Resize(-1, 9)
+		t.Assert(array.Len(), 0)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"a", "b", "c"})
+		capacity := array.Cap()
+		array.Truncate(5)
+		t.Assert(array.Slice(), []string{"a", "b", "c"})
+		array.Truncate(1)
+		t.Assert(array.Slice(), []string{"a"})
+		t.Assert(array.Cap(), capacity)
+		array.Add("d")
+		t.Assert(array.Slice(), []string{"a", "d"})
+		array.Truncate(0)
+		t.Assert(array.IsEmpty(), true)
+	})
+}
+
+func TestArray_SetRandSource(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		newArray := func() *g.ArrayList[int] {
+			array := g.NewArrayListRange(1, 20, 1, true)
+			array.SetRandSource(rand.New(rand.NewSource(1)))
+			return array
+		}
+		a1, a2 := newArray(), newArray()
+		t.Assert(a1.Shuffle().Slice(), a2.Shuffle().Slice())
+		t.Assert(a1.Rands(5), a2.Rands(5))
+		t.Assert(a1.RandsUnique(5), a2.RandsUnique(5))
+		t.Assert(a1.MustGet(0), a2.MustGet(0))
+		v1, _ := a1.PopRand()
+		v2, _ := a2.PopRand()
+		t.Assert(v1, v2)
+		t.Assert(a1.PopRands(3), a2.PopRands(3))
+		t.Assert(a1.Slice(), a2.Slice())
+
+		a1.SetRandSource(nil)
+		t.Assert(a1.Shuffle().Size(), a2.Len())
+		_, found := g.NewArrayList[int]().PopRand()
+		t.Assert(found, false)
+	})
+}
+
+func TestArray_ChunkEach(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3, 4, 5})
+		var chunks [][]int
+		array.ChunkEach(2, func(chunk []int) bool {
+			chunks = append(chunks, append([]int(nil), chunk...))
+			return true
+		})
+		t.Assert(chunks, array.Chunk(2))
+
+		count := 0
+		array.ChunkEach(2, func(chunk []int) bool {
+			count++
+			return false
+		})
+		t.Assert(count, 1)
+
+		array.ChunkEach(0, func(chunk []int) bool {
+			t.Error("should not be called")
+			return true
+		})
+		g.NewArrayList[int]().ChunkEach(2, func(chunk []int) bool {
+			t.Error("should not be called")
+			return true
+		})
+	})
+}
+
+func TestArray_SetCapPolicy(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayList[int]()
+		t.Assert(array.CapPolicy(), g.CapPolicyDefault)
+		array.SetCapPolicy(g.CapPolicyExact)
+		t.Assert(array.CapPolicy(), g.CapPolicyExact)
+		for i := 0; i < 5; i++ {
+			array.Add(i)
+			t.Assert(cap(array.Slice()), i+1)
+		}
+		array.PushLeft(-1)
+		t.Assert(cap(array.Slice()), 6)
+		t.Assert(array.Slice(), []int{-1, 0, 1, 2, 3, 4})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListSize[int](0, 4)
+		array.SetCapPolicy(g.CapPolicy{GrowthFactor: 2, MaxOverAlloc: 2})
+		array.Add(1, 2, 3, 4)
+		t.Assert(cap(array.Slice()), 4)
+		array.Add(5)
+		t.Assert(cap(array.Slice()), 7)
+		t.AssertNil(array.InsertAfter(0, 10, 11, 12))
+		t.Assert(cap(array.Slice()), 10)
+		t.Assert(array.Slice(), []int{1, 10, 11, 12, 2, 3, 4, 5})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayList[int]()
+		array.SetCapPolicy(g.CapPolicyAggressive)
+		array.Add(1)
+		array.Add(2)
+		array.Add(3)
+		t.Assert(cap(array.Slice()), 4)
+		t.AssertNil(array.Fill(3, 3, 0))
+		t.Assert(cap(array.Slice()), 8)
+		t.Assert(array.Slice(), []int{1, 2, 3, 0, 0, 0})
+	})
+}
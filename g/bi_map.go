@@ -0,0 +1,427 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"errors"
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// BiMap is a bidirectional map, which maps keys to values and values back to keys,
+// so both the keys and the values are unique in it.
+//
+// Putting a value bound to another key moves the value to the new key with Put,
+// or fails with PutUnique. The view returned by Inverse shares the data and the lock with the map,
+// so that both directions always stay in sync under mutation.
+type BiMap[K comparable, V comparable] struct {
+	mu          *rwmutex.RWMutex // Shared with the inverse view.
+	forward     map[K]V
+	backward    map[V]K
+	inverse     *BiMap[V, K]
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// NewBiMap creates and returns an empty bidirectional map.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewBiMap[K comparable, V comparable](safe ...bool) *BiMap[K, V] {
+	m := &BiMap[K, V]{
+		mu:       rwmutex.New(safe...),
+		forward:  make(map[K]V),
+		backward: make(map[V]K),
+	}
+	m.inverse = &BiMap[V, K]{
+		mu:       m.mu,
+		forward:  m.backward,
+		backward: m.forward,
+		inverse:  m,
+	}
+	return m
+}
+
+// NewBiMapFrom creates and returns a bidirectional map from a copy of given map `data`.
+// Only one of the keys mapped to the same value in `data` is kept, which is undefined.
+func NewBiMapFrom[K comparable, V comparable](data map[K]V, safe ...bool) *BiMap[K, V] {
+	m := NewBiMap[K, V](safe...)
+	m.Puts(data)
+	return m
+}
+
+// Inverse returns the inverse view of the map, which maps the values to the keys.
+// The view shares the data and the lock with the map, so the changes of either are visible to the other.
+func (m *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return m.inverse
+}
+
+// Put sets key-value to the map. If `value` is bound to another key, the mapping of that key is removed,
+// and if `key` is bound to another value, that value is unbound, so that both stay unique.
+func (m *BiMap[K, V]) Put(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putWithoutLock(key, value)
+}
+
+// PutUnique sets key-value to the map like Put, but returns an error without changing the map
+// if `value` is bound to another key.
+func (m *BiMap[K, V]) PutUnique(key K, value V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if k, ok := m.backward[value]; ok && k != key {
+		return errors.New(fmt.Sprintf("value %v is already bound to key %v", value, k))
+	}
+	m.putWithoutLock(key, value)
+	return nil
+}
+
+// Puts batch sets key-values to the map like Put.
+func (m *BiMap[K, V]) Puts(data map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range data {
+		m.putWithoutLock(k, v)
+	}
+}
+
+// PutAll batch sets key-values to the map, which is alias of Puts.
+func (m *BiMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the map is locked.
+func (m *BiMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		m.putWithoutLock(entry.Key, entry.Value)
+	}
+}
+
+// putWithoutLock binds `key` and `value`, and unbinds their former counterparts.
+func (m *BiMap[K, V]) putWithoutLock(key K, value V) {
+	if v, ok := m.forward[key]; ok {
+		delete(m.backward, v)
+	}
+	if k, ok := m.backward[value]; ok {
+		delete(m.forward, k)
+	}
+	m.forward[key] = value
+	m.backward[value] = key
+}
+
+// Search searches the map with given `key`.
+// Second return parameter `found` is true if key was found, otherwise false.
+func (m *BiMap[K, V]) Search(key K) (value V, found bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, found = m.forward[key]
+	return
+}
+
+// Get returns the value by given `key`, or empty value of type V if the key is not found in the map.
+func (m *BiMap[K, V]) Get(key K) (value V) {
+	value, _ = m.Search(key)
+	return
+}
+
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *BiMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *BiMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
+// SearchByValue searches the key bound to `value`.
+// Second return parameter `found` is true if value was found, otherwise false.
+func (m *BiMap[K, V]) SearchByValue(value V) (key K, found bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	key, found = m.backward[value]
+	return
+}
+
+// GetByValue returns the key bound to `value`, or empty value of type K if the value is not found in the map.
+func (m *BiMap[K, V]) GetByValue(value V) (key K) {
+	key, _ = m.SearchByValue(value)
+	return
+}
+
+// GetOrPut returns the value by key,
+// or sets value with given `value` like Put if it does not exist and then returns this value.
+func (m *BiMap[K, V]) GetOrPut(key K, value V) V {
+	v, _ := m.getOrPutFunc(key, func() V { return value })
+	return v
+}
+
+// GetOrPutFunc returns the value by key,
+// or sets value with returned value of callback function `f` like Put if it does not exist
+// and then returns this value.
+// Note that `f` is called with the map locked.
+func (m *BiMap[K, V]) GetOrPutFunc(key K, f func() V) V {
+	v, _ := m.getOrPutFunc(key, f)
+	return v
+}
+
+// PutIfAbsent sets `value` to the map like Put if the `key` does not exist, and then returns true.
+// It returns false if `key` exists, and `value` would be ignored.
+func (m *BiMap[K, V]) PutIfAbsent(key K, value V) bool {
+	_, put := m.getOrPutFunc(key, func() V { return value })
+	return put
+}
+
+// PutIfAbsentFunc sets value with return value of callback function `f` like Put if the `key` does not
+// exist, and then returns true.
+// It returns false if `key` exists, and `f` would not be called.
+func (m *BiMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
+	_, put := m.getOrPutFunc(key, f)
+	return put
+}
+
+// getOrPutFunc returns the value by key,
+// or sets value with returned value of `f` if it does not exist, in which case `put` is true.
+func (m *BiMap[K, V]) getOrPutFunc(key K, f func() V) (value V, put bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.forward[key]; ok {
+		return v, false
+	}
+	value = f()
+	m.putWithoutLock(key, value)
+	return value, true
+}
+
+// Remove deletes value from map by given `key`, and return this deleted value.
+func (m *BiMap[K, V]) Remove(key K) (value V, removed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if value, removed = m.forward[key]; removed {
+		delete(m.forward, key)
+		delete(m.backward, value)
+	}
+	return
+}
+
+// RemoveByValue deletes the mapping of `value`, and return the key bound to it.
+func (m *BiMap[K, V]) RemoveByValue(value V) (key K, removed bool) {
+	return m.inverse.Remove(value)
+}
+
+// Removes batch deletes values of the map by keys.
+func (m *BiMap[K, V]) Removes(keys []K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if value, ok := m.forward[key]; ok {
+			delete(m.forward, key)
+			delete(m.backward, value)
+		}
+	}
+}
+
+// ForEach iterates the map readonly with custom callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *BiMap[K, V]) ForEach(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for k, v := range m.forward {
+		if !f(k, v) {
+			break
+		}
+	}
+}
+
+// Entries returns all key-value pairs of the map as a slice of entries, in the same order as ForEach.
+func (m *BiMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *BiMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *BiMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *BiMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
+// ContainsKey checks whether a key exists.
+func (m *BiMap[K, V]) ContainsKey(key K) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.forward[key]
+	return ok
+}
+
+// ContainsValue checks whether a value exists.
+func (m *BiMap[K, V]) ContainsValue(value V) bool {
+	return m.inverse.ContainsKey(value)
+}
+
+// Size returns the size of the map.
+func (m *BiMap[K, V]) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.forward)
+}
+
+// IsEmpty checks whether the map is empty.
+// It returns true if map is empty, or else false.
+func (m *BiMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Keys returns all keys of the map as a slice.
+func (m *BiMap[K, V]) Keys() []K {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]K, 0, len(m.forward))
+	for k := range m.forward {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns all values of the map as a slice.
+func (m *BiMap[K, V]) Values() []V {
+	return m.inverse.Keys()
+}
+
+// Map returns a copy of the key-value mappings as map.
+func (m *BiMap[K, V]) Map() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[K]V, len(m.forward))
+	for k, v := range m.forward {
+		data[k] = v
+	}
+	return data
+}
+
+// MapStrAny returns a copy of the key-value mappings as map[string]V.
+func (m *BiMap[K, V]) MapStrAny() map[string]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[string]V, len(m.forward))
+	for k, v := range m.forward {
+		data[gconv.String(k)] = v
+	}
+	return data
+}
+
+// Clear deletes all data of the map.
+// The underlying maps are emptied rather than remade, as they are shared with the inverse view.
+func (m *BiMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.forward)
+	clear(m.backward)
+}
+
+// Replace the data of the map with a copy of given `data`, like Clear and then Puts.
+func (m *BiMap[K, V]) Replace(data map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clear(m.forward)
+	clear(m.backward)
+	for k, v := range data {
+		m.putWithoutLock(k, v)
+	}
+}
+
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// all done under one lock. If some of the new values are the same, only one of their keys is kept,
+// which is undefined, like NewBiMapFrom.
+func (m *BiMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := make([]Entry[K, V], 0, len(m.forward))
+	for k, v := range m.forward {
+		entries = append(entries, Entry[K, V]{Key: k, Value: f(k, v)})
+	}
+	clear(m.forward)
+	clear(m.backward)
+	for _, entry := range entries {
+		m.putWithoutLock(entry.Key, entry.Value)
+	}
+}
+
+// Clone returns a new bidirectional map with copy of current map data.
+func (m *BiMap[K, V]) Clone(safe ...bool) Map[K, V] {
+	return NewBiMapFrom[K, V](m.Map(), safe...)
+}
+
+// String returns the map as a string.
+func (m *BiMap[K, V]) String() string {
+	if m == nil {
+		return ""
+	}
+	b, _ := m.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *BiMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *BiMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *BiMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *BiMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (m *BiMap[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
+}
@@ -0,0 +1,137 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestBiMap_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBiMap[string, int]()
+		var _ g.Map[string, int] = m
+		var _ g.ExtendedMap[string, int] = m
+		m.Put("a", 1)
+		m.Puts(map[string]int{"b": 2, "c": 3})
+		t.Assert(m.Get("a"), 1)
+		t.Assert(m.GetByValue(2), "b")
+		k, found := m.SearchByValue(4)
+		t.Assert(k, "")
+		t.Assert(found, false)
+		t.Assert(m.ContainsValue(3), true)
+		t.Assert(m.Size(), 3)
+
+		// Moves value 1 from "a" to "d".
+		m.Put("d", 1)
+		t.Assert(m.ContainsKey("a"), false)
+		t.Assert(m.GetByValue(1), "d")
+		// Rebinds "d" to 4, which unbinds 1.
+		m.Put("d", 4)
+		t.Assert(m.ContainsValue(1), false)
+		t.Assert(m.Size(), 3)
+
+		t.AssertNE(m.PutUnique("e", 2), nil)
+		t.Assert(m.ContainsKey("e"), false)
+		t.AssertNil(m.PutUnique("b", 2))
+		t.AssertNil(m.PutUnique("e", 5))
+		t.Assert(m.GetByValue(5), "e")
+
+		t.Assert(m.PutIfAbsent("e", 6), false)
+		t.Assert(m.GetOrPut("f", 6), 6)
+		t.Assert(m.GetOrPutFunc("f", func() int { return 7 }), 6)
+
+		k, removed := m.RemoveByValue(6)
+		t.Assert(k, "f")
+		t.Assert(removed, true)
+		v, removed := m.Remove("e")
+		t.Assert(v, 5)
+		t.Assert(removed, true)
+		t.Assert(m.ContainsValue(5), false)
+		m.Removes([]string{"b"})
+		t.Assert(m.Map(), map[string]int{"c": 3, "d": 4})
+
+		keys := m.Keys()
+		slices.Sort(keys)
+		t.Assert(keys, []string{"c", "d"})
+		values := m.Values()
+		slices.Sort(values)
+		t.Assert(values, []int{3, 4})
+
+		m.Replace(map[string]int{"x": 9})
+		t.Assert(m.Map(), map[string]int{"x": 9})
+		t.Assert(m.GetByValue(9), "x")
+		t.Assert(m.Clone().Map(), map[string]int{"x": 9})
+		m.Clear()
+		t.Assert(m.IsEmpty(), true)
+		t.Assert(m.ContainsValue(9), false)
+	})
+}
+
+func TestBiMap_Inverse(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBiMapFrom(map[string]int{"a": 1, "b": 2}, true)
+		inverse := m.Inverse()
+		t.Assert(inverse.Get(1), "a")
+		t.Assert(inverse.Inverse() == m, true)
+
+		inverse.Put(3, "c")
+		t.Assert(m.Get("c"), 3)
+		m.Put("a", 10)
+		t.Assert(inverse.ContainsKey(1), false)
+		t.Assert(inverse.Get(10), "a")
+		inverse.Remove(2)
+		t.Assert(m.ContainsKey("b"), false)
+
+		m.Clear()
+		t.Assert(inverse.IsEmpty(), true)
+		m.Put("z", 26)
+		t.Assert(inverse.Map(), map[int]string{26: "z"})
+		inverse.Replace(map[int]string{1: "a"})
+		t.Assert(m.Map(), map[string]int{"a": 1})
+	})
+}
+
+func TestBiMap_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBiMapFrom(map[string]int{"a": 1})
+		t.Assert(m.String(), `{"a":1}`)
+		t.Assert(fmt.Sprint(m), m.String())
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"a":1}`)
+
+		var nilMap *g.BiMap[string, int]
+		t.Assert(nilMap.String(), "")
+	})
+}
+
+func TestBiMap_ReplaceAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBiMapFrom[string, int](map[string]int{"a": 1, "b": 2})
+		m.ReplaceAll(func(key string, value int) int {
+			return value * 10
+		})
+		t.Assert(m.Map(), map[string]int{"a": 10, "b": 20})
+		t.Assert(m.Inverse().Map(), map[int]string{10: "a", 20: "b"})
+
+		// The keys mapped to the same new value are merged into one.
+		m.ReplaceAll(func(key string, value int) int {
+			return 0
+		})
+		t.Assert(m.Size(), 1)
+		t.Assert(m.Inverse().Size(), 1)
+		t.Assert(m.ContainsValue(0), true)
+	})
+}
@@ -0,0 +1,414 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+	"strconv"
+
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+)
+
+// BitSet implements the Set interface for non-negative integers, backed by a bitmap of []uint64,
+// in which the integer i is contained if the bit i is set.
+// It costs one bit per integer in the domain from 0 to the largest item, so it's suitable for
+// dense small-integer domains, and it iterates the items in ascending order.
+// Negative integers are ignored by the set.
+type BitSet struct {
+	mu    rwmutex.RWMutex
+	words []uint64
+}
+
+const (
+	// bitSetWordBits is the number of bits of a word of BitSet.
+	bitSetWordBits = 64
+)
+
+// NewBitSet creates and returns an empty bitset.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewBitSet(safe ...bool) *BitSet {
+	return &BitSet{
+		mu: rwmutex.Create(safe...),
+	}
+}
+
+// NewBitSetFrom creates and returns a bitset from `items`.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewBitSetFrom(items []int, safe ...bool) *BitSet {
+	set := NewBitSet(safe...)
+	for _, item := range items {
+		set.doAddWithoutLock(item)
+	}
+	return set
+}
+
+// newBitSetWords creates and returns a bitset with `words` of which the trailing zero words are trimmed.
+func newBitSetWords(words []uint64, safe bool) *BitSet {
+	set := NewBitSet(safe)
+	set.words = words
+	set.trimWithoutLock()
+	return set
+}
+
+// trimWithoutLock trims the trailing zero words.
+func (set *BitSet) trimWithoutLock() {
+	n := len(set.words)
+	for n > 0 && set.words[n-1] == 0 {
+		n--
+	}
+	set.words = set.words[:n]
+}
+
+// doAddWithoutLock sets the bit of `item`, and returns true if it was not set.
+func (set *BitSet) doAddWithoutLock(item int) bool {
+	if item < 0 {
+		return false
+	}
+	index, mask := item/bitSetWordBits, uint64(1)<<(uint(item)%bitSetWordBits)
+	if index >= len(set.words) {
+		words := make([]uint64, index+1, max(index+1, 2*len(set.words)))
+		copy(words, set.words)
+		set.words = words
+	}
+	if set.words[index]&mask != 0 {
+		return false
+	}
+	set.words[index] |= mask
+	return true
+}
+
+// doRemoveWithoutLock clears the bit of `item`, and returns true if it was set.
+func (set *BitSet) doRemoveWithoutLock(item int) bool {
+	if !set.doContainsWithoutLock(item) {
+		return false
+	}
+	set.words[item/bitSetWordBits] &^= uint64(1) << (uint(item) % bitSetWordBits)
+	set.trimWithoutLock()
+	return true
+}
+
+// doContainsWithoutLock checks whether the bit of `item` is set.
+func (set *BitSet) doContainsWithoutLock(item int) bool {
+	if item < 0 || item/bitSetWordBits >= len(set.words) {
+		return false
+	}
+	return set.words[item/bitSetWordBits]&(uint64(1)<<(uint(item)%bitSetWordBits)) != 0
+}
+
+// doNextSetWithoutLock returns the smallest item >= `from`, or -1 if there is no such item.
+func (set *BitSet) doNextSetWithoutLock(from int) int {
+	from = max(from, 0)
+	index := from / bitSetWordBits
+	if index >= len(set.words) {
+		return -1
+	}
+	// Clears the bits lower than `from` in its word.
+	word := set.words[index] >> (uint(from) % bitSetWordBits)
+	if word != 0 {
+		return from + bits.TrailingZeros64(word)
+	}
+	for index++; index < len(set.words); index++ {
+		if set.words[index] != 0 {
+			return index*bitSetWordBits + bits.TrailingZeros64(set.words[index])
+		}
+	}
+	return -1
+}
+
+// wordsCopy returns a copy of the words.
+func (set *BitSet) wordsCopy() []uint64 {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return append([]uint64(nil), set.words...)
+}
+
+// Add adds `items` to the set, and returns true if any of them was not in the set.
+// Negative items are ignored.
+func (set *BitSet) Add(items ...int) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	changed := false
+	for _, item := range items {
+		if set.doAddWithoutLock(item) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// AddAll adds all the elements in collection `items` to the set.
+// It returns true if the set changed as a result of the call.
+func (set *BitSet) AddAll(items Collection[int]) bool {
+	if other, ok := items.(*BitSet); ok {
+		words := other.wordsCopy()
+		set.mu.Lock()
+		defer set.mu.Unlock()
+		if len(words) > len(set.words) {
+			grown := make([]uint64, len(words))
+			copy(grown, set.words)
+			set.words = grown
+		}
+		changed := false
+		for i, w := range words {
+			if w&^set.words[i] != 0 {
+				set.words[i] |= w
+				changed = true
+			}
+		}
+		return changed
+	}
+	return set.Add(items.Slice()...)
+}
+
+// Remove deletes `items` from the set, and returns true if any of them was in the set.
+func (set *BitSet) Remove(items ...int) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	changed := false
+	for _, item := range items {
+		if set.doRemoveWithoutLock(item) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RemoveAll removes all the elements contained in collection `items` from the set.
+// It returns true if the set changed as a result of the call.
+func (set *BitSet) RemoveAll(items Collection[int]) bool {
+	return set.Remove(items.Slice()...)
+}
+
+// Contains checks whether the set contains `item`.
+func (set *BitSet) Contains(item int) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.doContainsWithoutLock(item)
+}
+
+// ContainsAll returns true if the set contains all the elements in collection `items`.
+func (set *BitSet) ContainsAll(items Collection[int]) bool {
+	values := items.Slice()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for _, v := range values {
+		if !set.doContainsWithoutLock(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextSet returns the smallest item of the set which is >= `from`, and true as `found`,
+// or -1 and false if there is no such item. It can be used to iterate the set by steps:
+//
+//	for i, ok := set.NextSet(0); ok; i, ok = set.NextSet(i + 1) {
+//		...
+//	}
+func (set *BitSet) NextSet(from int) (next int, found bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	next = set.doNextSetWithoutLock(from)
+	return next, next >= 0
+}
+
+// Size returns the number of items of the set, which counts the set bits.
+func (set *BitSet) Size() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	size := 0
+	for _, w := range set.words {
+		size += bits.OnesCount64(w)
+	}
+	return size
+}
+
+// IsEmpty returns true if the set contains no items.
+func (set *BitSet) IsEmpty() bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return len(set.words) == 0
+}
+
+// Clear deletes all items of the set.
+func (set *BitSet) Clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.words = nil
+}
+
+// Clone returns a new bitset, which is a copy of current set.
+func (set *BitSet) Clone() Collection[int] {
+	return newBitSetWords(set.wordsCopy(), set.mu.IsSafe())
+}
+
+// DeepCopy implements interface for deep copy of current type.
+func (set *BitSet) DeepCopy() Collection[int] {
+	if set == nil {
+		return nil
+	}
+	return set.Clone()
+}
+
+// Equals checks whether the set contains the same items as collection `another`.
+func (set *BitSet) Equals(another Collection[int]) bool {
+	if set == another {
+		return true
+	}
+	if other, ok := another.(*BitSet); ok {
+		words := other.wordsCopy()
+		set.mu.RLock()
+		defer set.mu.RUnlock()
+		if len(words) != len(set.words) {
+			return false
+		}
+		for i, w := range words {
+			if set.words[i] != w {
+				return false
+			}
+		}
+		return true
+	}
+	return set.Size() == another.Size() && set.ContainsAll(another)
+}
+
+// ForEach iterates the items in ascending order readonly with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (set *BitSet) ForEach(f func(v int) bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for i, w := range set.words {
+		for w != 0 {
+			if !f(i*bitSetWordBits + bits.TrailingZeros64(w)) {
+				return
+			}
+			// Clears the lowest set bit.
+			w &= w - 1
+		}
+	}
+}
+
+// Slice returns the items of the set in ascending order as slice.
+func (set *BitSet) Slice() []int {
+	items := make([]int, 0, set.Size())
+	set.ForEach(func(v int) bool {
+		items = append(items, v)
+		return true
+	})
+	return items
+}
+
+// And returns a new bitset with the items in both `set` and `other`.
+func (set *BitSet) And(other *BitSet) *BitSet {
+	words := other.wordsCopy()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	words = words[:min(len(words), len(set.words))]
+	for i := range words {
+		words[i] &= set.words[i]
+	}
+	return newBitSetWords(words, set.mu.IsSafe())
+}
+
+// Or returns a new bitset with the items in `set` or `other`.
+func (set *BitSet) Or(other *BitSet) *BitSet {
+	words := other.wordsCopy()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if len(words) < len(set.words) {
+		words = append(words, make([]uint64, len(set.words)-len(words))...)
+	}
+	for i, w := range set.words {
+		words[i] |= w
+	}
+	return newBitSetWords(words, set.mu.IsSafe())
+}
+
+// Xor returns a new bitset with the items in exactly one of `set` and `other`.
+func (set *BitSet) Xor(other *BitSet) *BitSet {
+	words := other.wordsCopy()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if len(words) < len(set.words) {
+		words = append(words, make([]uint64, len(set.words)-len(words))...)
+	}
+	for i, w := range set.words {
+		words[i] ^= w
+	}
+	return newBitSetWords(words, set.mu.IsSafe())
+}
+
+// AndNot returns a new bitset with the items in `set` but not in `other`.
+func (set *BitSet) AndNot(other *BitSet) *BitSet {
+	otherWords := other.wordsCopy()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	words := append([]uint64(nil), set.words...)
+	for i := range words[:min(len(words), len(otherWords))] {
+		words[i] &^= otherWords[i]
+	}
+	return newBitSetWords(words, set.mu.IsSafe())
+}
+
+// Join joins items in ascending order with a string `glue`.
+func (set *BitSet) Join(glue string) string {
+	buffer := bytes.NewBuffer(nil)
+	set.ForEach(func(v int) bool {
+		if buffer.Len() > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(strconv.Itoa(v))
+		return true
+	})
+	return buffer.String()
+}
+
+// String returns items as a string, which implements like json.Marshal does.
+func (set *BitSet) String() string {
+	if set == nil {
+		return ""
+	}
+	return "[" + set.Join(",") + "]"
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (set *BitSet) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, set)
+}
+
+// formatEntries implements the interface formattable.
+func (set *BitSet) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = set.Size()
+	return collectFormatValues(set.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the items using `seed`.
+func (set *BitSet) Hash64(seed maphash.Seed) uint64 {
+	return hashUnordered(seed, set.ForEach)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal,
+// which marshals the items in ascending order as an array.
+func (set *BitSet) MarshalJSON() ([]byte, error) {
+	return marshalJSONSlice(nil, set.Slice())
+}
+
+// UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
+func (set *BitSet) UnmarshalJSON(b []byte) error {
+	var array []int
+	if err := json.UnmarshalUseNumber(b, &array); err != nil {
+		return err
+	}
+	set.Add(array...)
+	return nil
+}
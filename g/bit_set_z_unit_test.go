@@ -0,0 +1,115 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestBitSet_Var(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var s g.BitSet
+		t.Assert(s.IsEmpty(), true)
+		t.Assert(s.Add(3, 1, 3, 200, -1), true)
+		t.Assert(s.Add(1), false)
+		t.Assert(s.Size(), 3)
+		t.Assert(s.Contains(200), true)
+		t.Assert(s.Contains(2), false)
+		t.Assert(s.Contains(-1), false)
+		t.Assert(s.Contains(1000), false)
+		t.Assert(s.Slice(), []int{1, 3, 200})
+		t.Assert(s.Remove(200, 5), true)
+		t.Assert(s.Remove(200), false)
+		t.Assert(s.Slice(), []int{1, 3})
+		s.Clear()
+		t.Assert(s.IsEmpty(), true)
+		t.Assert(s.Size(), 0)
+	})
+}
+
+func TestBitSet_NextSet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewBitSetFrom([]int{0, 63, 64, 130})
+		var items []int
+		for i, ok := s.NextSet(0); ok; i, ok = s.NextSet(i + 1) {
+			items = append(items, i)
+		}
+		t.Assert(items, []int{0, 63, 64, 130})
+		next, found := s.NextSet(65)
+		t.Assert(next, 130)
+		t.Assert(found, true)
+		next, found = s.NextSet(131)
+		t.Assert(next, -1)
+		t.Assert(found, false)
+		next, _ = s.NextSet(-5)
+		t.Assert(next, 0)
+	})
+}
+
+func TestBitSet_Operations(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s1 := g.NewBitSetFrom([]int{1, 2, 3, 100})
+		s2 := g.NewBitSetFrom([]int{2, 3, 4, 300})
+		t.Assert(s1.And(s2).Slice(), []int{2, 3})
+		t.Assert(s1.Or(s2).Slice(), []int{1, 2, 3, 4, 100, 300})
+		t.Assert(s1.Xor(s2).Slice(), []int{1, 4, 100, 300})
+		t.Assert(s1.AndNot(s2).Slice(), []int{1, 100})
+		t.Assert(s2.AndNot(s1).Slice(), []int{4, 300})
+		t.Assert(s1.Xor(s1).IsEmpty(), true)
+		t.Assert(s1.Xor(s2).Xor(s2).Equals(s1), true)
+		t.Assert(s1.Slice(), []int{1, 2, 3, 100})
+
+		t.Assert(s1.AddAll(s2), true)
+		t.Assert(s1.AddAll(s2), false)
+		t.Assert(s1.Slice(), []int{1, 2, 3, 4, 100, 300})
+		t.Assert(s1.AddAll(g.NewArrayListFrom([]int{5})), true)
+		t.Assert(s1.RemoveAll(s2), true)
+		t.Assert(s1.Slice(), []int{1, 5, 100})
+		t.Assert(s1.ContainsAll(g.NewArrayListFrom([]int{1, 100})), true)
+		t.Assert(s1.ContainsAll(g.NewArrayListFrom([]int{1, 2})), false)
+	})
+}
+
+func TestBitSet_Equals(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s1 := g.NewBitSetFrom([]int{1, 500})
+		s2 := g.NewBitSetFrom([]int{1})
+		t.Assert(s1.Equals(s2), false)
+		s1.Remove(500)
+		t.Assert(s1.Equals(s2), true)
+		t.Assert(s1.Equals(g.NewHashSetFrom([]int{1})), true)
+		t.Assert(s1.Equals(g.NewHashSetFrom([]int{2})), false)
+		t.Assert(s1.Equals(s1.Clone()), true)
+		t.Assert(s1.Equals(s1.DeepCopy()), true)
+	})
+}
+
+func TestBitSet_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewBitSetFrom([]int{70, 2})
+		t.Assert(s.String(), "[2,70]")
+		t.Assert(s.Join("-"), "2-70")
+		t.Assert(fmt.Sprint(s), s.String())
+
+		b, err := json.Marshal(s)
+		t.AssertNil(err)
+		t.Assert(string(b), `[2,70]`)
+		var s2 g.BitSet
+		t.AssertNil(json.Unmarshal(b, &s2))
+		t.Assert(s2.Equals(s), true)
+
+		var nilSet *g.BitSet
+		t.Assert(nilSet.String(), "")
+	})
+}
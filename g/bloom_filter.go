@@ -0,0 +1,279 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+)
+
+const (
+	// bloomFilterVersion is the version of the binary format of BloomFilter.
+	bloomFilterVersion = 1
+)
+
+// BloomFilter is a probabilistic membership sketch, which tells whether an item is possibly added
+// or definitely not added, in far less memory than a set of the items.
+// Its false positive rate stays under the configured rate until the expected number of items are added.
+// Items can't be removed from it. It must be created by NewBloomFilter or UnmarshalBinary.
+//
+// The items are hashed by their values deterministically, so a filter serialized by MarshalBinary
+// can be used in other processes, except the items of pointers, channels or containing them,
+// which are hashed by their addresses like they are compared by ==.
+type BloomFilter[T comparable] struct {
+	mu    rwmutex.RWMutex
+	words []uint64
+	m     uint64 // Number of bits.
+	k     uint64 // Number of hash functions.
+}
+
+// NewBloomFilter creates and returns a bloom filter sized for `expectedItems` items with false positive
+// rate `falsePositiveRate`, which should be in (0, 1).
+// The parameter `safe` is used to specify whether using filter in concurrent-safety, which is false in default.
+func NewBloomFilter[T comparable](expectedItems int, falsePositiveRate float64, safe ...bool) *BloomFilter[T] {
+	n := float64(max(expectedItems, 1))
+	p := min(max(falsePositiveRate, math.SmallestNonzeroFloat64), 0.5)
+	// Optimal number of bits m = -n*ln(p)/ln(2)^2, and number of hash functions k = m/n*ln(2).
+	m := uint64(math.Ceil(-n * math.Log(p) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := uint64(math.Round(float64(m) / n * math.Ln2))
+	return &BloomFilter[T]{
+		mu:    rwmutex.Create(safe...),
+		words: make([]uint64, (m+63)/64),
+		m:     m,
+		k:     max(k, 1),
+	}
+}
+
+// bloomHash returns two hashes of `item` for double hashing, the second of which is odd.
+func bloomHash[T comparable](item T) (h1, h2 uint64) {
+	h := fnv.New64a()
+	writeBloomValue(h, item)
+	// Mixes the bits with the finalizer of SplitMix64, as FNV distributes small integers poorly.
+	x := h.Sum64()
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return x & math.MaxUint32, (x >> 32) | 1
+}
+
+// writeBloomValue writes `v` to `h` in an encoding which is stable across processes.
+func writeBloomValue(h hash.Hash64, v interface{}) {
+	var b [8]byte
+	switch value := v.(type) {
+	case string:
+		_, _ = h.Write([]byte(value))
+		return
+	case bool:
+		if value {
+			b[0] = 1
+		}
+	case int:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case int8:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case int16:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case int32:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case int64:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case uint:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case uint8:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case uint16:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case uint32:
+		binary.LittleEndian.PutUint64(b[:], uint64(value))
+	case uint64:
+		binary.LittleEndian.PutUint64(b[:], value)
+	case float32:
+		binary.LittleEndian.PutUint64(b[:], bloomFloatBits(float64(value)))
+	case float64:
+		binary.LittleEndian.PutUint64(b[:], bloomFloatBits(value))
+	default:
+		writeBloomReflectValue(h, reflect.ValueOf(v))
+		return
+	}
+	_, _ = h.Write(b[:])
+}
+
+// writeBloomReflectValue writes `v` of any comparable type to `h`, so that the values equal by == are
+// written the same. The pointers and channels are written by their addresses instead of the values
+// they point to, which may be changed after the item is added.
+func writeBloomReflectValue(h hash.Hash64, v reflect.Value) {
+	var b [8]byte
+	switch v.Kind() {
+	case reflect.Invalid:
+		b[0] = 0xff
+	case reflect.Bool:
+		if v.Bool() {
+			b[0] = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(b[:], uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.LittleEndian.PutUint64(b[:], v.Uint())
+	case reflect.Float32, reflect.Float64:
+		binary.LittleEndian.PutUint64(b[:], bloomFloatBits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		binary.LittleEndian.PutUint64(b[:], bloomFloatBits(real(c)))
+		_, _ = h.Write(b[:])
+		binary.LittleEndian.PutUint64(b[:], bloomFloatBits(imag(c)))
+	case reflect.String:
+		// The length is written first, so that the fields of structs are not mixed up.
+		binary.LittleEndian.PutUint64(b[:], uint64(v.Len()))
+		_, _ = h.Write(b[:])
+		_, _ = h.Write([]byte(v.String()))
+		return
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		binary.LittleEndian.PutUint64(b[:], uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			b[0] = 0xff
+			break
+		}
+		// The dynamic type is written, as the values of different types are not equal.
+		_, _ = h.Write([]byte(v.Elem().Type().String()))
+		writeBloomReflectValue(h, v.Elem())
+		return
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeBloomReflectValue(h, v.Index(i))
+		}
+		return
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeBloomReflectValue(h, v.Field(i))
+		}
+		return
+	}
+	_, _ = h.Write(b[:])
+}
+
+// bloomFloatBits returns the bits of `f` to be hashed, in which -0 is normalized to 0
+// as they are equal by ==.
+func bloomFloatBits(f float64) uint64 {
+	if f == 0 {
+		f = 0
+	}
+	return math.Float64bits(f)
+}
+
+// Add adds `items` to the filter.
+func (f *BloomFilter[T]) Add(items ...T) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, item := range items {
+		h1, h2 := bloomHash(item)
+		for i := uint64(0); i < f.k; i++ {
+			bit := (h1 + i*h2) % f.m
+			f.words[bit/64] |= 1 << (bit % 64)
+		}
+	}
+}
+
+// MightContain returns true if `item` is possibly added to the filter,
+// or false if it is definitely not added.
+func (f *BloomFilter[T]) MightContain(item T) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	h1, h2 := bloomHash(item)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.words[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Merge adds all the items added to `other` into the filter, so that the filter becomes the union of both.
+// It returns an error if `other` is not created with the same parameters.
+func (f *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	other.mu.RLock()
+	m, k := other.m, other.k
+	words := append([]uint64(nil), other.words...)
+	other.mu.RUnlock()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if m != f.m || k != f.k {
+		return errors.New(fmt.Sprintf("bloom filter of %d bits and %d hashes cannot merge one of %d bits and %d hashes", f.m, f.k, m, k))
+	}
+	for i, w := range words {
+		f.words[i] |= w
+	}
+	return nil
+}
+
+// BitSize returns the number of bits of the filter.
+func (f *BloomFilter[T]) BitSize() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return int(f.m)
+}
+
+// HashCount returns the number of hash functions of the filter.
+func (f *BloomFilter[T]) HashCount() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return int(f.k)
+}
+
+// Clear removes all the items from the filter.
+func (f *BloomFilter[T]) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.words)
+}
+
+// MarshalBinary implements the interface encoding.BinaryMarshaler.
+// The data consists of the version, the number of bits and hash functions, and the bits in little endian.
+func (f *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	data := make([]byte, 0, 1+16+8*len(f.words))
+	data = append(data, bloomFilterVersion)
+	data = binary.LittleEndian.AppendUint64(data, f.m)
+	data = binary.LittleEndian.AppendUint64(data, f.k)
+	for _, w := range f.words {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which replaces the parameters and the bits of the filter with the ones in `data`.
+func (f *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 17 || data[0] != bloomFilterVersion {
+		return errors.New("invalid bloom filter data")
+	}
+	m := binary.LittleEndian.Uint64(data[1:])
+	k := binary.LittleEndian.Uint64(data[9:])
+	data = data[17:]
+	// The number of hash functions never exceeds the number of bits, which bounds the work of each call.
+	if m == 0 || k == 0 || k > m || uint64(len(data)) != (m+63)/64*8 {
+		return errors.New("invalid bloom filter data")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.m, f.k = m, k
+	f.words = make([]uint64, len(data)/8)
+	for i := range f.words {
+		f.words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g_test
+
+import (
+	"fmt"
+
+	"github.com/wesleywu/gcontainer/g"
+)
+
+func ExampleBloomFilter_MightContain() {
+	f := g.NewBloomFilter[string](1000, 0.01)
+	f.Add("alice", "bob")
+	fmt.Println(f.MightContain("alice"))
+	fmt.Println(f.MightContain("carol"))
+
+	// Output:
+	// true
+	// false
+}
@@ -0,0 +1,133 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+)
+
+func TestBloomFilter_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		f := g.NewBloomFilter[string](1000, 0.01)
+		t.Assert(f.HashCount(), 7)
+		t.Assert(f.BitSize(), 9586)
+		t.Assert(f.MightContain("a"), false)
+		f.Add("a", "b")
+		t.Assert(f.MightContain("a"), true)
+		t.Assert(f.MightContain("b"), true)
+		f.Clear()
+		t.Assert(f.MightContain("a"), false)
+	})
+}
+
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		f := g.NewBloomFilter[int](10000, 0.01, true)
+		for i := 0; i < 10000; i++ {
+			f.Add(i)
+		}
+		for i := 0; i < 10000; i++ {
+			t.Assert(f.MightContain(i), true)
+		}
+		falsePositives := 0
+		for i := 10000; i < 110000; i++ {
+			if f.MightContain(i) {
+				falsePositives++
+			}
+		}
+		// The expected number is 1000 for the rate 0.01.
+		t.AssertLT(falsePositives, 1500)
+	})
+}
+
+func TestBloomFilter_Merge(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		f1 := g.NewBloomFilter[string](100, 0.01)
+		f2 := g.NewBloomFilter[string](100, 0.01)
+		f1.Add("a")
+		f2.Add("b")
+		t.AssertNil(f1.Merge(f2))
+		t.Assert(f1.MightContain("a"), true)
+		t.Assert(f1.MightContain("b"), true)
+		t.AssertNE(f1.Merge(g.NewBloomFilter[string](1000, 0.01)), nil)
+	})
+}
+
+func TestBloomFilter_Binary(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		f := g.NewBloomFilter[int64](500, 0.001)
+		for i := int64(0); i < 500; i++ {
+			f.Add(i * 7)
+		}
+		data, err := f.MarshalBinary()
+		t.AssertNil(err)
+
+		var f2 g.BloomFilter[int64]
+		t.AssertNil(f2.UnmarshalBinary(data))
+		t.Assert(f2.BitSize(), f.BitSize())
+		t.Assert(f2.HashCount(), f.HashCount())
+		for i := int64(0); i < 1000; i++ {
+			t.Assert(f2.MightContain(i), f.MightContain(i))
+		}
+		t.AssertNE(f2.UnmarshalBinary(data[:20]), nil)
+		t.AssertNE(f2.UnmarshalBinary(nil), nil)
+
+		// The number of hash functions must not exceed the number of bits.
+		invalid := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint64(invalid[9:], uint64(f.BitSize())+1)
+		t.AssertNE(f2.UnmarshalBinary(invalid), nil)
+		t.Assert(f2.HashCount(), f.HashCount())
+	})
+}
+
+func TestBloomFilter_Equality(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		f := g.NewBloomFilter[float64](100, 0.01)
+		f.Add(math.Copysign(0, -1))
+		t.Assert(f.MightContain(0), true)
+
+		f32 := g.NewBloomFilter[float32](100, 0.01)
+		f32.Add(0)
+		t.Assert(f32.MightContain(float32(math.Copysign(0, -1))), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		// The pointers are hashed by identity, so changing the pointed value does not matter.
+		type item struct {
+			name string
+		}
+		f := g.NewBloomFilter[*item](100, 0.01)
+		a := &item{name: "a"}
+		f.Add(a)
+		a.name = "b"
+		t.Assert(f.MightContain(a), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		type key struct {
+			name  string
+			score float64
+			ptr   *int
+		}
+		n := 1
+		f := g.NewBloomFilter[key](100, 0.01)
+		f.Add(key{name: "a", score: math.Copysign(0, -1), ptr: &n})
+		n = 2
+		t.Assert(f.MightContain(key{name: "a", ptr: &n}), true)
+
+		fa := g.NewBloomFilter[any](100, 0.01)
+		fa.Add(key{name: "a", ptr: &n}, 1, "x")
+		t.Assert(fa.MightContain(key{name: "a", ptr: &n}), true)
+		t.Assert(fa.MightContain(1), true)
+		t.Assert(fa.MightContain("x"), true)
+	})
+}
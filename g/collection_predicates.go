@@ -0,0 +1,28 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// anyMatch returns true if `predicate` returns true for any value iterated by `forEach`,
+// which stops iterating at the first match.
+func anyMatch[T any](forEach func(f func(v T) bool), predicate func(v T) bool) (matched bool) {
+	forEach(func(v T) bool {
+		matched = predicate(v)
+		return !matched
+	})
+	return
+}
+
+// countMatch returns the number of values iterated by `forEach` for which `predicate` returns true.
+func countMatch[T any](forEach func(f func(v T) bool), predicate func(v T) bool) (count int) {
+	forEach(func(v T) bool {
+		if predicate(v) {
+			count++
+		}
+		return true
+	})
+	return
+}
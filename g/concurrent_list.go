@@ -0,0 +1,240 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"sync/atomic"
+
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// ConcurrentList is a lock-free concurrent-safe singly linked list for multi-producer multi-consumer
+// workloads, which appends to the back and pops from the front with CAS operations on the links,
+// following the algorithm of Michael and Scott. It scales better than the LinkedList in concurrent-safe
+// usage on many cores, as pushing and popping never block each other.
+// The zero value for ConcurrentList is an empty list ready to use.
+//
+// It supports only the operations which can be done lock-free, so there's no removal in the middle
+// or positional access. Iterations are weakly consistent: they see the elements in the list when they
+// reach them, which may include elements pushed or exclude elements popped during the iteration.
+type ConcurrentList[T any] struct {
+	head        atomic.Pointer[concurrentListNode[T]] // Sentinel node, of which the next node is the front.
+	tail        atomic.Pointer[concurrentListNode[T]] // The last or the second last node.
+	len         atomic.Int64                          // Length, which is increased before linking a node.
+	jsonOptions atomic.Pointer[JSONOptions]           // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// concurrentListNode is a node of ConcurrentList.
+type concurrentListNode[T any] struct {
+	value T
+	next  atomic.Pointer[concurrentListNode[T]]
+}
+
+// NewConcurrentList creates and returns an empty lock-free list.
+func NewConcurrentList[T any]() *ConcurrentList[T] {
+	l := &ConcurrentList[T]{}
+	l.lazyInit()
+	return l
+}
+
+// NewConcurrentListFrom creates and returns a lock-free list with the values of `array` in order.
+func NewConcurrentListFrom[T any](array []T) *ConcurrentList[T] {
+	l := NewConcurrentList[T]()
+	l.PushBacks(array)
+	return l
+}
+
+// lazyInit lazily initializes the sentinel node of a zero ConcurrentList value.
+// The tail is set after the head, so that every goroutine helps to finish the initialization.
+func (l *ConcurrentList[T]) lazyInit() {
+	if l.tail.Load() != nil {
+		return
+	}
+	l.head.CompareAndSwap(nil, &concurrentListNode[T]{})
+	l.tail.CompareAndSwap(nil, l.head.Load())
+}
+
+// PushBack appends a new element with value `v` to the back of the list.
+func (l *ConcurrentList[T]) PushBack(v T) {
+	l.lazyInit()
+	node := &concurrentListNode[T]{value: v}
+	l.len.Add(1)
+	for {
+		tail := l.tail.Load()
+		next := tail.next.Load()
+		if tail != l.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// The tail is lagging behind, helps to advance it.
+			l.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, node) {
+			l.tail.CompareAndSwap(tail, node)
+			return
+		}
+	}
+}
+
+// PushBacks appends new elements with values `values` to the back of the list in order.
+// Note that the values may be interleaved with the ones pushed concurrently.
+func (l *ConcurrentList[T]) PushBacks(values []T) {
+	for _, v := range values {
+		l.PushBack(v)
+	}
+}
+
+// PopFront removes the element from the front of the list and returns its value.
+// If the list is empty, the `ok` is false.
+func (l *ConcurrentList[T]) PopFront() (value T, ok bool) {
+	l.lazyInit()
+	for {
+		head := l.head.Load()
+		tail := l.tail.Load()
+		next := head.next.Load()
+		if head != l.head.Load() {
+			continue
+		}
+		if next == nil {
+			return
+		}
+		if head == tail {
+			// The tail is lagging behind, helps to advance it.
+			l.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		// The next node becomes the new sentinel node. Its value is not cleared,
+		// as it may be read by concurrent iterations.
+		if l.head.CompareAndSwap(head, next) {
+			l.len.Add(-1)
+			return next.value, true
+		}
+	}
+}
+
+// PopFronts removes at most `max` elements from the front of the list, or all elements if `max` <= 0,
+// and returns values of the removed elements as slice.
+func (l *ConcurrentList[T]) PopFronts(max int) (values []T) {
+	for max <= 0 || len(values) < max {
+		v, ok := l.PopFront()
+		if !ok {
+			break
+		}
+		values = append(values, v)
+	}
+	return
+}
+
+// FrontValue returns value of the first element of the list, and true as `ok`,
+// or empty of type T and false if the list is empty.
+func (l *ConcurrentList[T]) FrontValue() (value T, ok bool) {
+	l.lazyInit()
+	if front := l.head.Load().next.Load(); front != nil {
+		return front.value, true
+	}
+	return
+}
+
+// Len returns the number of elements of the list.
+// Note that it may be transiently larger than the number of elements reachable by iterations,
+// when elements are being pushed concurrently.
+func (l *ConcurrentList[T]) Len() int {
+	return int(l.len.Load())
+}
+
+// Size is alias of Len.
+func (l *ConcurrentList[T]) Size() int {
+	return l.Len()
+}
+
+// IsEmpty checks whether the list is empty.
+func (l *ConcurrentList[T]) IsEmpty() bool {
+	l.lazyInit()
+	return l.head.Load().next.Load() == nil
+}
+
+// ForEach iterates the list from front to back with given callback function `f`, which is weakly consistent.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (l *ConcurrentList[T]) ForEach(f func(value T) bool) {
+	l.lazyInit()
+	for node := l.head.Load().next.Load(); node != nil; node = node.next.Load() {
+		if !f(node.value) {
+			return
+		}
+	}
+}
+
+// Slice returns a copy of the values of the elements from front to back.
+func (l *ConcurrentList[T]) Slice() []T {
+	values := make([]T, 0, l.Len())
+	l.ForEach(func(value T) bool {
+		values = append(values, value)
+		return true
+	})
+	return values
+}
+
+// Join joins list elements with a string `glue`.
+func (l *ConcurrentList[T]) Join(glue string) string {
+	buffer := bytes.NewBuffer(nil)
+	first := true
+	l.ForEach(func(value T) bool {
+		if !first {
+			buffer.WriteString(glue)
+		}
+		first = false
+		buffer.WriteString(gconv.String(value))
+		return true
+	})
+	return buffer.String()
+}
+
+// String returns current list as a string.
+func (l *ConcurrentList[T]) String() string {
+	if l == nil {
+		return ""
+	}
+	return "[" + l.Join(",") + "]"
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (l *ConcurrentList[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, l)
+}
+
+// formatEntries implements the interface formattable.
+func (l *ConcurrentList[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	values := l.Slice()
+	return collectFormatValues(func(f func(v T) bool) {
+		for _, v := range values {
+			if !f(v) {
+				return
+			}
+		}
+	}, len(values), limit), false, len(values)
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is sensitive to the order of elements.
+func (l *ConcurrentList[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashOrdered(seed, l.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (l *ConcurrentList[T]) SetMarshalOptions(options JSONOptions) {
+	l.jsonOptions.Store(newJSONOptions(options))
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (l *ConcurrentList[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSONSlice(l.jsonOptions.Load(), l.Slice())
+}
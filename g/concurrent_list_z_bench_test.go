@@ -0,0 +1,48 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go -bench=".*" -benchmem
+
+package g_test
+
+import (
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+)
+
+// Benchmark_ConcurrentList_PushPop benchmarks the lock-free list against the safe LinkedList
+// in a multi-producer multi-consumer workload.
+func Benchmark_ConcurrentList_PushPop(b *testing.B) {
+	b.Run("ConcurrentList", func(b *testing.B) {
+		list := g.NewConcurrentList[int]()
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if i%2 == 0 {
+					list.PushBack(i)
+				} else {
+					list.PopFront()
+				}
+				i++
+			}
+		})
+	})
+	b.Run("LinkedList", func(b *testing.B) {
+		list := g.NewLinkedList[int](true)
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if i%2 == 0 {
+					list.PushBack(i)
+				} else {
+					list.PopFront()
+				}
+				i++
+			}
+		})
+	})
+}
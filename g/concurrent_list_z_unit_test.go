@@ -0,0 +1,113 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestConcurrentList_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var l g.ConcurrentList[int]
+		t.Assert(l.IsEmpty(), true)
+		_, ok := l.PopFront()
+		t.Assert(ok, false)
+		_, ok = l.FrontValue()
+		t.Assert(ok, false)
+
+		l.PushBack(1)
+		l.PushBacks([]int{2, 3, 4})
+		t.Assert(l.Len(), 4)
+		t.Assert(l.Size(), 4)
+		t.Assert(l.IsEmpty(), false)
+		v, ok := l.FrontValue()
+		t.Assert(ok, true)
+		t.Assert(v, 1)
+		t.Assert(l.Slice(), []int{1, 2, 3, 4})
+
+		v, ok = l.PopFront()
+		t.Assert(ok, true)
+		t.Assert(v, 1)
+		t.Assert(l.PopFronts(2), []int{2, 3})
+		t.Assert(l.PopFronts(0), []int{4})
+		t.Assert(l.Len(), 0)
+		t.Assert(l.IsEmpty(), true)
+		l.PushBack(5)
+		t.Assert(l.Slice(), []int{5})
+	})
+}
+
+func TestConcurrentList_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewConcurrentListFrom([]string{"a", "b"})
+		t.Assert(l.Join("-"), "a-b")
+		t.Assert(l.String(), "[a,b]")
+		t.Assert(fmt.Sprint(l), l.String())
+		b, err := json.Marshal(l)
+		t.AssertNil(err)
+		t.Assert(string(b), `["a","b"]`)
+		var nilList *g.ConcurrentList[int]
+		t.Assert(nilList.String(), "")
+	})
+}
+
+func TestConcurrentList_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			l         = g.NewConcurrentList[int]()
+			producers = 8
+			consumers = 8
+			perWorker = 1000
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			sum       int
+			count     int
+		)
+		for i := 0; i < producers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 1; j <= perWorker; j++ {
+					l.PushBack(j)
+				}
+			}()
+		}
+		for i := 0; i < consumers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				localSum, localCount := 0, 0
+				for n := 0; n < perWorker/2; n++ {
+					if v, ok := l.PopFront(); ok {
+						localSum += v
+						localCount++
+					}
+				}
+				mu.Lock()
+				sum += localSum
+				count += localCount
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		for _, v := range l.PopFronts(0) {
+			sum += v
+			count++
+		}
+		t.Assert(count, producers*perWorker)
+		t.Assert(sum, producers*perWorker*(perWorker+1)/2)
+		t.Assert(l.Len(), 0)
+	})
+}
@@ -0,0 +1,413 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wesleywu/gcontainer/internal/deepcopy"
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/utils/equal"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+	"github.com/wesleywu/gcontainer/utils/gstr"
+)
+
+// CopyOnWriteArrayList is a concurrent-safe array for read-heavy workloads, like CopyOnWriteArrayList of Java.
+// Reads are lock-free on an immutable snapshot of the array, while every mutation copies the whole array
+// and swaps the snapshot atomically. It is suitable for data that is read frequently and rarely updated,
+// like configurations or listeners, and the zero value is an empty array ready to use.
+//
+// Iterations work on the snapshot when they start, so they never see concurrent mutations.
+type CopyOnWriteArrayList[T any] struct {
+	mu          sync.Mutex                  // Serializes writers.
+	array       atomic.Pointer[[]T]         // Current immutable snapshot.
+	jsonOptions atomic.Pointer[JSONOptions] // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// NewCopyOnWriteArrayList creates and returns an empty copy-on-write array.
+func NewCopyOnWriteArrayList[T any]() *CopyOnWriteArrayList[T] {
+	return &CopyOnWriteArrayList[T]{}
+}
+
+// NewCopyOnWriteArrayListFrom creates and returns a copy-on-write array with a copy of `array`.
+func NewCopyOnWriteArrayListFrom[T any](array []T) *CopyOnWriteArrayList[T] {
+	a := &CopyOnWriteArrayList[T]{}
+	newArray := make([]T, len(array))
+	copy(newArray, array)
+	a.array.Store(&newArray)
+	return a
+}
+
+// load returns the current snapshot, which must not be modified.
+func (a *CopyOnWriteArrayList[T]) load() []T {
+	if p := a.array.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// update replaces the snapshot with the result of `f` on a copy of current snapshot.
+// The array is not changed if `f` returns false.
+func (a *CopyOnWriteArrayList[T]) update(f func(array []T) ([]T, bool)) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var (
+		current  = a.load()
+		newArray = make([]T, len(current))
+	)
+	copy(newArray, current)
+	newArray, changed := f(newArray)
+	if changed {
+		a.array.Store(&newArray)
+	}
+	return changed
+}
+
+// Get returns the value by the specified index.
+// If the given `index` is out of range of the array, the `found` is false.
+func (a *CopyOnWriteArrayList[T]) Get(index int) (value T, found bool) {
+	array := a.load()
+	if index < 0 || index >= len(array) {
+		return
+	}
+	return array[index], true
+}
+
+// MustGet returns the value by the specified index.
+// If the given `index` is out of range of the array, it returns empty value of type T.
+func (a *CopyOnWriteArrayList[T]) MustGet(index int) (value T) {
+	value, _ = a.Get(index)
+	return
+}
+
+// Set sets value to specified index.
+func (a *CopyOnWriteArrayList[T]) Set(index int, value T) (err error) {
+	a.update(func(array []T) ([]T, bool) {
+		if index < 0 || index >= len(array) {
+			err = errors.New(fmt.Sprintf("index %d out of array range %d", index, len(array)))
+			return array, false
+		}
+		array[index] = value
+		return array, true
+	})
+	return
+}
+
+// Add adds all the `values` to the end of array.
+func (a *CopyOnWriteArrayList[T]) Add(values ...T) bool {
+	if len(values) == 0 {
+		return false
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var (
+		current  = a.load()
+		newArray = make([]T, len(current), len(current)+len(values))
+	)
+	copy(newArray, current)
+	newArray = append(newArray, values...)
+	a.array.Store(&newArray)
+	return true
+}
+
+// AddAll adds all the elements in the specified collection to the end of array.
+func (a *CopyOnWriteArrayList[T]) AddAll(values Collection[T]) bool {
+	return a.Add(values.Slice()...)
+}
+
+// AddIfAbsent adds `value` to the end of array if it does not exist in the array.
+// It returns true if `value` is added.
+func (a *CopyOnWriteArrayList[T]) AddIfAbsent(value T) bool {
+	return a.update(func(array []T) ([]T, bool) {
+		if doSearchSlice(array, value) != -1 {
+			return array, false
+		}
+		return append(array, value), true
+	})
+}
+
+// InsertBefore inserts the `values` to the front of `index`.
+func (a *CopyOnWriteArrayList[T]) InsertBefore(index int, values ...T) (err error) {
+	a.update(func(array []T) ([]T, bool) {
+		if index < 0 || index >= len(array) {
+			err = errors.New(fmt.Sprintf("index %d out of array range %d", index, len(array)))
+			return array, false
+		}
+		return slices.Insert(array, index, values...), true
+	})
+	return
+}
+
+// RemoveAt removes an item by index.
+// If the given `index` is out of range of the array, the `found` is false.
+func (a *CopyOnWriteArrayList[T]) RemoveAt(index int) (value T, found bool) {
+	found = a.update(func(array []T) ([]T, bool) {
+		if index < 0 || index >= len(array) {
+			return array, false
+		}
+		value = array[index]
+		return append(array[:index], array[index+1:]...), true
+	})
+	return
+}
+
+// Remove removes the first occurrence of each of `values` from the array.
+// It returns true if any of the `values` is removed.
+func (a *CopyOnWriteArrayList[T]) Remove(values ...T) bool {
+	return a.update(func(array []T) ([]T, bool) {
+		changed := false
+		for _, value := range values {
+			if i := doSearchSlice(array, value); i != -1 {
+				array = append(array[:i], array[i+1:]...)
+				changed = true
+			}
+		}
+		return array, changed
+	})
+}
+
+// RemoveAll removes the first occurrence of each element of `values` from the array.
+func (a *CopyOnWriteArrayList[T]) RemoveAll(values Collection[T]) bool {
+	return a.Remove(values.Slice()...)
+}
+
+// Clear deletes all items of current array.
+func (a *CopyOnWriteArrayList[T]) Clear() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.array.Store(nil)
+}
+
+// Replace replaces the whole array with a copy of `array`.
+func (a *CopyOnWriteArrayList[T]) Replace(array []T) {
+	newArray := make([]T, len(array))
+	copy(newArray, array)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.array.Store(&newArray)
+}
+
+// Len returns the length of array.
+func (a *CopyOnWriteArrayList[T]) Len() int {
+	return len(a.load())
+}
+
+// Size returns the length of array.
+func (a *CopyOnWriteArrayList[T]) Size() int {
+	return a.Len()
+}
+
+// IsEmpty checks whether the array is empty.
+func (a *CopyOnWriteArrayList[T]) IsEmpty() bool {
+	return a.Len() == 0
+}
+
+// Slice returns a copy of the underlying data of array.
+func (a *CopyOnWriteArrayList[T]) Slice() []T {
+	array := a.load()
+	newArray := make([]T, len(array))
+	copy(newArray, array)
+	return newArray
+}
+
+// Snapshot returns the current immutable snapshot of array without copying.
+// Note that the returned slice is shared and must not be modified.
+func (a *CopyOnWriteArrayList[T]) Snapshot() []T {
+	return a.load()
+}
+
+// Search searches array by `value`, returns the index of `value`,
+// or returns -1 if not exists.
+func (a *CopyOnWriteArrayList[T]) Search(value T) int {
+	return doSearchSlice(a.load(), value)
+}
+
+// Contains checks whether a value exists in the array.
+func (a *CopyOnWriteArrayList[T]) Contains(value T) bool {
+	return a.Search(value) != -1
+}
+
+// ContainsAll checks whether all the elements of `values` exist in the array.
+func (a *CopyOnWriteArrayList[T]) ContainsAll(values Collection[T]) bool {
+	var (
+		array    = a.load()
+		allFound = true
+	)
+	values.ForEach(func(value T) bool {
+		if doSearchSlice(array, value) == -1 {
+			allFound = false
+			return false
+		}
+		return true
+	})
+	return allFound
+}
+
+// ForEach iterates the snapshot of array with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (a *CopyOnWriteArrayList[T]) ForEach(f func(value T) bool) {
+	for _, v := range a.load() {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// ForEachAsc iterates the snapshot of array in ascending order with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (a *CopyOnWriteArrayList[T]) ForEachAsc(f func(index int, value T) bool) {
+	for i, v := range a.load() {
+		if !f(i, v) {
+			return
+		}
+	}
+}
+
+// Clone returns a new array, which is a copy of current array.
+func (a *CopyOnWriteArrayList[T]) Clone() Collection[T] {
+	return NewCopyOnWriteArrayListFrom[T](a.load())
+}
+
+// DeepCopy implements interface for deep copy of current type.
+func (a *CopyOnWriteArrayList[T]) DeepCopy() Collection[T] {
+	if a == nil {
+		return nil
+	}
+	array := a.load()
+	newArray := make([]T, len(array))
+	for i, v := range array {
+		newArray[i] = deepcopy.Copy(v).(T)
+	}
+	newList := &CopyOnWriteArrayList[T]{}
+	newList.array.Store(&newArray)
+	return newList
+}
+
+// Equals checks whether `another` is a CopyOnWriteArrayList with equal elements in the same order.
+func (a *CopyOnWriteArrayList[T]) Equals(another Collection[T]) bool {
+	if a == another {
+		return true
+	}
+	ano, ok := another.(*CopyOnWriteArrayList[T])
+	if !ok {
+		return false
+	}
+	array, anoArray := a.load(), ano.load()
+	if len(array) != len(anoArray) {
+		return false
+	}
+	for i, v := range array {
+		if !equal.Equals(v, anoArray[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Join joins array elements with a string `glue`.
+func (a *CopyOnWriteArrayList[T]) Join(glue string) string {
+	array := a.load()
+	buffer := bytes.NewBuffer(nil)
+	for i, v := range array {
+		if i > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(gconv.String(v))
+	}
+	return buffer.String()
+}
+
+// JoinFunc joins array elements with a string `glue`, each of which is formatted by `format`.
+func (a *CopyOnWriteArrayList[T]) JoinFunc(glue string, format func(value T) string) string {
+	array := a.load()
+	buffer := bytes.NewBuffer(nil)
+	for i, v := range array {
+		if i > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(format(v))
+	}
+	return buffer.String()
+}
+
+// String returns current array as a string, which implements like json.Marshal does.
+func (a *CopyOnWriteArrayList[T]) String() string {
+	if a == nil {
+		return ""
+	}
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteByte('[')
+	for i, v := range a.load() {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		s := gconv.String(v)
+		if gstr.IsNumeric(s) {
+			buffer.WriteString(s)
+		} else {
+			buffer.WriteString(`"` + gstr.QuoteMeta(s, `"\`) + `"`)
+		}
+	}
+	buffer.WriteByte(']')
+	return buffer.String()
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (a *CopyOnWriteArrayList[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, a)
+}
+
+// formatEntries implements the interface formattable.
+func (a *CopyOnWriteArrayList[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = a.Len()
+	return collectFormatValues(a.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is sensitive to the order of elements.
+func (a *CopyOnWriteArrayList[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashOrdered(seed, a.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (a *CopyOnWriteArrayList[T]) SetMarshalOptions(options JSONOptions) {
+	a.jsonOptions.Store(newJSONOptions(options))
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (a *CopyOnWriteArrayList[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSONSlice(a.jsonOptions.Load(), a.load())
+}
+
+// UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
+func (a *CopyOnWriteArrayList[T]) UnmarshalJSON(b []byte) error {
+	var array []T
+	if err := json.UnmarshalUseNumber(b, &array); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.array.Store(&array)
+	return nil
+}
+
+// doSearchSlice returns the index of the first element in `array` equal to `value`, or -1 if not found.
+func doSearchSlice[T any](array []T, value T) int {
+	for i, v := range array {
+		if equal.Equals(v, value) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,121 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestCopyOnWriteArrayList_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var zero g.CopyOnWriteArrayList[int]
+		t.Assert(zero.Len(), 0)
+		t.Assert(zero.Add(1), true)
+		t.Assert(zero.Slice(), []int{1})
+
+		source := []int{1, 2, 3}
+		a := g.NewCopyOnWriteArrayListFrom(source)
+		source[0] = 100
+		t.Assert(a.Slice(), []int{1, 2, 3})
+		t.Assert(a.MustGet(0), 1)
+		_, found := a.Get(3)
+		t.Assert(found, false)
+
+		t.AssertNil(a.Set(1, 20))
+		t.AssertNE(a.Set(3, 0), nil)
+		t.Assert(a.Slice(), []int{1, 20, 3})
+		t.Assert(a.AddIfAbsent(3), false)
+		t.Assert(a.AddIfAbsent(4), true)
+		t.AssertNil(a.InsertBefore(0, -1, 0))
+		t.Assert(a.Slice(), []int{-1, 0, 1, 20, 3, 4})
+		t.Assert(a.Search(20), 3)
+		t.Assert(a.Contains(5), false)
+		t.Assert(a.ContainsAll(g.NewArrayListFrom([]int{0, 1})), true)
+		t.Assert(a.ContainsAll(g.NewArrayListFrom([]int{0, 5})), false)
+
+		v, found := a.RemoveAt(0)
+		t.Assert(v, -1)
+		t.Assert(found, true)
+		t.Assert(a.Remove(20, 30), true)
+		t.Assert(a.Remove(30), false)
+		t.Assert(a.Slice(), []int{0, 1, 3, 4})
+		t.Assert(a.Join(","), "0,1,3,4")
+		t.Assert(a.String(), "[0,1,3,4]")
+		t.Assert(fmt.Sprint(a), a.String())
+
+		t.Assert(a.Equals(a.Clone()), true)
+		t.Assert(a.Equals(a.DeepCopy()), true)
+		a.Replace([]int{9})
+		t.Assert(a.Slice(), []int{9})
+		a.Clear()
+		t.Assert(a.IsEmpty(), true)
+	})
+}
+
+func TestCopyOnWriteArrayList_Snapshot(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		a := g.NewCopyOnWriteArrayListFrom([]int{1, 2, 3})
+		snapshot := a.Snapshot()
+		var values []int
+		a.ForEach(func(value int) bool {
+			// Mutations during iteration are not visible to the iteration.
+			a.Add(value * 10)
+			values = append(values, value)
+			return true
+		})
+		t.Assert(values, []int{1, 2, 3})
+		t.Assert(snapshot, []int{1, 2, 3})
+		t.Assert(a.Slice(), []int{1, 2, 3, 10, 20, 30})
+	})
+}
+
+func TestCopyOnWriteArrayList_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			a  = g.NewCopyOnWriteArrayList[int]()
+			wg sync.WaitGroup
+		)
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					a.Add(i*100 + j)
+				}
+			}(i)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					a.ForEach(func(value int) bool { return true })
+				}
+			}()
+		}
+		wg.Wait()
+		t.Assert(a.Len(), 1000)
+	})
+}
+
+func TestCopyOnWriteArrayList_Json(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		a := g.NewCopyOnWriteArrayListFrom([]string{"a", "b"})
+		b, err := json.Marshal(a)
+		t.AssertNil(err)
+		t.Assert(string(b), `["a","b"]`)
+
+		var a2 g.CopyOnWriteArrayList[string]
+		t.AssertNil(json.Unmarshal(b, &a2))
+		t.Assert(a2.Slice(), []string{"a", "b"})
+	})
+}
@@ -0,0 +1,635 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wesleywu/gcontainer/gtimer"
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// ExpiringMapOptions is the options of ExpiringMap.
+type ExpiringMapOptions[K comparable, V any] struct {
+	// OnEvict is called with every entry removed from the map for its expiration, after the map is unlocked,
+	// no matter it's found expired on access or by the background sweep.
+	// It's not called for the entries removed by Remove, Removes, Clear or Replace.
+	OnEvict func(key K, value V)
+
+	// SweepInterval is the interval of the background sweep, which removes the expired entries that are
+	// not accessed any more. It's the default TTL of the map if zero, or one second if the default TTL is
+	// not positive either. The background sweep is disabled if it's negative, so that the expired entries
+	// are only removed on access.
+	// The sweep runs on the default timer of package gtimer, so the interval is rounded to its ticks,
+	// which are 100 milliseconds by default.
+	SweepInterval time.Duration
+
+	// SweepLimit is the max number of the expirations handled by each background sweep, which bounds the
+	// time the map is locked by it. The expired entries beyond the limit are removed by the following sweeps,
+	// or on access. It's 1000 if not positive.
+	SweepLimit int
+}
+
+const (
+	defaultExpiringMapSweepLimit = 1000 // Default max number of the expirations handled by each background sweep.
+	expiringMapCompactSlack      = 64   // Number of the stale expirations allowed beyond the entries before compaction.
+)
+
+// ExpiringMap is a hash map whose entries expire after a TTL (time to live), either the default one of
+// the map or the one specified for each entry. The expired entries are invisible to all the methods,
+// and are removed when they are accessed, or by a background sweep scheduled with package gtimer, which
+// takes the expired entries from a min-heap of the expiration times instead of scanning the whole map.
+//
+// It's always concurrent-safe, as the background sweep runs in another goroutine.
+// It should be closed by Close if it's no longer used but still holds entries to expire,
+// so that the background sweep stops.
+type ExpiringMap[K comparable, V any] struct {
+	mu            rwmutex.RWMutex
+	data          map[K]expiringMapEntry[V]
+	ttl           time.Duration
+	onEvict       func(key K, value V)
+	expiry        *expiringMapHeap[K] // Expirations of the entries, including the stale ones of the entries overwritten or removed.
+	sweepInterval time.Duration
+	sweepLimit    int
+	sweeper       *gtimer.Entry // Timing job of the background sweep, or nil if it's not scheduled.
+	closed        bool
+	jsonOptions   *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// expiringMapEntry is the value and the expiration time of an entry in ExpiringMap.
+type expiringMapEntry[V any] struct {
+	value    V
+	expireAt time.Time // Zero if the entry never expires.
+}
+
+// expired checks whether the entry is expired at `now`.
+func (e expiringMapEntry[V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+// expiringMapHeap is a min-heap of the expirations of the entries in ExpiringMap, of which the underlying
+// `array` is an array implementing a heap structure, with the earliest expiration on the top.
+type expiringMapHeap[K comparable] struct {
+	array []expiringMapItem[K]
+}
+
+// expiringMapItem is the expiration of an entry in expiringMapHeap. It's stale if the entry of `key`
+// no longer expires at `expireAt`.
+type expiringMapItem[K comparable] struct {
+	key      K
+	expireAt time.Time
+}
+
+// Len is used to implement the interface of sort.Interface.
+func (h *expiringMapHeap[K]) Len() int {
+	return len(h.array)
+}
+
+// Less is used to implement the interface of sort.Interface.
+// The earliest one is placed to the top of the heap.
+func (h *expiringMapHeap[K]) Less(i, j int) bool {
+	return h.array[i].expireAt.Before(h.array[j].expireAt)
+}
+
+// Swap is used to implement the interface of sort.Interface.
+func (h *expiringMapHeap[K]) Swap(i, j int) {
+	h.array[i], h.array[j] = h.array[j], h.array[i]
+}
+
+// Push pushes an item to the heap.
+func (h *expiringMapHeap[K]) Push(x interface{}) {
+	h.array = append(h.array, x.(expiringMapItem[K]))
+}
+
+// Pop removes and returns the last item of the array, which is used by heap.Pop.
+func (h *expiringMapHeap[K]) Pop() interface{} {
+	length := len(h.array)
+	item := h.array[length-1]
+	h.array = h.array[:length-1]
+	return item
+}
+
+// NewExpiringMap creates and returns an empty expiring map, whose entries put by Put expire after
+// `defaultTTL`. The entries never expire by default if `defaultTTL` is not positive.
+// The optional parameter `options` specifies the eviction callback and the background sweep.
+func NewExpiringMap[K comparable, V any](defaultTTL time.Duration, options ...ExpiringMapOptions[K, V]) *ExpiringMap[K, V] {
+	m := &ExpiringMap[K, V]{
+		mu:         rwmutex.Create(true),
+		data:       make(map[K]expiringMapEntry[V]),
+		ttl:        defaultTTL,
+		expiry:     &expiringMapHeap[K]{},
+		sweepLimit: defaultExpiringMapSweepLimit,
+	}
+	if len(options) > 0 {
+		m.onEvict = options[0].OnEvict
+		m.sweepInterval = options[0].SweepInterval
+		if options[0].SweepLimit > 0 {
+			m.sweepLimit = options[0].SweepLimit
+		}
+	}
+	if m.sweepInterval == 0 {
+		m.sweepInterval = time.Second
+		if defaultTTL > 0 {
+			m.sweepInterval = defaultTTL
+		}
+	}
+	return m
+}
+
+// Put sets key-value to the map, which expires after the default TTL of the map.
+func (m *ExpiringMap[K, V]) Put(key K, value V) {
+	m.PutWithTTL(key, value, m.ttl)
+}
+
+// PutWithTTL sets key-value to the map, which expires after `ttl`, or never expires if `ttl` is not positive.
+func (m *ExpiringMap[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putWithoutLock(key, value, ttl, time.Now())
+}
+
+// Puts batch sets key-values to the map, which expire after the default TTL of the map.
+func (m *ExpiringMap[K, V]) Puts(data map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, v := range data {
+		m.putWithoutLock(k, v, m.ttl, now)
+	}
+}
+
+// PutAll batch sets key-values to the map, which is alias of Puts.
+func (m *ExpiringMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the map is locked.
+func (m *ExpiringMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, entry := range entries {
+		m.putWithoutLock(entry.Key, entry.Value, m.ttl, now)
+	}
+}
+
+// putWithoutLock sets key-value which expires after `ttl` from `now`, and schedules the background sweep.
+func (m *ExpiringMap[K, V]) putWithoutLock(key K, value V, ttl time.Duration, now time.Time) {
+	entry := expiringMapEntry[V]{value: value}
+	if ttl > 0 {
+		entry.expireAt = now.Add(ttl)
+	}
+	m.data[key] = entry
+	if ttl > 0 {
+		m.pushExpiryWithoutLock(key, entry.expireAt)
+	}
+}
+
+// Search searches the map with given `key`.
+// Second return parameter `found` is true if key was found and not expired, otherwise false.
+func (m *ExpiringMap[K, V]) Search(key K) (value V, found bool) {
+	m.mu.RLock()
+	entry, ok := m.data[key]
+	m.mu.RUnlock()
+	if !ok {
+		return
+	}
+	if entry.expired(time.Now()) {
+		m.evict(key)
+		return
+	}
+	return entry.value, true
+}
+
+// Get returns the value by given `key`, or empty value of type V if the key is not found or expired.
+func (m *ExpiringMap[K, V]) Get(key K) (value V) {
+	value, _ = m.Search(key)
+	return
+}
+
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *ExpiringMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *ExpiringMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
+// TTL returns the remaining time to live of `key`, which is zero if the entry never expires.
+// Second return parameter `found` is true if key was found and not expired, otherwise false.
+func (m *ExpiringMap[K, V]) TTL(key K) (ttl time.Duration, found bool) {
+	m.mu.RLock()
+	entry, ok := m.data[key]
+	m.mu.RUnlock()
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if entry.expired(now) {
+		m.evict(key)
+		return
+	}
+	if !entry.expireAt.IsZero() {
+		ttl = entry.expireAt.Sub(now)
+	}
+	return ttl, true
+}
+
+// GetOrPut returns the value by key,
+// or sets value with given `value` if it does not exist or is expired, and then returns this value.
+func (m *ExpiringMap[K, V]) GetOrPut(key K, value V) V {
+	v, _ := m.getOrPutFunc(key, func() V { return value })
+	return v
+}
+
+// GetOrPutFunc returns the value by key,
+// or sets value with returned value of callback function `f` if it does not exist or is expired,
+// and then returns this value.
+// Note that `f` is called with the map locked.
+func (m *ExpiringMap[K, V]) GetOrPutFunc(key K, f func() V) V {
+	v, _ := m.getOrPutFunc(key, f)
+	return v
+}
+
+// PutIfAbsent sets `value` to the map if the `key` does not exist or is expired, and then returns true.
+// It returns false if `key` exists, and `value` would be ignored.
+func (m *ExpiringMap[K, V]) PutIfAbsent(key K, value V) bool {
+	_, put := m.getOrPutFunc(key, func() V { return value })
+	return put
+}
+
+// PutIfAbsentFunc sets value with return value of callback function `f` if the `key` does not exist
+// or is expired, and then returns true.
+// It returns false if `key` exists, and `f` would not be called.
+func (m *ExpiringMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
+	_, put := m.getOrPutFunc(key, f)
+	return put
+}
+
+// getOrPutFunc returns the value by key, or sets value with returned value of `f` with the default TTL
+// if it does not exist or is expired, in which case `put` is true.
+func (m *ExpiringMap[K, V]) getOrPutFunc(key K, f func() V) (value V, put bool) {
+	var evicted *expiringMapEntry[V]
+	defer func() {
+		if evicted != nil {
+			m.notifyEvict([]K{key}, []V{evicted.value})
+		}
+	}()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if entry, ok := m.data[key]; ok {
+		if !entry.expired(now) {
+			return entry.value, false
+		}
+		evicted = &entry
+	}
+	value = f()
+	m.putWithoutLock(key, value, m.ttl, now)
+	return value, true
+}
+
+// Remove deletes value from map by given `key`, and return this deleted value.
+// It returns false as `removed` if the key does not exist or is expired.
+func (m *ExpiringMap[K, V]) Remove(key K) (value V, removed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.data[key]
+	if !ok {
+		return
+	}
+	delete(m.data, key)
+	if entry.expired(time.Now()) {
+		return
+	}
+	return entry.value, true
+}
+
+// Removes batch deletes values of the map by keys.
+func (m *ExpiringMap[K, V]) Removes(keys []K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.data, key)
+	}
+}
+
+// ForEach iterates the entries not expired readonly with custom callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *ExpiringMap[K, V]) ForEach(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	now := time.Now()
+	for k, entry := range m.data {
+		if entry.expired(now) {
+			continue
+		}
+		if !f(k, entry.value) {
+			break
+		}
+	}
+}
+
+// Entries returns all key-value pairs of the map as a slice of entries, in the same order as ForEach.
+func (m *ExpiringMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *ExpiringMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *ExpiringMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *ExpiringMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
+// ContainsKey checks whether a key exists and is not expired.
+func (m *ExpiringMap[K, V]) ContainsKey(key K) bool {
+	_, found := m.Search(key)
+	return found
+}
+
+// Size returns the number of the entries not expired, which takes a pass over all the entries.
+func (m *ExpiringMap[K, V]) Size() (size int) {
+	m.ForEach(func(K, V) bool {
+		size++
+		return true
+	})
+	return
+}
+
+// IsEmpty checks whether the map has no entries not expired.
+func (m *ExpiringMap[K, V]) IsEmpty() bool {
+	empty := true
+	m.ForEach(func(K, V) bool {
+		empty = false
+		return false
+	})
+	return empty
+}
+
+// Keys returns the keys of the entries not expired as a slice.
+func (m *ExpiringMap[K, V]) Keys() []K {
+	keys := make([]K, 0)
+	m.ForEach(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values of the entries not expired as a slice.
+func (m *ExpiringMap[K, V]) Values() []V {
+	values := make([]V, 0)
+	m.ForEach(func(_ K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Map returns a copy of the entries not expired as map.
+func (m *ExpiringMap[K, V]) Map() map[K]V {
+	data := make(map[K]V)
+	m.ForEach(func(k K, v V) bool {
+		data[k] = v
+		return true
+	})
+	return data
+}
+
+// MapStrAny returns a copy of the entries not expired as map[string]V.
+func (m *ExpiringMap[K, V]) MapStrAny() map[string]V {
+	data := make(map[string]V)
+	m.ForEach(func(k K, v V) bool {
+		data[gconv.String(k)] = v
+		return true
+	})
+	return data
+}
+
+// Clear deletes all data of the map, it will remake a new underlying data map.
+func (m *ExpiringMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = make(map[K]expiringMapEntry[V])
+	m.expiry = &expiringMapHeap[K]{}
+}
+
+// Replace the data of the map with given `data`, which expire after the default TTL of the map.
+func (m *ExpiringMap[K, V]) Replace(data map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = make(map[K]expiringMapEntry[V], len(data))
+	m.expiry = &expiringMapHeap[K]{}
+	now := time.Now()
+	for k, v := range data {
+		m.putWithoutLock(k, v, m.ttl, now)
+	}
+}
+
+// ReplaceAll replaces the value of every entry not expired with the result of `f` called with the key and
+// its value, all done under one lock. The entries keep their expiration time.
+func (m *ExpiringMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, entry := range m.data {
+		if entry.expired(now) {
+			continue
+		}
+		entry.value = f(k, entry.value)
+		m.data[k] = entry
+	}
+}
+
+// Clone returns a new expiring map with the entries not expired, which keep their expiration time,
+// and the same default TTL and options.
+// The parameter `safe` is ignored, as ExpiringMap is always concurrent-safe.
+func (m *ExpiringMap[K, V]) Clone(safe ...bool) Map[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := NewExpiringMap[K, V](m.ttl, ExpiringMapOptions[K, V]{
+		OnEvict:       m.onEvict,
+		SweepInterval: m.sweepInterval,
+		SweepLimit:    m.sweepLimit,
+	})
+	newMap.mu.Lock()
+	defer newMap.mu.Unlock()
+	now := time.Now()
+	for k, entry := range m.data {
+		if entry.expired(now) {
+			continue
+		}
+		newMap.data[k] = entry
+		if !entry.expireAt.IsZero() {
+			newMap.pushExpiryWithoutLock(k, entry.expireAt)
+		}
+	}
+	return newMap
+}
+
+// Close stops the background sweep of the map, after which the expired entries are only removed on access.
+func (m *ExpiringMap[K, V]) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	if m.sweeper != nil {
+		m.sweeper.Close()
+		m.sweeper = nil
+	}
+}
+
+// evict removes `key` if it's expired, and calls the eviction callback.
+func (m *ExpiringMap[K, V]) evict(key K) {
+	m.mu.Lock()
+	entry, ok := m.data[key]
+	if ok && entry.expired(time.Now()) {
+		delete(m.data, key)
+	} else {
+		ok = false
+	}
+	m.mu.Unlock()
+	if ok {
+		m.notifyEvict([]K{key}, []V{entry.value})
+	}
+}
+
+// pushExpiryWithoutLock adds the expiration of the entry of `key` to the heap, and schedules the background sweep.
+// The heap is rebuilt from the entries if the stale expirations outnumber them, so that it doesn't grow
+// with the entries overwritten or removed.
+func (m *ExpiringMap[K, V]) pushExpiryWithoutLock(key K, expireAt time.Time) {
+	heap.Push(m.expiry, expiringMapItem[K]{key: key, expireAt: expireAt})
+	if m.expiry.Len() > 2*len(m.data)+expiringMapCompactSlack {
+		array := m.expiry.array[:0]
+		for k, entry := range m.data {
+			if !entry.expireAt.IsZero() {
+				array = append(array, expiringMapItem[K]{key: k, expireAt: entry.expireAt})
+			}
+		}
+		clear(m.expiry.array[len(array):])
+		m.expiry.array = array
+		heap.Init(m.expiry)
+	}
+	m.scheduleSweepWithoutLock()
+}
+
+// scheduleSweepWithoutLock schedules the background sweep if it's enabled and not scheduled yet.
+func (m *ExpiringMap[K, V]) scheduleSweepWithoutLock() {
+	if m.sweepInterval > 0 && m.sweeper == nil && !m.closed {
+		m.sweeper = gtimer.AddSingleton(context.Background(), m.sweepInterval, m.sweep)
+	}
+}
+
+// sweep removes the expired entries in the order of their expiration, at most `sweepLimit` expirations
+// each time, and stops the background sweep if no entry is to expire any more.
+func (m *ExpiringMap[K, V]) sweep(ctx context.Context) error {
+	var (
+		keys   []K
+		values []V
+	)
+	m.mu.Lock()
+	now := time.Now()
+	for i := 0; i < m.sweepLimit && m.expiry.Len() > 0; i++ {
+		item := m.expiry.array[0]
+		if now.Before(item.expireAt) {
+			break
+		}
+		heap.Pop(m.expiry)
+		// The stale expirations are skipped, whose entries are overwritten or removed.
+		if entry, ok := m.data[item.key]; ok && entry.expireAt.Equal(item.expireAt) {
+			delete(m.data, item.key)
+			keys = append(keys, item.key)
+			values = append(values, entry.value)
+		}
+	}
+	if m.expiry.Len() == 0 && m.sweeper != nil {
+		m.sweeper.Close()
+		m.sweeper = nil
+	}
+	m.mu.Unlock()
+	m.notifyEvict(keys, values)
+	return nil
+}
+
+// notifyEvict calls the eviction callback with the evicted entries, which must be called without lock.
+func (m *ExpiringMap[K, V]) notifyEvict(keys []K, values []V) {
+	if m.onEvict == nil {
+		return
+	}
+	for i, k := range keys {
+		m.onEvict(k, values[i])
+	}
+}
+
+// String returns the entries not expired as a string.
+func (m *ExpiringMap[K, V]) String() string {
+	if m == nil {
+		return ""
+	}
+	b, _ := m.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *ExpiringMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *ExpiringMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *ExpiringMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal, which marshals the entries not expired.
+func (m *ExpiringMap[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
+}
@@ -0,0 +1,241 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/gtype"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestExpiringMap_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewExpiringMap[string, int](time.Hour)
+		defer m.Close()
+		var _ g.Map[string, int] = m
+		var _ g.ExtendedMap[string, int] = m
+		m.Put("a", 1)
+		m.Puts(map[string]int{"b": 2, "c": 3})
+		t.Assert(m.Get("a"), 1)
+		t.Assert(m.Size(), 3)
+		t.Assert(m.IsEmpty(), false)
+		t.Assert(m.ContainsKey("b"), true)
+		t.Assert(m.ContainsKey("d"), false)
+		v, found := m.Search("d")
+		t.Assert(v, 0)
+		t.Assert(found, false)
+
+		t.Assert(m.PutIfAbsent("a", 10), false)
+		t.Assert(m.PutIfAbsent("d", 4), true)
+		t.Assert(m.GetOrPut("d", 40), 4)
+		t.Assert(m.GetOrPutFunc("e", func() int { return 5 }), 5)
+		t.Assert(m.PutIfAbsentFunc("e", func() int { return 50 }), false)
+
+		keys := m.Keys()
+		slices.Sort(keys)
+		t.Assert(keys, []string{"a", "b", "c", "d", "e"})
+		values := m.Values()
+		slices.Sort(values)
+		t.Assert(values, []int{1, 2, 3, 4, 5})
+		t.Assert(m.Map(), map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5})
+
+		v, removed := m.Remove("a")
+		t.Assert(v, 1)
+		t.Assert(removed, true)
+		_, removed = m.Remove("a")
+		t.Assert(removed, false)
+		m.Removes([]string{"b", "c"})
+		t.Assert(m.Size(), 2)
+
+		m.Replace(map[string]int{"x": 9})
+		t.Assert(m.Map(), map[string]int{"x": 9})
+		t.Assert(m.Clone().Map(), map[string]int{"x": 9})
+		m.Clear()
+		t.Assert(m.IsEmpty(), true)
+	})
+}
+
+func TestExpiringMap_Expire(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewExpiringMap[string, int](50*time.Millisecond, g.ExpiringMapOptions[string, int]{
+			SweepInterval: -1,
+		})
+		m.Put("a", 1)
+		m.PutWithTTL("b", 2, time.Hour)
+		m.PutWithTTL("c", 3, 0)
+		ttl, found := m.TTL("a")
+		t.Assert(found, true)
+		t.Assert(ttl > 0 && ttl <= 50*time.Millisecond, true)
+		ttl, found = m.TTL("c")
+		t.Assert(found, true)
+		t.Assert(ttl == 0, true)
+
+		time.Sleep(100 * time.Millisecond)
+		t.Assert(m.ContainsKey("a"), false)
+		t.Assert(m.Get("b"), 2)
+		t.Assert(m.Get("c"), 3)
+		t.Assert(m.Size(), 2)
+		_, found = m.TTL("a")
+		t.Assert(found, false)
+
+		m.Put("d", 4)
+		time.Sleep(100 * time.Millisecond)
+		t.Assert(m.PutIfAbsent("d", 40), true)
+		t.Assert(m.Get("d"), 40)
+		_, removed := m.Remove("d")
+		t.Assert(removed, true)
+	})
+}
+
+func TestExpiringMap_OnEvict(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			mu      sync.Mutex
+			evicted = make(map[string]int)
+		)
+		m := g.NewExpiringMap[string, int](30*time.Millisecond, g.ExpiringMapOptions[string, int]{
+			OnEvict: func(key string, value int) {
+				mu.Lock()
+				defer mu.Unlock()
+				evicted[key] = value
+			},
+			SweepInterval: 20 * time.Millisecond,
+		})
+		defer m.Close()
+		m.Put("a", 1)
+		m.Put("b", 2)
+		m.PutWithTTL("c", 3, time.Hour)
+		m.Remove("b")
+
+		time.Sleep(250 * time.Millisecond)
+		mu.Lock()
+		t.Assert(evicted, map[string]int{"a": 1})
+		mu.Unlock()
+		t.Assert(m.Keys(), []string{"c"})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var evicted []string
+		m := g.NewExpiringMap[string, int](30*time.Millisecond, g.ExpiringMapOptions[string, int]{
+			OnEvict: func(key string, value int) {
+				evicted = append(evicted, key)
+			},
+		})
+		m.Close()
+		m.Put("a", 1)
+		time.Sleep(60 * time.Millisecond)
+		t.Assert(evicted, nil)
+		t.Assert(m.Get("a"), 0)
+		t.Assert(evicted, []string{"a"})
+	})
+}
+
+func TestExpiringMap_Sweep(t *testing.T) {
+	// The expired entries are swept in the order of their expiration, at most SweepLimit each time.
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			mu      sync.Mutex
+			evicted []int
+		)
+		m := g.NewExpiringMap[int, int](time.Hour, g.ExpiringMapOptions[int, int]{
+			OnEvict: func(key int, value int) {
+				mu.Lock()
+				defer mu.Unlock()
+				evicted = append(evicted, key)
+			},
+			SweepInterval: 10 * time.Millisecond,
+			SweepLimit:    3,
+		})
+		defer m.Close()
+		for i := 9; i >= 0; i-- {
+			m.PutWithTTL(i, i, time.Duration(i+1)*time.Millisecond)
+		}
+		m.Put(10, 10)
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		t.Assert(len(evicted) <= 3, true)
+		mu.Unlock()
+
+		time.Sleep(time.Second)
+		mu.Lock()
+		t.Assert(evicted, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+		mu.Unlock()
+		t.Assert(m.Keys(), []int{10})
+	})
+	// The expirations of the entries overwritten or removed are skipped.
+	gtest.C(t, func(t *gtest.T) {
+		var evicted = gtype.NewInt()
+		m := g.NewExpiringMap[string, int](10*time.Millisecond, g.ExpiringMapOptions[string, int]{
+			OnEvict: func(key string, value int) {
+				evicted.Add(1)
+			},
+			SweepInterval: 10 * time.Millisecond,
+		})
+		defer m.Close()
+		m.Put("a", 1)
+		m.PutWithTTL("a", 2, time.Hour)
+		m.Put("b", 1)
+		m.Remove("b")
+		m.Put("c", 1)
+		m.Put("c", 3)
+		for i := 0; i < 1000; i++ {
+			m.PutWithTTL("d", i, time.Hour)
+		}
+		time.Sleep(300 * time.Millisecond)
+		t.Assert(evicted.Val(), 1)
+		t.Assert(m.Map(), map[string]int{"a": 2, "d": 999})
+	})
+}
+
+func TestExpiringMap_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			m = g.NewExpiringMap[int, int](10*time.Millisecond, g.ExpiringMapOptions[int, int]{
+				SweepInterval: 5 * time.Millisecond,
+			})
+			wg sync.WaitGroup
+		)
+		defer m.Close()
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					m.Put(j%50, j)
+					m.Get((j + i) % 50)
+					m.GetOrPut(j%70, j)
+				}
+			}()
+		}
+		wg.Wait()
+		t.Assert(m.Size() <= 70, true)
+	})
+}
+
+func TestExpiringMap_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewExpiringMap[string, int](time.Hour)
+		defer m.Close()
+		m.Put("a", 1)
+		t.Assert(m.String(), `{"a":1}`)
+		t.Assert(fmt.Sprint(m), m.String())
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"a":1}`)
+
+		var nilMap *g.ExpiringMap[string, int]
+		t.Assert(nilMap.String(), "")
+	})
+}
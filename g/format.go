@@ -0,0 +1,203 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+// FormatOptions is the options for the pretty form of containers printed by fmt with verb `%+v`.
+type FormatOptions struct {
+	// MaxElements is the max number of elements printed for each container,
+	// and the rest are summarized as a count. It is unlimited if it is <= 0.
+	MaxElements int
+
+	// MaxDepth is the max depth of nested containers to be expanded,
+	// and the deeper containers are collapsed. It is unlimited if it is <= 0.
+	MaxDepth int
+
+	// Indent is the indentation for each depth, which is two spaces if it is empty.
+	Indent string
+}
+
+// formatOptions holds the global FormatOptions, which is nil for the default options.
+var formatOptions atomic.Pointer[FormatOptions]
+
+// SetFormatOptions sets the global options for the pretty form of containers printed with `%+v`.
+func SetFormatOptions(options FormatOptions) {
+	if options == (FormatOptions{}) {
+		formatOptions.Store(nil)
+		return
+	}
+	formatOptions.Store(&options)
+}
+
+// GetFormatOptions returns the global options for the pretty form of containers printed with `%+v`.
+func GetFormatOptions() FormatOptions {
+	if options := formatOptions.Load(); options != nil {
+		return *options
+	}
+	return FormatOptions{}
+}
+
+// formatEntry is an element of a container to be formatted, and `key` is used only for maps.
+type formatEntry struct {
+	key   interface{}
+	value interface{}
+}
+
+// formattable is implemented by containers supporting fmt.Formatter.
+type formattable interface {
+	// formatEntries returns at most `limit` entries of the container, or all entries if `limit` <= 0,
+	// whether the container is a map, and the total number of entries in the container.
+	formatEntries(limit int) (entries []formatEntry, isMap bool, size int)
+}
+
+// formatContainer implements fmt.Formatter for container `c`.
+//
+// The verbs `%v` and `%s` print the compact form returned by String of the container, like `[1,2,3]`,
+// which is the same as before the containers implement fmt.Formatter, so that the existing outputs
+// of fmt.Print and fmt.Println are not changed.
+// The verb `%+v` prints the pretty and indented form limited by FormatOptions,
+// the verb `%#v` prints the same form annotated with type, length and capacity of the containers,
+// and the other verbs print the form like golang slice and map, of which the elements are formatted
+// with the same verb and flags, like `[001 002 003]` for `%03d`.
+func formatContainer(s fmt.State, verb rune, c formattable) {
+	if isNilFormattable(c) {
+		_, _ = fmt.Fprint(s, "<nil>")
+		return
+	}
+	switch {
+	case verb == 's' || (verb == 'v' && !s.Flag('+') && !s.Flag('#')):
+		if stringer, ok := c.(fmt.Stringer); ok {
+			_, _ = fmt.Fprint(s, stringer.String())
+			return
+		}
+	case verb == 'v':
+		buffer := bytes.NewBuffer(nil)
+		formatPretty(buffer, c, GetFormatOptions(), s.Flag('#'), 0)
+		_, _ = s.Write(buffer.Bytes())
+		return
+	}
+	formatCompact(s, verb, c)
+}
+
+// formatCompact writes the form of container `c` like golang slice and map to `s`.
+func formatCompact(s fmt.State, verb rune, c formattable) {
+	var (
+		entries, isMap, _ = c.formatEntries(0)
+		elementFormat     = fmt.FormatString(s, verb)
+		buffer            = bytes.NewBuffer(nil)
+	)
+	if isMap {
+		buffer.WriteString("map")
+	}
+	buffer.WriteByte('[')
+	for i, entry := range entries {
+		if i > 0 {
+			buffer.WriteByte(' ')
+		}
+		if isMap {
+			_, _ = fmt.Fprintf(buffer, elementFormat, entry.key)
+			buffer.WriteByte(':')
+		}
+		_, _ = fmt.Fprintf(buffer, elementFormat, entry.value)
+	}
+	buffer.WriteByte(']')
+	_, _ = s.Write(buffer.Bytes())
+}
+
+// formatPretty writes the pretty form of container `c` at `depth` to `buffer`.
+// If `typed` is true, each container is prefixed with its type, length and capacity if any,
+// and the elements are printed in Go-syntax representation.
+func formatPretty(buffer *bytes.Buffer, c formattable, options FormatOptions, typed bool, depth int) {
+	entries, isMap, size := c.formatEntries(options.MaxElements)
+	keyFormat, leafFormat := "%v: ", "%+v"
+	if typed {
+		keyFormat, leafFormat = "%#v: ", "%#v"
+		_, _ = fmt.Fprintf(buffer, "%T{len: %d", c, size)
+		if capacity, ok := c.(interface{ Cap() int }); ok {
+			_, _ = fmt.Fprintf(buffer, ", cap: %d", capacity.Cap())
+		}
+		buffer.WriteString("} ")
+	} else if isMap {
+		buffer.WriteString("map")
+	}
+	if size == 0 {
+		buffer.WriteString("[]")
+		return
+	}
+	if options.MaxDepth > 0 && depth >= options.MaxDepth {
+		_, _ = fmt.Fprintf(buffer, "[...%d elements]", size)
+		return
+	}
+	indent := options.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	buffer.WriteString("[\n")
+	for _, entry := range entries {
+		buffer.WriteString(strings.Repeat(indent, depth+1))
+		if isMap {
+			_, _ = fmt.Fprintf(buffer, keyFormat, entry.key)
+		}
+		if nested, ok := entry.value.(formattable); ok && !isNilFormattable(nested) {
+			formatPretty(buffer, nested, options, typed, depth+1)
+		} else {
+			_, _ = fmt.Fprintf(buffer, leafFormat, entry.value)
+		}
+		buffer.WriteByte('\n')
+	}
+	if size > len(entries) {
+		buffer.WriteString(strings.Repeat(indent, depth+1))
+		_, _ = fmt.Fprintf(buffer, "...%d more\n", size-len(entries))
+	}
+	buffer.WriteString(strings.Repeat(indent, depth))
+	buffer.WriteByte(']')
+}
+
+// isNilFormattable checks whether `c` is a nil pointer of container.
+func isNilFormattable(c formattable) bool {
+	v := reflect.ValueOf(c)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// collectFormatValues collects at most `limit` values by `forEach`, or all values if `limit` <= 0.
+func collectFormatValues[T any](forEach func(f func(v T) bool), size, limit int) []formatEntry {
+	if limit <= 0 || limit > size {
+		limit = size
+	}
+	entries := make([]formatEntry, 0, limit)
+	forEach(func(v T) bool {
+		if len(entries) >= limit {
+			return false
+		}
+		entries = append(entries, formatEntry{value: v})
+		return true
+	})
+	return entries
+}
+
+// collectFormatPairs collects at most `limit` key-value pairs by `forEach`, or all pairs if `limit` <= 0.
+func collectFormatPairs[K, V any](forEach func(f func(k K, v V) bool), size, limit int) []formatEntry {
+	if limit <= 0 || limit > size {
+		limit = size
+	}
+	entries := make([]formatEntry, 0, limit)
+	forEach(func(k K, v V) bool {
+		if len(entries) >= limit {
+			return false
+		}
+		entries = append(entries, formatEntry{key: k, value: v})
+		return true
+	})
+	return entries
+}
@@ -0,0 +1,96 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/utils/comparators"
+)
+
+func TestFormat_Compact(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3})
+		t.Assert(fmt.Sprintf("%v", array), "[1,2,3]")
+		t.Assert(fmt.Sprint(array), array.String())
+		t.Assert(fmt.Sprintf("%s", array), array.String())
+		t.Assert(fmt.Sprintf("%d", array), "[1 2 3]")
+
+		list := g.NewLinkedListFrom([]string{"a", "b"})
+		t.Assert(fmt.Sprintf("%v", list), "[a,b]")
+		t.Assert(fmt.Sprintf("%q", list), `["a" "b"]`)
+
+		set := g.NewTreeSetFrom([]int{3, 1, 2}, comparators.ComparatorInt)
+		t.Assert(fmt.Sprintf("%v", set), "[1,2,3]")
+		t.Assert(fmt.Sprintf("%03d", set), "[001 002 003]")
+
+		m := g.NewTreeMapFrom[string, int](comparators.ComparatorString, map[string]int{"b": 2, "a": 1})
+		t.Assert(fmt.Sprintf("%v", m), m.String())
+
+		var nilArray *g.ArrayList[int]
+		t.Assert(fmt.Sprintf("%v", nilArray), "<nil>")
+		t.Assert(fmt.Sprintf("%v", g.NewHashMap[int, int]()), "{}")
+		t.Assert(fmt.Sprintf("%d", g.NewHashMap[int, int]()), "map[]")
+	})
+}
+
+func TestFormat_Nested(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMap[string, *g.ArrayList[int]]()
+		m.Put("x", g.NewArrayListFrom([]int{1, 2}))
+		m.Put("y", g.NewArrayListFrom([]int{3}))
+		t.Assert(fmt.Sprintf("%v", m), `{"x":[1,2],"y":[3]}`)
+		t.Assert(fmt.Sprintf("%+v", m), "map[\n  x: [\n    1\n    2\n  ]\n  y: [\n    3\n  ]\n]")
+	})
+}
+
+func TestFormat_Options(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		defer g.SetFormatOptions(g.FormatOptions{})
+		g.SetFormatOptions(g.FormatOptions{MaxElements: 2, MaxDepth: 1, Indent: "\t"})
+		t.Assert(g.GetFormatOptions(), g.FormatOptions{MaxElements: 2, MaxDepth: 1, Indent: "\t"})
+
+		array := g.NewArrayListFrom([]int{1, 2, 3, 4})
+		t.Assert(fmt.Sprintf("%+v", array), "[\n\t1\n\t2\n\t...2 more\n]")
+		// The compact form is not limited.
+		t.Assert(fmt.Sprintf("%v", array), "[1,2,3,4]")
+
+		nested := g.NewArrayListFrom([]*g.LinkedList[int]{
+			g.NewLinkedListFrom([]int{1, 2, 3}),
+			g.NewLinkedList[int](),
+		})
+		t.Assert(fmt.Sprintf("%+v", nested), "[\n\t[...3 elements]\n\t[]\n]")
+
+		g.SetFormatOptions(g.FormatOptions{})
+		t.Assert(g.GetFormatOptions(), g.FormatOptions{})
+		t.Assert(fmt.Sprintf("%+v", nested), "[\n  [\n    1\n    2\n    3\n  ]\n  []\n]")
+	})
+}
+
+func TestFormat_GoSyntax(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]string{"a", "b"})
+		array.Reserve(2)
+		t.Assert(
+			fmt.Sprintf("%#v", array),
+			fmt.Sprintf("*g.ArrayList[string]{len: 2, cap: %d} [\n  \"a\"\n  \"b\"\n]", array.Cap()),
+		)
+		t.Assert(fmt.Sprintf("%#v", g.NewLinkedList[int]()), "*g.LinkedList[int]{len: 0} []")
+
+		m := g.NewHashMap[string, *g.LinkedList[int]]()
+		m.Put("x", g.NewLinkedListFrom([]int{1}))
+		t.Assert(
+			fmt.Sprintf("%#v", m),
+			"*g.HashMap[string,*github.com/wesleywu/gcontainer/g.LinkedList[int]]{len: 1} [\n  \"x\": *g.LinkedList[int]{len: 1} [\n    1\n  ]\n]",
+		)
+	})
+}
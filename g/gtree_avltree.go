@@ -9,18 +9,20 @@ package g
 import (
 	"bytes"
 	"fmt"
+	"hash/maphash"
 
-	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/rwmutex"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
 // AVLTree holds elements of the AVL tree.
 type AVLTree[K comparable, V any] struct {
-	mu         rwmutex.RWMutex
-	root       *AVLTreeNode[K, V]
-	comparator func(v1, v2 K) int
-	size       int
+	mu          rwmutex.RWMutex
+	root        *AVLTreeNode[K, V]
+	comparator  func(v1, v2 K) int
+	size        int
+	arena       *nodeArena[AVLTreeNode[K, V]] // Optional node allocator, nil if disabled.
+	jsonOptions *JSONOptions                  // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // AVLTreeNode is a single element within the tree.
@@ -84,6 +86,22 @@ func (tree *AVLTree[K, V]) Puts(data map[K]V) {
 	}
 }
 
+// PutAll batch sets key-values to the tree, which is alias of Puts.
+func (tree *AVLTree[K, V]) PutAll(data map[K]V) {
+	tree.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the tree
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the tree is locked.
+func (tree *AVLTree[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	for _, entry := range entries {
+		tree.put(entry.Key, entry.Value, nil, &tree.root)
+	}
+}
+
 // Search searches the tree with given `key`.
 // Second return parameter `found` is true if key was found, otherwise false.
 func (tree *AVLTree[K, V]) Search(key K) (value V, found bool) {
@@ -119,6 +137,24 @@ func (tree *AVLTree[K, V]) Get(key K) (value V) {
 	return
 }
 
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (tree *AVLTree[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := tree.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (tree *AVLTree[K, V]) MustGet(key K) V {
+	value, found := tree.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
 // doSetWithLockCheck checks whether value of the key exists with mutex.Lock,
 // if not exists, set value to the map with given `key`,
 // or else just return the existing value.
@@ -214,7 +250,8 @@ func (tree *AVLTree[K, V]) ContainsKey(key K) bool {
 func (tree *AVLTree[K, V]) Remove(key K) (value V, removed bool) {
 	tree.mu.Lock()
 	defer tree.mu.Unlock()
-	return tree.remove(key, &tree.root)
+	value, removed, _ = tree.remove(key, &tree.root)
+	return
 }
 
 // Removes batch deletes values of the tree by `keys`.
@@ -358,6 +395,38 @@ func (tree *AVLTree[K, V]) Clear() {
 	defer tree.mu.Unlock()
 	tree.root = nil
 	tree.size = 0
+	if tree.arena != nil {
+		tree.arena.drop()
+	}
+}
+
+// SetNodeArena enables allocating nodes from an arena, which allocates `chunkSize` nodes at a time
+// instead of one by one. It reduces the allocations of trees holding a huge number of nodes.
+// The arena is disabled if `chunkSize` <= 0.
+//
+// The arena is meant for the trees built and then dropped by Reset as a whole. The memory of the removed
+// nodes is not reused until Reset, so that the nodes previously returned by the tree, like Left or Floor,
+// are never reused for other keys, which means the chunks keep growing with the insertions and removals
+// in between, and a node still in the tree keeps its whole chunk alive.
+// Use Clear or Replace instead of Reset, or don't enable the arena, for long-lived trees with churn.
+func (tree *AVLTree[K, V]) SetNodeArena(chunkSize int) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.arena = newNodeArena[AVLTreeNode[K, V]](chunkSize)
+}
+
+// Reset removes all nodes from the tree. If the node arena is enabled, all nodes are returned
+// to the arena at once and their memory is reused by later insertions, or else it is the same as Clear.
+//
+// Note that any node previously returned by the tree, like Left or Floor, must not be used after Reset.
+func (tree *AVLTree[K, V]) Reset() {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.root = nil
+	tree.size = 0
+	if tree.arena != nil {
+		tree.arena.reset()
+	}
 }
 
 // Replace the data of the tree with given `data`.
@@ -366,11 +435,24 @@ func (tree *AVLTree[K, V]) Replace(data map[K]V) {
 	defer tree.mu.Unlock()
 	tree.root = nil
 	tree.size = 0
+	if tree.arena != nil {
+		tree.arena.drop()
+	}
 	for key, value := range data {
 		tree.put(key, value, nil, &tree.root)
 	}
 }
 
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// in ascending order of the keys and under one lock.
+func (tree *AVLTree[K, V]) ReplaceAll(f func(key K, value V) V) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	for node := tree.bottom(0); node != nil; node = node.Next() {
+		node.value = f(node.key, node.value)
+	}
+}
+
 // String returns a string representation of container
 func (tree *AVLTree[K, V]) String() string {
 	if tree == nil {
@@ -385,6 +467,24 @@ func (tree *AVLTree[K, V]) String() string {
 	return str
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (tree *AVLTree[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, tree)
+}
+
+// formatEntries implements the interface formattable.
+func (tree *AVLTree[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = tree.Size()
+	return collectFormatPairs(tree.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (tree *AVLTree[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, tree.ForEach)
+}
+
 // Print prints the tree to stdout.
 func (tree *AVLTree[K, V]) Print() {
 	fmt.Println(tree.String())
@@ -415,6 +515,34 @@ func (tree *AVLTree[K, V]) ForEach(f func(key K, value V) bool) {
 	tree.ForEachAsc(f)
 }
 
+// Entries returns all key-value pairs of the map as a slice of entries, in ascending order of the keys.
+func (tree *AVLTree[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(tree.ForEach, tree.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (tree *AVLTree[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	tree.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (tree *AVLTree[K, V]) IterKeys(f func(key K) bool) {
+	tree.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (tree *AVLTree[K, V]) IterValues(f func(value V) bool) {
+	tree.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
 // IteratorFrom is alias of IteratorAscFrom.
 func (tree *AVLTree[K, V]) IteratorFrom(key K, match bool, f func(key K, value V) bool) {
 	tree.IteratorAscFrom(key, match, f)
@@ -492,7 +620,15 @@ func (tree *AVLTree[K, V]) put(key K, value V, p *AVLTreeNode[K, V], qp **AVLTre
 	q := *qp
 	if q == nil {
 		tree.size++
-		*qp = &AVLTreeNode[K, V]{key: key, value: value, parent: p}
+		if tree.arena == nil {
+			*qp = &AVLTreeNode[K, V]{key: key, value: value, parent: p}
+		} else {
+			node := tree.arena.alloc()
+			node.key = key
+			node.value = value
+			node.parent = p
+			*qp = node
+		}
 		return true
 	}
 
@@ -515,7 +651,9 @@ func (tree *AVLTree[K, V]) put(key K, value V, p *AVLTreeNode[K, V], qp **AVLTre
 	return false
 }
 
-func (tree *AVLTree[K, V]) remove(key K, qp **AVLTreeNode[K, V]) (value V, fix bool) {
+// remove deletes the node by `key` from the subtree `qp`.
+// The returned `fix` reports whether the height of the subtree is decreased.
+func (tree *AVLTree[K, V]) remove(key K, qp **AVLTreeNode[K, V]) (value V, removed bool, fix bool) {
 	q := *qp
 	if q == nil {
 		return
@@ -525,19 +663,18 @@ func (tree *AVLTree[K, V]) remove(key K, qp **AVLTreeNode[K, V]) (value V, fix b
 	if c == 0 {
 		tree.size--
 		value = q.value
-		fix = true
 		if q.children[1] == nil {
 			if q.children[0] != nil {
 				q.children[0].parent = q.parent
 			}
 			*qp = q.children[0]
-			return
+			return value, true, true
 		}
-		if removeMin(&q.children[1], &q.key, &q.value) {
-			removeFix(-1, qp)
-			return value, true
+		fix = removeMin(&q.children[1], &q.key, &q.value)
+		if fix {
+			fix = removeFix(-1, qp)
 		}
-		return
+		return value, true, fix
 	}
 
 	if c < 0 {
@@ -546,12 +683,11 @@ func (tree *AVLTree[K, V]) remove(key K, qp **AVLTreeNode[K, V]) (value V, fix b
 		c = 1
 	}
 	a := (c + 1) / 2
-	value, fix = tree.remove(key, &q.children[a])
+	value, removed, fix = tree.remove(key, &q.children[a])
 	if fix {
-		removeFix(int8(-c), qp)
-		return value, true
+		fix = removeFix(int8(-c), qp)
 	}
-	return value, false
+	return value, removed, fix
 }
 
 func removeMin[K comparable, V any](qp **AVLTreeNode[K, V], minKey *K, minVal *V) bool {
@@ -737,15 +873,25 @@ func output[K comparable, V any](node *AVLTreeNode[K, V], prefix string, isTail
 	}
 }
 
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (tree *AVLTree[K, V]) SetMarshalOptions(options JSONOptions) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (tree AVLTree[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 	if tree.root == nil {
 		return []byte("null"), nil
 	}
+	tree.mu.RLock()
+	options := tree.jsonOptions
+	tree.mu.RUnlock()
 	buffer := bytes.NewBuffer(nil)
 	buffer.WriteByte('{')
 	tree.ForEach(func(key K, value V) bool {
-		valueBytes, valueJsonErr := json.Marshal(value)
+		valueBytes, valueJsonErr := marshalJSONValue(options, value)
 		if valueJsonErr != nil {
 			err = valueJsonErr
 			return false
@@ -757,6 +903,9 @@ func (tree AVLTree[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 		return true
 	})
 	buffer.WriteByte('}')
+	if err != nil {
+		return nil, err
+	}
 	return buffer.Bytes(), nil
 }
 
@@ -9,21 +9,24 @@ package g
 import (
 	"bytes"
 	"fmt"
+	"hash/maphash"
 	"log"
 	"strings"
+	"unsafe"
 
-	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/rwmutex"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
 // BTree holds elements of the B-tree.
 type BTree[K comparable, V comparable] struct {
-	mu         rwmutex.RWMutex
-	root       *BTreeNode[K, V]
-	comparator func(v1, v2 K) int
-	size       int // Total number of keys in the tree
-	m          int // order (maximum number of children)
+	mu          rwmutex.RWMutex
+	root        *BTreeNode[K, V]
+	comparator  func(v1, v2 K) int
+	size        int                          // Total number of keys in the tree
+	m           int                          // order (maximum number of children)
+	arena       *nodeArena[BTreeEntry[K, V]] // Optional entry allocator, nil if disabled.
+	jsonOptions *JSONOptions                 // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // BTreeNode is a single element within the tree.
@@ -39,10 +42,24 @@ type BTreeEntry[K comparable, V comparable] struct {
 	value V
 }
 
+const (
+	// btreeAutoNodeBytes is the approximate size of entries per node targeted by NewBTreeAuto,
+	// which is 16 cache lines of 64 bytes.
+	btreeAutoNodeBytes = 1024
+	// btreeAutoMinOrder and btreeAutoMaxOrder bound the order selected by NewBTreeAuto.
+	btreeAutoMinOrder = 4
+	btreeAutoMaxOrder = 128
+)
+
 // NewBTree instantiates a B-tree with `m` (maximum number of children) and a custom key comparators.
 // The parameter `safe` is used to specify whether using tree in concurrent-safety,
 // which is false in default.
 // Note that the `m` must be greater or equal than 3, or else it panics.
+//
+// The order `m` is the tuning knob of the tree. A larger order makes the tree shallower with fewer
+// node allocations, at the cost of more comparisons and longer copies when a node is split or merged.
+// Orders from 16 to 64 usually suit small keys like integers, and smaller orders suit big keys
+// and values. Use NewBTreeAuto or BTreeOrderFor if not sure.
 func NewBTree[K comparable, V comparable](m int, comparator func(v1, v2 K) int, safe ...bool) *BTree[K, V] {
 	if m < 3 {
 		panic("Invalid order, should be at least 3")
@@ -65,6 +82,39 @@ func NewBTreeFrom[K comparable, V comparable](m int, comparator func(v1, v2 K) i
 	return tree
 }
 
+// NewBTreeAuto instantiates a B-tree with a custom key comparators, of which the order is selected by
+// BTreeOrderFor with the in-memory size of types K and V.
+// Note that the size of the data referenced by strings, slices and pointers is not counted.
+// The parameter `safe` is used to specify whether using tree in concurrent-safety,
+// which is false in default.
+func NewBTreeAuto[K comparable, V comparable](comparator func(v1, v2 K) int, safe ...bool) *BTree[K, V] {
+	var (
+		key   K
+		value V
+	)
+	return NewBTree[K, V](BTreeOrderFor(int(unsafe.Sizeof(key)), int(unsafe.Sizeof(value))), comparator, safe...)
+}
+
+// BTreeOrderFor returns the suggested order of BTree for keys and values of approximately
+// `keySize` and `valueSize` bytes, so that the entries of a node take about 16 cache lines.
+func BTreeOrderFor(keySize, valueSize int) int {
+	// Each entry is referenced by a pointer in the node.
+	entrySize := keySize + valueSize + int(unsafe.Sizeof(uintptr(0)))
+	order := btreeAutoNodeBytes / entrySize
+	if order < btreeAutoMinOrder {
+		return btreeAutoMinOrder
+	}
+	if order > btreeAutoMaxOrder {
+		return btreeAutoMaxOrder
+	}
+	return order
+}
+
+// Order returns the order (maximum number of children) of the tree.
+func (tree *BTree[K, V]) Order() int {
+	return tree.m
+}
+
 func (n *BTreeEntry[K, V]) Key() K {
 	return n.key
 }
@@ -90,7 +140,14 @@ func (tree *BTree[K, V]) Put(key K, value V) {
 // doSet inserts key-value pair node into the tree.
 // If key already exists, then its value is updated with the new value.
 func (tree *BTree[K, V]) doSet(key K, value V) {
-	entry := &BTreeEntry[K, V]{key: key, value: value}
+	if tree.arena != nil {
+		// The value of the existing entry is updated in place, so that no arena slot is taken for it.
+		if entry := tree.doSearch(key); entry != nil {
+			entry.value = value
+			return
+		}
+	}
+	entry := tree.newEntry(key, value)
 	if tree.root == nil {
 		tree.root = &BTreeNode[K, V]{Entries: []*BTreeEntry[K, V]{entry}, Children: []*BTreeNode[K, V]{}}
 		tree.size++
@@ -102,6 +159,17 @@ func (tree *BTree[K, V]) doSet(key K, value V) {
 	}
 }
 
+// newEntry creates an entry, which is allocated from the entry arena if it is enabled.
+func (tree *BTree[K, V]) newEntry(key K, value V) *BTreeEntry[K, V] {
+	if tree.arena == nil {
+		return &BTreeEntry[K, V]{key: key, value: value}
+	}
+	entry := tree.arena.alloc()
+	entry.key = key
+	entry.value = value
+	return entry
+}
+
 // Puts batch sets key-values to the tree.
 func (tree *BTree[K, V]) Puts(data map[K]V) {
 	tree.mu.Lock()
@@ -111,12 +179,46 @@ func (tree *BTree[K, V]) Puts(data map[K]V) {
 	}
 }
 
+// PutAll batch sets key-values to the tree, which is alias of Puts.
+func (tree *BTree[K, V]) PutAll(data map[K]V) {
+	tree.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the tree
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the tree is locked.
+func (tree *BTree[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	for _, entry := range entries {
+		tree.doSet(entry.Key, entry.Value)
+	}
+}
+
 // Get returns the value by given `key`, or empty value of type K if the key is not found in the map.
 func (tree *BTree[K, V]) Get(key K) (value V) {
 	value, _ = tree.Search(key)
 	return
 }
 
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (tree *BTree[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := tree.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (tree *BTree[K, V]) MustGet(key K) V {
+	value, found := tree.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
 // doSetWithLockCheck checks whether value of the key exists with mutex.Lock,
 // if not exists, set value to the map with given `key`,
 // or else just return the existing value.
@@ -298,6 +400,42 @@ func (tree *BTree[K, V]) Clear() {
 	defer tree.mu.Unlock()
 	tree.root = nil
 	tree.size = 0
+	if tree.arena != nil {
+		tree.arena.drop()
+	}
+}
+
+// SetNodeArena enables allocating entries from an arena, which allocates `chunkSize` entries at a time
+// instead of one by one. It reduces the allocations of trees holding a huge number of entries.
+// The arena is disabled if `chunkSize` <= 0.
+//
+// The arena is meant for the trees built and then dropped by Reset as a whole. The memory of the removed
+// entries is not reused until Reset, so that the entries previously returned by the tree are never reused
+// for other keys, which means the chunks keep growing with the insertions and removals in between,
+// and an entry still in the tree keeps its whole chunk alive.
+// Use Clear or Replace instead of Reset, or don't enable the arena, for long-lived trees with churn.
+// The value of an existing key is updated in place in its entry instead of replacing the entry.
+//
+// Note that the arena only covers the entries. The nodes of B-tree, each holding the slices of up to `m`-1
+// entries and `m` children, are still allocated one by one.
+func (tree *BTree[K, V]) SetNodeArena(chunkSize int) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.arena = newNodeArena[BTreeEntry[K, V]](chunkSize)
+}
+
+// Reset removes all nodes from the tree. If the entry arena is enabled, all entries are returned
+// to the arena at once and their memory is reused by later insertions, or else it is the same as Clear.
+//
+// Note that any entry previously returned by the tree, like Left or Right, must not be used after Reset.
+func (tree *BTree[K, V]) Reset() {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.root = nil
+	tree.size = 0
+	if tree.arena != nil {
+		tree.arena.reset()
+	}
 }
 
 // Replace the data of the tree with given `data`.
@@ -306,11 +444,38 @@ func (tree *BTree[K, V]) Replace(data map[K]V) {
 	defer tree.mu.Unlock()
 	tree.root = nil
 	tree.size = 0
+	if tree.arena != nil {
+		tree.arena.drop()
+	}
 	for k, v := range data {
 		tree.doSet(k, v)
 	}
 }
 
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// in ascending order of the keys and under one lock.
+func (tree *BTree[K, V]) ReplaceAll(f func(key K, value V) V) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.doReplaceAll(tree.root, f)
+}
+
+// doReplaceAll replaces the values of the entries under `node` in ascending order without lock.
+func (tree *BTree[K, V]) doReplaceAll(node *BTreeNode[K, V], f func(key K, value V) V) {
+	if node == nil {
+		return
+	}
+	for i, entry := range node.Entries {
+		if i < len(node.Children) {
+			tree.doReplaceAll(node.Children[i], f)
+		}
+		entry.value = f(entry.key, entry.value)
+	}
+	if len(node.Children) > len(node.Entries) {
+		tree.doReplaceAll(node.Children[len(node.Entries)], f)
+	}
+}
+
 // Height returns the height of the tree.
 func (tree *BTree[K, V]) Height() int {
 	tree.mu.RLock()
@@ -354,6 +519,24 @@ func (tree *BTree[K, V]) String() string {
 	return buffer.String()
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (tree *BTree[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, tree)
+}
+
+// formatEntries implements the interface formattable.
+func (tree *BTree[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = tree.Size()
+	return collectFormatPairs(tree.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (tree *BTree[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, tree.ForEach)
+}
+
 // Search searches the tree with given `key`.
 // Second return parameter `found` is true if key was found, otherwise false.
 func (tree *BTree[K, V]) Search(key K) (value V, found bool) {
@@ -386,6 +569,34 @@ func (tree *BTree[K, V]) ForEach(f func(key K, value V) bool) {
 	tree.ForEachAsc(f)
 }
 
+// Entries returns all key-value pairs of the map as a slice of entries, in ascending order of the keys.
+func (tree *BTree[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(tree.ForEach, tree.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (tree *BTree[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	tree.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (tree *BTree[K, V]) IterKeys(f func(key K) bool) {
+	tree.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (tree *BTree[K, V]) IterValues(f func(value V) bool) {
+	tree.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
 // IteratorFrom is alias of IteratorAscFrom.
 func (tree *BTree[K, V]) IteratorFrom(key K, match bool, f func(key K, value V) bool) {
 	tree.IteratorAscFrom(key, match, f)
@@ -921,15 +1132,25 @@ func (tree *BTree[K, V]) deleteChild(node *BTreeNode[K, V], index int) {
 	node.Children = node.Children[:len(node.Children)-1]
 }
 
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (tree *BTree[K, V]) SetMarshalOptions(options JSONOptions) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (tree BTree[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 	if tree.root == nil {
 		return []byte("null"), nil
 	}
+	tree.mu.RLock()
+	options := tree.jsonOptions
+	tree.mu.RUnlock()
 	buffer := bytes.NewBuffer(nil)
 	buffer.WriteByte('{')
 	tree.ForEach(func(key K, value V) bool {
-		valueBytes, valueJsonErr := json.Marshal(value)
+		valueBytes, valueJsonErr := marshalJSONValue(options, value)
 		if valueJsonErr != nil {
 			err = valueJsonErr
 			return false
@@ -941,6 +1162,9 @@ func (tree BTree[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 		return true
 	})
 	buffer.WriteByte('}')
+	if err != nil {
+		return nil, err
+	}
 	return buffer.Bytes(), nil
 }
 
@@ -271,3 +271,80 @@ func Test_AVLTree_Remove(t *testing.T) {
 		}
 	})
 }
+
+func Test_AVLTree_RemoveReported(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewAVLTree[int, int](comparators.ComparatorInt)
+		for i := 0; i < 100; i++ {
+			m.Put(i, i)
+		}
+		for i := 0; i < 100; i += 3 {
+			value, removed := m.Remove(i)
+			t.Assert(removed, true)
+			t.Assert(value, i)
+			_, removed = m.Remove(i)
+			t.Assert(removed, false)
+		}
+		t.Assert(m.Size(), 66)
+		keys := m.Keys()
+		t.Assert(len(keys), 66)
+		for i, k := range keys {
+			t.Assert(k%3 != 0, true)
+			if i > 0 {
+				t.AssertGT(k, keys[i-1])
+			}
+			t.Assert(m.Get(k), k)
+		}
+	})
+}
+
+func Test_AVLTree_NodeArena(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewAVLTree[int, int](comparators.ComparatorInt)
+		m.SetNodeArena(4)
+		for i := 0; i < 100; i++ {
+			m.Put((i*37)%100, i)
+		}
+		t.Assert(m.Size(), 100)
+		for i := 0; i < 100; i += 2 {
+			_, removed := m.Remove(i)
+			t.Assert(removed, true)
+		}
+		t.Assert(m.Size(), 50)
+		keys := m.Keys()
+		for i, k := range keys {
+			t.Assert(k, i*2+1)
+			t.Assert(m.Get(k), (k*73)%100)
+		}
+		for i := 0; i < 10; i++ {
+			m.Put(i, -i)
+		}
+		t.Assert(m.Size(), 55)
+		t.Assert(m.Get(4), -4)
+		t.Assert(m.Get(5), -5)
+
+		m.Reset()
+		t.Assert(m.Size(), 0)
+		t.Assert(m.ContainsKey(1), false)
+		m.Puts(map[int]int{3: 3, 1: 1, 2: 2})
+		t.Assert(m.Keys(), []int{1, 2, 3})
+		m.Clear()
+		t.Assert(m.Size(), 0)
+	})
+}
+
+func Test_AVLTree_NodeArenaEntries(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewAVLTree[int, int](comparators.ComparatorInt)
+		m.SetNodeArena(2)
+		m.Puts(map[int]int{1: 10, 2: 20, 3: 30})
+		left := m.Left()
+		m.Remove(1)
+		for i := 4; i < 20; i++ {
+			m.Put(i, i*10)
+		}
+		t.Assert(left.Key(), 1)
+		t.Assert(left.Value(), 10)
+		t.Assert(m.Size(), 18)
+	})
+}
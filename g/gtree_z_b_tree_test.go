@@ -227,3 +227,104 @@ func Test_BTree_Remove(t *testing.T) {
 		}
 	})
 }
+
+func Test_BTree_NodeArena(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBTree[int, int](3, comparators.ComparatorInt)
+		m.SetNodeArena(4)
+		for i := 0; i < 100; i++ {
+			m.Put((i*37)%100, i)
+		}
+		t.Assert(m.Size(), 100)
+		for i := 0; i < 100; i += 2 {
+			_, removed := m.Remove(i)
+			t.Assert(removed, true)
+		}
+		t.Assert(m.Size(), 50)
+		keys := m.Keys()
+		for i, k := range keys {
+			t.Assert(k, i*2+1)
+			t.Assert(m.Get(k), (k*73)%100)
+		}
+		for i := 0; i < 10; i++ {
+			m.Put(i, -i)
+		}
+		t.Assert(m.Size(), 55)
+		t.Assert(m.Get(4), -4)
+		t.Assert(m.Get(5), -5)
+
+		m.Reset()
+		t.Assert(m.Size(), 0)
+		t.Assert(m.ContainsKey(1), false)
+		m.Puts(map[int]int{3: 3, 1: 1, 2: 2})
+		t.Assert(m.Keys(), []int{1, 2, 3})
+		m.Clear()
+		t.Assert(m.Size(), 0)
+	})
+}
+
+func Test_BTree_NodeArenaEntries(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBTree[int, int](3, comparators.ComparatorInt)
+		m.SetNodeArena(2)
+		m.Puts(map[int]int{1: 10, 2: 20, 3: 30})
+		left := m.Left()
+		// The value of an existing key is updated in place.
+		m.Put(1, 11)
+		t.Assert(left.Value(), 11)
+		m.Remove(1)
+		for i := 4; i < 20; i++ {
+			m.Put(i, i*10)
+		}
+		t.Assert(left.Key(), 1)
+		t.Assert(left.Value(), 11)
+		t.Assert(m.Size(), 18)
+
+		// Updating the existing keys takes no arena slots.
+		allocs := testing.AllocsPerRun(100, func() {
+			m.Put(2, 21)
+			m.Put(3, 31)
+		})
+		t.Assert(allocs, 0)
+		t.Assert(m.Get(3), 31)
+	})
+}
+
+func Test_BTree_Auto(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(g.BTreeOrderFor(8, 8), 42)
+		t.Assert(g.BTreeOrderFor(16, 16), 25)
+		t.Assert(g.BTreeOrderFor(1024, 1024), 4)
+		t.Assert(g.BTreeOrderFor(0, 0), 128)
+
+		m := g.NewBTreeAuto[int, int](comparators.ComparatorInt)
+		t.Assert(m.Order(), g.BTreeOrderFor(8, 8))
+		for i := 0; i < 1000; i++ {
+			m.Put(i, i*2)
+		}
+		t.Assert(m.Size(), 1000)
+		t.Assert(m.Get(500), 1000)
+		t.AssertLE(m.Height(), 3)
+
+		s := g.NewBTreeAuto[string, string](comparators.ComparatorString)
+		t.Assert(s.Order(), g.BTreeOrderFor(16, 16))
+		t.Assert(g.NewBTree[int, int](5, comparators.ComparatorInt).Order(), 5)
+	})
+}
+
+func Test_BTree_ReplaceAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewBTree[int, string](3, comparators.ComparatorInt)
+		for i := 100; i > 0; i-- {
+			m.Put(i, "")
+		}
+		var keys []int
+		m.ReplaceAll(func(key int, value string) string {
+			keys = append(keys, key)
+			return fmt.Sprint(key)
+		})
+		t.Assert(keys, m.Keys())
+		t.Assert(len(keys), 100)
+		t.Assert(m.Get(42), "42")
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/utils/comparators"
+)
+
+const btreeBenchSize = 10000
+
+var btreeBenchOrders = []int{3, 8, 16, 32, 64, 128}
+
+// benchmarkBTreeOrders benchmarks putting and getting keys with each of btreeBenchOrders and the auto order.
+func benchmarkBTreeOrders[K comparable](b *testing.B, keys []K, comparator func(a, b K) int) {
+	orders := append(append([]int{}, btreeBenchOrders...), g.NewBTreeAuto[K, int](comparator).Order())
+	for i, order := range orders {
+		name := fmt.Sprintf("Order%d", order)
+		if i == len(orders)-1 {
+			name = fmt.Sprintf("Auto%d", order)
+		}
+		b.Run(name+"/Put", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tree := g.NewBTree[K, int](order, comparator)
+				for j, key := range keys {
+					tree.Put(key, j)
+				}
+			}
+		})
+		b.Run(name+"/Get", func(b *testing.B) {
+			tree := g.NewBTree[K, int](order, comparator)
+			for j, key := range keys {
+				tree.Put(key, j)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tree.Get(keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+func Benchmark_BTree_Order_IntKey(b *testing.B) {
+	keys := make([]int, btreeBenchSize)
+	for i := range keys {
+		keys[i] = (i * 7919) % btreeBenchSize
+	}
+	benchmarkBTreeOrders(b, keys, comparators.ComparatorInt)
+}
+
+func Benchmark_BTree_Order_ShortStringKey(b *testing.B) {
+	keys := make([]string, btreeBenchSize)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%08d", (i*7919)%btreeBenchSize)
+	}
+	benchmarkBTreeOrders(b, keys, comparators.ComparatorString)
+}
+
+func Benchmark_BTree_Order_LongStringKey(b *testing.B) {
+	prefix := strings.Repeat("k", 120)
+	keys := make([]string, btreeBenchSize)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s%08d", prefix, (i*7919)%btreeBenchSize)
+	}
+	benchmarkBTreeOrders(b, keys, comparators.ComparatorString)
+}
@@ -144,6 +144,154 @@ func Test_RedBlackTree_Iterator(t *testing.T) {
 	})
 }
 
+func Test_RedBlackTree_IteratorChecked(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		for i := 1; i <= 10; i++ {
+			m.Put(i, i)
+		}
+		var keys []int
+		err := m.ForEachAscChecked(func(key, value int) bool {
+			keys = append(keys, key)
+			return key < 5
+		})
+		t.AssertNil(err)
+		t.Assert(keys, []int{1, 2, 3, 4, 5})
+
+		// Updating value of existing key is not structural modification.
+		keys = keys[:0]
+		err = m.ForEachDescChecked(func(key, value int) bool {
+			keys = append(keys, key)
+			m.Put(key, value*10)
+			return true
+		})
+		t.AssertNil(err)
+		t.Assert(keys, []int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+		t.Assert(m.Get(3), 30)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		for i := 1; i <= 10; i++ {
+			m.Put(i, i)
+		}
+		count := 0
+		err := m.ForEachAscChecked(func(key, value int) bool {
+			count++
+			m.Put(key+100, value)
+			return true
+		})
+		t.Assert(err, g.ErrConcurrentModification)
+		t.Assert(count, 1)
+
+		count = 0
+		err = m.ForEachDescChecked(func(key, value int) bool {
+			count++
+			m.Remove(key)
+			return true
+		})
+		t.Assert(err, g.ErrConcurrentModification)
+		t.Assert(count, 1)
+	})
+	// The concurrent-safe tree can be modified in the callback function without deadlock.
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt, true)
+		for i := 1; i <= 10; i++ {
+			m.Put(i, i)
+		}
+		var keys []int
+		err := m.ForEachAscChecked(func(key, value int) bool {
+			keys = append(keys, key)
+			m.Put(key, value*10)
+			return true
+		})
+		t.AssertNil(err)
+		t.Assert(keys, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+		t.Assert(m.Get(3), 30)
+
+		count := 0
+		err = m.ForEachDescChecked(func(key, value int) bool {
+			count++
+			m.Remove(key)
+			return true
+		})
+		t.Assert(err, g.ErrConcurrentModification)
+		t.Assert(count, 1)
+		t.Assert(m.Size(), 9)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt, true)
+		for i := 1; i <= 10; i++ {
+			m.Put(i, i)
+		}
+		var keys []int
+		err := m.IteratorAscFromChecked(5, true, func(key, value int) bool {
+			keys = append(keys, key)
+			return true
+		})
+		t.AssertNil(err)
+		t.Assert(keys, []int{5, 6, 7, 8, 9, 10})
+
+		keys = keys[:0]
+		err = m.IteratorDescFromChecked(5, false, func(key, value int) bool {
+			keys = append(keys, key)
+			return true
+		})
+		t.AssertNil(err)
+		t.Assert(keys, []int{4, 3, 2, 1})
+
+		keys = keys[:0]
+		err = m.IteratorAscFromChecked(11, true, func(key, value int) bool {
+			keys = append(keys, key)
+			return true
+		})
+		t.AssertNil(err)
+		t.Assert(len(keys), 0)
+
+		count := 0
+		err = m.IteratorAscFromChecked(5, false, func(key, value int) bool {
+			count++
+			m.Put(key+100, value)
+			return true
+		})
+		t.Assert(err, g.ErrConcurrentModification)
+		t.Assert(count, 1)
+
+		count = 0
+		err = m.IteratorDescFromChecked(5, true, func(key, value int) bool {
+			count++
+			m.Remove(1)
+			return true
+		})
+		t.Assert(err, g.ErrConcurrentModification)
+		t.Assert(count, 1)
+	})
+	// The unchecked iterating functions stop iterating without panics.
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		for i := 1; i <= 10; i++ {
+			m.Put(i, i)
+		}
+		added := 0
+		iterate := func(iterator func(f func(key, value int) bool)) (count int) {
+			iterator(func(key, value int) bool {
+				count++
+				added++
+				m.Put(100+added, value)
+				return true
+			})
+			return
+		}
+		t.Assert(iterate(m.ForEachAsc), 1)
+		t.Assert(iterate(m.ForEachDesc), 1)
+		t.Assert(iterate(func(f func(key, value int) bool) {
+			m.IteratorAscFrom(5, true, f)
+		}), 1)
+		t.Assert(iterate(func(f func(key, value int) bool) {
+			m.IteratorDescFrom(5, true, f)
+		}), 1)
+	})
+}
+
 func Test_RedBlackTree_IteratorFrom(t *testing.T) {
 	m := make(map[int]int)
 	for i := 1; i <= 10; i++ {
@@ -387,3 +535,164 @@ func Test_RedBlackTree_Remove(t *testing.T) {
 		}
 	})
 }
+
+func Test_RedBlackTree_NodeArena(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		m.SetNodeArena(4)
+		for i := 0; i < 100; i++ {
+			m.Put((i*37)%100, i)
+		}
+		t.Assert(m.Size(), 100)
+		for i := 0; i < 100; i += 2 {
+			_, removed := m.Remove(i)
+			t.Assert(removed, true)
+		}
+		t.Assert(m.Size(), 50)
+		keys := m.Keys()
+		for i, k := range keys {
+			t.Assert(k, i*2+1)
+			t.Assert(m.Get(k), (k*73)%100)
+		}
+		for i := 0; i < 10; i++ {
+			m.Put(i, -i)
+		}
+		t.Assert(m.Size(), 55)
+		t.Assert(m.Get(4), -4)
+		t.Assert(m.Get(5), -5)
+
+		m.Reset()
+		t.Assert(m.Size(), 0)
+		t.Assert(m.ContainsKey(1), false)
+		m.Puts(map[int]int{3: 3, 1: 1, 2: 2})
+		t.Assert(m.Keys(), []int{1, 2, 3})
+		m.Clear()
+		t.Assert(m.Size(), 0)
+	})
+}
+
+func Test_RedBlackTree_NodeArenaEntries(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		m.SetNodeArena(2)
+		m.Puts(map[int]int{1: 10, 2: 20, 3: 30})
+		first := m.FirstEntry()
+		left := m.Left()
+		m.Remove(1)
+		for i := 4; i < 20; i++ {
+			m.Put(i, i*10)
+		}
+		t.Assert(first.Key(), 1)
+		t.Assert(first.Value(), 10)
+		t.Assert(left.Key(), 1)
+		t.Assert(left.Value(), 10)
+		t.Assert(m.Size(), 18)
+	})
+}
+
+func Test_RedBlackTree_Compute(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, string](comparators.ComparatorInt, true)
+		m.Put(2, "b")
+		v, exists := m.Compute(1, func(value string, exists bool) (string, bool) {
+			t.Assert(exists, false)
+			return "a", true
+		})
+		t.Assert(v, "a")
+		t.Assert(exists, true)
+		m.Compute(2, func(value string, exists bool) (string, bool) {
+			return value + value, true
+		})
+		t.Assert(m.Keys(), []int{1, 2})
+		t.Assert(m.Values(), []string{"a", "bb"})
+		_, exists = m.Compute(1, func(value string, exists bool) (string, bool) {
+			return "", false
+		})
+		t.Assert(exists, false)
+		t.Assert(m.Keys(), []int{2})
+
+		_, exists = m.ComputeIfPresent(3, func(value string) (string, bool) {
+			return "c", true
+		})
+		t.Assert(exists, false)
+		t.Assert(m.Size(), 1)
+
+		join := func(a, b string) string { return a + "," + b }
+		t.Assert(m.MergeValue(0, "x", join), "x")
+		t.Assert(m.MergeValue(0, "y", join), "x,y")
+		t.Assert(m.Keys(), []int{0, 2})
+	})
+}
+
+func Test_RedBlackTree_GetOrDefault_MustGet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		m.Put(1, 0)
+		t.Assert(m.GetOrDefault(1, -1), 0)
+		t.Assert(m.GetOrDefault(2, -1), -1)
+		t.Assert(m.MustGet(1), 0)
+
+		func() {
+			defer func() {
+				t.Assert(recover(), "key not found: 2")
+			}()
+			m.MustGet(2)
+		}()
+	})
+}
+
+func Test_RedBlackTree_Entries(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[string, int](comparators.ComparatorString)
+		m.Put("b", 2)
+		m.Put("c", 3)
+		m.Put("a", 1)
+		t.Assert(m.Entries(), []g.Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}})
+
+		var values []int
+		m.ForEachEntry(func(entry g.Entry[string, int]) bool {
+			values = append(values, entry.Value)
+			return true
+		})
+		t.Assert(values, []int{1, 2, 3})
+	})
+}
+
+func Test_RedBlackTree_IterKeysValues(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, string](comparators.ComparatorInt)
+		for i := 5; i > 0; i-- {
+			m.Put(i, fmt.Sprint(i))
+		}
+
+		var keys []int
+		m.IterKeys(func(key int) bool {
+			keys = append(keys, key)
+			return key < 3
+		})
+		t.Assert(keys, []int{1, 2, 3})
+
+		var values []string
+		m.IterValues(func(value string) bool {
+			values = append(values, value)
+			return true
+		})
+		t.Assert(values, m.Values())
+	})
+}
+
+func Test_RedBlackTree_ReplaceAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		for i := 10; i > 0; i-- {
+			m.Put(i, i)
+		}
+		var keys []int
+		m.ReplaceAll(func(key int, value int) int {
+			keys = append(keys, key)
+			return -value
+		})
+		t.Assert(keys, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+		t.Assert(m.Values(), []int{-1, -2, -3, -4, -5, -6, -7, -8, -9, -10})
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// Hasher64 is implemented by containers whose contents can be hashed, e.g. to be used as cache keys.
+// Containers implementing it are hashed by Hash64 when they are nested in other containers.
+type Hasher64 interface {
+	// Hash64 returns the 64-bit hash of the contents using `seed`.
+	Hash64(seed maphash.Seed) uint64
+}
+
+// writeHashUint64 writes `v` to `h` in little endian.
+func writeHashUint64(h *maphash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	_, _ = h.Write(b[:])
+}
+
+// writeHashValue writes `v` to `h`.
+// Strings and bytes are prefixed with their length, so that sequences of them are not ambiguous.
+func writeHashValue(h *maphash.Hash, v interface{}) {
+	switch value := v.(type) {
+	case nil:
+		_ = h.WriteByte(0)
+	case Hasher64:
+		writeHashUint64(h, value.Hash64(h.Seed()))
+	case string:
+		writeHashUint64(h, uint64(len(value)))
+		_, _ = h.WriteString(value)
+	case []byte:
+		writeHashUint64(h, uint64(len(value)))
+		_, _ = h.Write(value)
+	case bool:
+		if value {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case int:
+		writeHashUint64(h, uint64(value))
+	case int8:
+		writeHashUint64(h, uint64(value))
+	case int16:
+		writeHashUint64(h, uint64(value))
+	case int32:
+		writeHashUint64(h, uint64(value))
+	case int64:
+		writeHashUint64(h, uint64(value))
+	case uint:
+		writeHashUint64(h, uint64(value))
+	case uint8:
+		writeHashUint64(h, uint64(value))
+	case uint16:
+		writeHashUint64(h, uint64(value))
+	case uint32:
+		writeHashUint64(h, uint64(value))
+	case uint64:
+		writeHashUint64(h, value)
+	case uintptr:
+		writeHashUint64(h, uint64(value))
+	case float32:
+		writeHashUint64(h, math.Float64bits(float64(value)))
+	case float64:
+		writeHashUint64(h, math.Float64bits(value))
+	default:
+		s := gconv.String(value)
+		writeHashUint64(h, uint64(len(s)))
+		_, _ = h.WriteString(s)
+	}
+}
+
+// hashOrdered returns the hash of values iterated by `forEach`, which is sensitive to the iteration order.
+func hashOrdered[T any](seed maphash.Seed, forEach func(f func(v T) bool)) uint64 {
+	var (
+		h    maphash.Hash
+		size uint64
+	)
+	h.SetSeed(seed)
+	forEach(func(v T) bool {
+		writeHashValue(&h, v)
+		size++
+		return true
+	})
+	writeHashUint64(&h, size)
+	return h.Sum64()
+}
+
+// hashUnordered returns the hash of values iterated by `forEach`, which is insensitive to the iteration order.
+func hashUnordered[T any](seed maphash.Seed, forEach func(f func(v T) bool)) uint64 {
+	var (
+		h    maphash.Hash
+		sum  uint64
+		size uint64
+	)
+	h.SetSeed(seed)
+	forEach(func(v T) bool {
+		h.Reset()
+		writeHashValue(&h, v)
+		sum += h.Sum64()
+		size++
+		return true
+	})
+	h.Reset()
+	writeHashUint64(&h, sum)
+	writeHashUint64(&h, size)
+	return h.Sum64()
+}
+
+// hashUnorderedPairs returns the hash of key-value pairs iterated by `forEach`,
+// which is insensitive to the iteration order.
+func hashUnorderedPairs[K, V any](seed maphash.Seed, forEach func(f func(k K, v V) bool)) uint64 {
+	var (
+		h    maphash.Hash
+		sum  uint64
+		size uint64
+	)
+	h.SetSeed(seed)
+	forEach(func(k K, v V) bool {
+		h.Reset()
+		writeHashValue(&h, k)
+		writeHashValue(&h, v)
+		sum += h.Sum64()
+		size++
+		return true
+	})
+	h.Reset()
+	writeHashUint64(&h, sum)
+	writeHashUint64(&h, size)
+	return h.Sum64()
+}
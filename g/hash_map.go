@@ -8,6 +8,8 @@ package g
 
 import (
 	json2 "encoding/json"
+	"fmt"
+	"hash/maphash"
 
 	"github.com/wesleywu/gcontainer/internal/deepcopy"
 	"github.com/wesleywu/gcontainer/internal/json"
@@ -18,8 +20,9 @@ import (
 
 // HashMap wraps map type `map[K]V` and provides more map features.
 type HashMap[K comparable, V any] struct {
-	mu   rwmutex.RWMutex
-	data map[K]V
+	mu          rwmutex.RWMutex
+	data        map[K]V
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // NewHashMap creates and returns an empty hash map.
@@ -56,6 +59,34 @@ func (m *HashMap[K, V]) ForEach(f func(k K, v V) bool) {
 	}
 }
 
+// Entries returns all key-value pairs of the map as a slice of entries, in random order.
+func (m *HashMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *HashMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *HashMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *HashMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
 // Clone returns a new hash map with copy of current map data.
 func (m *HashMap[K, V]) Clone(safe ...bool) Map[K, V] {
 	return NewHashMapFrom[K, V](m.Map(), safe...)
@@ -95,6 +126,32 @@ func (m *HashMap[K, V]) FilterEmpty() {
 	}
 }
 
+// Filtered returns a new hash map containing all key-value pairs for which `f` returns true.
+// Different from FilterInPlace, it does not change current map.
+func (m *HashMap[K, V]) Filtered(f func(key K, value V) bool) Map[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[K]V)
+	for k, v := range m.data {
+		if f(k, v) {
+			data[k] = v
+		}
+	}
+	return NewHashMapFrom[K, V](data, m.mu.IsSafe())
+}
+
+// FilterInPlace deletes all key-value pairs for which `f` returns false,
+// which means only the key-value pairs for which `f` returns true are retained.
+func (m *HashMap[K, V]) FilterInPlace(f func(key K, value V) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range m.data {
+		if !f(k, v) {
+			delete(m.data, k)
+		}
+	}
+}
+
 // FilterNil deletes all key-value pair of which the value is nil.
 func (m *HashMap[K, V]) FilterNil() {
 	m.mu.Lock()
@@ -129,6 +186,25 @@ func (m *HashMap[K, V]) Puts(data map[K]V) {
 	m.mu.Unlock()
 }
 
+// PutAll batch sets key-values to the hash map, which is alias of Puts.
+func (m *HashMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the hash map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the hash map is locked.
+func (m *HashMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		m.data = make(map[K]V, len(entries))
+	}
+	for _, entry := range entries {
+		m.data[entry.Key] = entry.Value
+	}
+}
+
 // Search searches the map with given `key`.
 // Second return parameter `found` is true if key was found, otherwise false.
 func (m *HashMap[K, V]) Search(key K) (value V, found bool) {
@@ -150,6 +226,24 @@ func (m *HashMap[K, V]) Get(key K) (value V) {
 	return
 }
 
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *HashMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *HashMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
 // Pop retrieves and deletes an item from the map.
 func (m *HashMap[K, V]) Pop() (key K, value V) {
 	m.mu.Lock()
@@ -271,6 +365,50 @@ func (m *HashMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
 	return false
 }
 
+// Compute computes the new value of `key` with `f` in one operation with the map locked, so it's atomic
+// to the other goroutines. The function `f` is called with the current value and whether the key exists,
+// and the key is set to the returned `newValue` if `f` returns true as `keep`, or else removed.
+// It returns the value of `key` after the call, and whether the key exists.
+func (m *HashMap[K, V]) Compute(key K, f func(value V, exists bool) (newValue V, keep bool)) (value V, exists bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		m.data = make(map[K]V)
+	}
+	oldValue, found := m.data[key]
+	newValue, keep := f(oldValue, found)
+	if !keep {
+		delete(m.data, key)
+		return
+	}
+	m.data[key] = newValue
+	return newValue, true
+}
+
+// ComputeIfPresent computes the new value of `key` with `f` like Compute, but only if the key exists.
+// It returns the value of `key` after the call, and whether the key exists.
+func (m *HashMap[K, V]) ComputeIfPresent(key K, f func(value V) (newValue V, keep bool)) (value V, exists bool) {
+	return m.Compute(key, func(value V, exists bool) (V, bool) {
+		if !exists {
+			return value, false
+		}
+		return f(value)
+	})
+}
+
+// MergeValue sets `value` to `key` if the key does not exist, or else sets the result of `f` called with
+// the current value and `value`, like counting with `m.MergeValue(key, 1, func(a, b int) int { return a + b })`.
+// It's done with the map locked, and returns the new value of `key`.
+func (m *HashMap[K, V]) MergeValue(key K, value V, f func(oldValue, value V) V) V {
+	newValue, _ := m.Compute(key, func(oldValue V, exists bool) (V, bool) {
+		if !exists {
+			return value, true
+		}
+		return f(oldValue, value), true
+	})
+	return newValue
+}
+
 // Remove deletes value from map by given `key`, and return this deleted value.
 func (m *HashMap[K, V]) Remove(key K) (value V, removed bool) {
 	m.mu.Lock()
@@ -366,6 +504,16 @@ func (m *HashMap[K, V]) Replace(data map[K]V) {
 	m.mu.Unlock()
 }
 
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// all done under one lock.
+func (m *HashMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range m.data {
+		m.data[k] = f(k, v)
+	}
+}
+
 // LockFunc locks writing with given callback function `f` within RWMutex.Lock.
 func (m *HashMap[K, V]) LockFunc(f func(m map[K]V)) {
 	m.mu.Lock()
@@ -411,9 +559,37 @@ func (m *HashMap[K, V]) String() string {
 	return string(b)
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *HashMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *HashMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *HashMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *HashMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (m HashMap[K, V]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(gconv.Map(m.Map()))
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
 }
 
 // UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
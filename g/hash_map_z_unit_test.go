@@ -7,12 +7,14 @@
 package g_test
 
 import (
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/internal/gtest"
 	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/utils/comparators"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
@@ -267,6 +269,22 @@ func Test_AnyAnyMap_FilterEmpty(t *testing.T) {
 	})
 }
 
+func Test_AnyAnyMap_Filtered(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMapFrom[int, int](map[int]int{1: 1, 2: 2, 3: 3, 4: 4})
+		filtered := m.Filtered(func(k int, v int) bool {
+			return v%2 == 0
+		})
+		t.Assert(filtered.Map(), map[int]int{2: 2, 4: 4})
+		t.Assert(m.Size(), 4)
+
+		m.FilterInPlace(func(k int, v int) bool {
+			return v > 2
+		})
+		t.Assert(m.Map(), map[int]int{3: 3, 4: 4})
+	})
+}
+
 func Test_AnyAnyMap_Json(t *testing.T) {
 	// Marshal
 	gtest.C(t, func(t *gtest.T) {
@@ -494,3 +512,193 @@ func Test_AnyAnyMap_DeepCopy(t *testing.T) {
 		t.AssertNE(m.Get("k1"), n.Get("k1"))
 	})
 }
+
+func TestHashMap_Compute(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMap[string, int](true)
+		v, exists := m.Compute("a", func(value int, exists bool) (int, bool) {
+			t.Assert(exists, false)
+			return 1, true
+		})
+		t.Assert(v, 1)
+		t.Assert(exists, true)
+		v, exists = m.Compute("a", func(value int, exists bool) (int, bool) {
+			return value + 1, true
+		})
+		t.Assert(v, 2)
+		_, exists = m.Compute("a", func(value int, exists bool) (int, bool) {
+			return 0, false
+		})
+		t.Assert(exists, false)
+		t.Assert(m.ContainsKey("a"), false)
+		_, exists = m.Compute("b", func(value int, exists bool) (int, bool) {
+			return 0, false
+		})
+		t.Assert(exists, false)
+		t.Assert(m.Size(), 0)
+
+		_, exists = m.ComputeIfPresent("a", func(value int) (int, bool) {
+			t.Error("should not be called for absent keys")
+			return 0, true
+		})
+		t.Assert(exists, false)
+		m.Put("a", 10)
+		v, exists = m.ComputeIfPresent("a", func(value int) (int, bool) {
+			return value * 2, true
+		})
+		t.Assert(v, 20)
+		t.Assert(exists, true)
+
+		sum := func(a, b int) int { return a + b }
+		t.Assert(m.MergeValue("c", 1, sum), 1)
+		t.Assert(m.MergeValue("c", 1, sum), 2)
+		t.Assert(m.Map(), map[string]int{"a": 20, "c": 2})
+	})
+}
+
+func TestHashMap_GetOrDefault_MustGet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMapFrom[string, int](map[string]int{"a": 0, "b": 2})
+		t.Assert(m.GetOrDefault("a", -1), 0)
+		t.Assert(m.GetOrDefault("b", -1), 2)
+		t.Assert(m.GetOrDefault("c", -1), -1)
+		t.Assert(m.ContainsKey("c"), false)
+		t.Assert(m.MustGet("a"), 0)
+		t.Assert(m.MustGet("b"), 2)
+
+		func() {
+			defer func() {
+				t.Assert(recover(), "key not found: c")
+			}()
+			m.MustGet("c")
+		}()
+	})
+}
+
+func TestHashMap_Entries(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMapFrom[string, int](map[string]int{"a": 1, "b": 2, "c": 3})
+		entries := m.Entries()
+		t.Assert(len(entries), 3)
+		data := make(map[string]int)
+		for _, entry := range entries {
+			data[entry.Key] = entry.Value
+		}
+		t.Assert(data, m.Map())
+
+		count := 0
+		m.ForEachEntry(func(entry g.Entry[string, int]) bool {
+			t.Assert(m.Get(entry.Key), entry.Value)
+			count++
+			return count < 2
+		})
+		t.Assert(count, 2)
+		t.Assert(len(g.NewHashMap[string, int]().Entries()), 0)
+	})
+}
+
+func TestHashMap_IterKeysValues(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMapFrom[int, int](map[int]int{1: 10, 2: 20, 3: 30})
+		sum := 0
+		m.IterKeys(func(key int) bool {
+			sum += key
+			return true
+		})
+		t.Assert(sum, 6)
+
+		sum = 0
+		m.IterValues(func(value int) bool {
+			sum += value
+			return true
+		})
+		t.Assert(sum, 60)
+
+		count := 0
+		m.IterKeys(func(key int) bool {
+			count++
+			return false
+		})
+		t.Assert(count, 1)
+	})
+}
+
+func TestHashMap_ReplaceAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMapFrom[string, int](map[string]int{"a": 1, "b": 2}, true)
+		m.ReplaceAll(func(key string, value int) int {
+			return value * 10
+		})
+		t.Assert(m.Map(), map[string]int{"a": 10, "b": 20})
+	})
+}
+
+func TestHashMap_PutAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewHashMap[string, int]()
+		m.PutAll(map[string]int{"a": 1, "b": 2})
+		t.Assert(m.Map(), map[string]int{"a": 1, "b": 2})
+
+		tree := g.NewTreeMap[string, int](comparators.ComparatorString)
+		tree.Put("b", 20)
+		tree.Put("c", 30)
+		m.PutAllFrom(tree)
+		t.Assert(m.Map(), map[string]int{"a": 1, "b": 20, "c": 30})
+		t.Assert(tree.Size(), 2)
+
+		// Putting the pairs of itself doesn't change or deadlock the map.
+		safe := g.NewHashMapFrom[string, int](map[string]int{"a": 1}, true)
+		safe.PutAllFrom(safe)
+		t.Assert(safe.Map(), map[string]int{"a": 1})
+
+		// The maps implementing only Map are supported too.
+		plain := plainMap{g.NewHashMapFrom[string, int](map[string]int{"d": 4})}
+		m.PutAllFrom(plain)
+		t.Assert(m.Map(), map[string]int{"a": 1, "b": 20, "c": 30, "d": 4})
+	})
+}
+
+// stringIntMap names the embedded field of plainMap, which can't be Map as it conflicts with the function Map.
+type stringIntMap = g.Map[string, int]
+
+// plainMap hides the functions of ExtendedMap of the embedded map.
+type plainMap struct {
+	stringIntMap
+}
+
+func TestHashMap_ExtendedMap(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var _ g.ExtendedMap[string, int] = g.NewHashMap[string, int]()
+		var _ g.ExtendedMap[string, int] = g.NewListMap[string, int]()
+		var _ g.ExtendedMap[string, int] = g.NewTreeMap[string, int](comparators.ComparatorString)
+		var _ g.ExtendedMap[string, int] = g.NewAVLTree[string, int](comparators.ComparatorString)
+		var _ g.ExtendedMap[string, int] = g.NewBTree[string, int](3, comparators.ComparatorString)
+		var _ g.ExtendedMap[string, int] = g.NewSmallMap[string, int]()
+		_, ok := g.Map[string, int](plainMap{g.NewHashMap[string, int]()}).(g.ExtendedMap[string, int])
+		t.Assert(ok, false)
+	})
+}
+
+func TestHashMap_PutAllFrom_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			m1 = g.NewHashMapFrom[int, int](map[int]int{1: 1}, true)
+			m2 = g.NewHashMapFrom[int, int](map[int]int{2: 2}, true)
+			wg sync.WaitGroup
+		)
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				m1.PutAllFrom(m2)
+			}()
+			go func() {
+				defer wg.Done()
+				m2.PutAllFrom(m1)
+			}()
+		}
+		wg.Wait()
+		t.Assert(m1.Map(), map[int]int{1: 1, 2: 2})
+		t.Assert(m2.Map(), map[int]int{1: 1, 2: 2})
+	})
+}
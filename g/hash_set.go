@@ -9,12 +9,16 @@ package g
 
 import (
 	"bytes"
+	"fmt"
+	"hash/maphash"
+	"slices"
 	"strings"
 
 	"github.com/wesleywu/gcontainer/internal/deepcopy"
 
 	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/comparators"
 	"github.com/wesleywu/gcontainer/utils/empty"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 	"github.com/wesleywu/gcontainer/utils/gstr"
@@ -25,8 +29,13 @@ import (
 // it does not guarantee that the order will remain constant over time.
 // This struct permits the nil or empty element.
 type HashSet[T comparable] struct {
-	mu   rwmutex.RWMutex
-	data map[T]struct{}
+	mu          rwmutex.RWMutex
+	data        map[T]struct{}
+	stored      map[T]T                   // Index from the items to the stored instances, built lazily by Get.
+	onAdd       []func(item T)            // Observers registered by OnAdd.
+	onRemove    []func(item T)            // Observers registered by OnRemove.
+	marshalCmp  comparators.Comparator[T] // Comparator set by SetMarshalSorted.
+	jsonOptions *JSONOptions              // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // NewHashSet create and returns a new set, which contains un-repeated items.
@@ -51,6 +60,34 @@ func NewHashSetFrom[T comparable](items []T, safe ...bool) *HashSet[T] {
 	}
 }
 
+// NewHashSetOf returns a new set of `values`, in which the duplicated values are merged.
+// The returned set is not concurrent-safe; use NewHashSetFrom for a concurrent-safe one.
+func NewHashSetOf[T comparable](values ...T) *HashSet[T] {
+	return NewHashSetFrom(values)
+}
+
+// NewHashSetFromKeys returns a new set of the keys of map `m`.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewHashSetFromKeys[K comparable, V any](m Map[K, V], safe ...bool) *HashSet[K] {
+	set := NewHashSet[K](safe...)
+	m.ForEach(func(key K, _ V) bool {
+		set.data[key] = struct{}{}
+		return true
+	})
+	return set
+}
+
+// NewHashSetFromValues returns a new set of the distinct values of map `m`.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewHashSetFromValues[K comparable, V comparable](m Map[K, V], safe ...bool) *HashSet[V] {
+	set := NewHashSet[V](safe...)
+	m.ForEach(func(_ K, value V) bool {
+		set.data[value] = struct{}{}
+		return true
+	})
+	return set
+}
+
 // ForEach iterates the set readonly with given callback function `f`,
 // if `f` returns true then continue iterating; or false to stop.
 func (set *HashSet[T]) ForEach(f func(v T) bool) {
@@ -65,12 +102,19 @@ func (set *HashSet[T]) ForEach(f func(v T) bool) {
 
 // Add adds one or multiple items to the set.
 func (set *HashSet[T]) Add(items ...T) bool {
+	return set.AddAllCount(items...) > 0
+}
+
+// AddAllCount adds `items` to the set with a single lock acquisition, so that the batch is atomic to the
+// other goroutines, and returns the number of items actually added, which excludes the ones already in the set.
+func (set *HashSet[T]) AddAllCount(items ...T) (count int) {
+	var added []T
+	defer func() { set.notifyAdd(added) }()
 	set.mu.Lock()
 	defer set.mu.Unlock()
 	if set.data == nil {
 		set.data = make(map[T]struct{})
 	}
-	var setChanged = false
 	for _, item := range items {
 		if empty.IsNil(item) {
 			continue
@@ -79,31 +123,59 @@ func (set *HashSet[T]) Add(items ...T) bool {
 			continue
 		}
 		set.data[item] = struct{}{}
-		setChanged = true
+		set.storeWithoutLock(item)
+		count++
+		added = set.appendIfWatched(added, set.onAdd, item)
 	}
-	return setChanged
+	return count
 }
 
 // AddAll adds all the elements in the specified collection to this set.
+// Returns true if this set changed as a result of the call.
 func (set *HashSet[T]) AddAll(items Collection[T]) bool {
+	// The items are copied before locking, as `items` may be the set itself.
+	return set.Add(items.Slice()...)
+}
+
+// Get returns the item stored in the set which equals to `item`, and true as `ok`,
+// or empty of type T and false if there is no such item.
+// It returns the instance added to the set rather than `item`, so it can be used to intern
+// equal values like strings, e.g. to reuse the stored instance and drop `item`.
+// The index for it is built on the first call, and maintained by the mutations after that.
+func (set *HashSet[T]) Get(item T) (stored T, ok bool) {
+	set.mu.RLock()
+	if set.stored != nil {
+		stored, ok = set.stored[item]
+		set.mu.RUnlock()
+		return
+	}
+	set.mu.RUnlock()
 	set.mu.Lock()
 	defer set.mu.Unlock()
-	if set.data == nil {
-		set.data = make(map[T]struct{})
+	if _, ok = set.data[item]; !ok {
+		return
 	}
-	var setChanged = false
-	items.ForEach(func(item T) bool {
-		if empty.IsNil(item) {
-			return true
-		}
-		if _, found := set.data[item]; found {
-			return true
+	if set.stored == nil {
+		set.stored = make(map[T]T, len(set.data))
+		for k := range set.data {
+			set.stored[k] = k
 		}
-		set.data[item] = struct{}{}
-		setChanged = true
-		return true
-	})
-	return setChanged
+	}
+	return set.stored[item], true
+}
+
+// storeWithoutLock adds `item` to the index of Get if it is built.
+func (set *HashSet[T]) storeWithoutLock(item T) {
+	if set.stored != nil {
+		set.stored[item] = item
+	}
+}
+
+// unstoreWithoutLock removes `item` from the index of Get if it is built.
+func (set *HashSet[T]) unstoreWithoutLock(item T) {
+	if set.stored != nil {
+		delete(set.stored, item)
+	}
 }
 
 // Contains checks whether the set contains `item`.
@@ -163,32 +235,66 @@ func (set *HashSet[T]) IsEmpty() bool {
 }
 
 // Remove deletes `items` from set.
+// Returns true if any of `items` was in the set.
 func (set *HashSet[T]) Remove(items ...T) bool {
+	return set.RemoveAllCount(items...) > 0
+}
+
+// RemoveAllCount deletes `items` from the set with a single lock acquisition, so that the batch is atomic
+// to the other goroutines, and returns the number of items actually removed.
+func (set *HashSet[T]) RemoveAllCount(items ...T) (count int) {
+	var removed []T
+	defer func() { set.notifyRemove(removed) }()
 	set.mu.Lock()
 	defer set.mu.Unlock()
-	dataChanged := false
-	if set.data != nil {
-		for _, item := range items {
-			delete(set.data, item)
-			dataChanged = true
+	for _, item := range items {
+		if _, ok := set.data[item]; !ok {
+			continue
 		}
+		delete(set.data, item)
+		set.unstoreWithoutLock(item)
+		count++
+		removed = set.appendIfWatched(removed, set.onRemove, item)
 	}
-	return dataChanged
+	return count
 }
 
-// RemoveAll removes all of this collection's elements that are also contained in the specified collection
+// RemoveAll removes all of this collection's elements that are also contained in the specified collection.
+// Returns true if this set changed as a result of the call.
 func (set *HashSet[T]) RemoveAll(items Collection[T]) bool {
+	// The items are copied before locking, as `items` may be the set itself.
+	return set.Remove(items.Slice()...)
+}
+
+// RetainAll retains only the items that are contained in the specified collection `items`,
+// in which `items.Contains` is called for each item of the set.
+// Unlike Intersect, it modifies the set in place rather than returning a new set.
+// Returns true if this set changed as a result of the call.
+func (set *HashSet[T]) RetainAll(items Collection[T]) bool {
+	if Collection[T](set) == items {
+		return false
+	}
+	return set.RemoveIf(func(item T) bool {
+		return !items.Contains(item)
+	}) > 0
+}
+
+// RemoveIf removes the items for which `predicate` returns true in a single pass with the set locked,
+// and returns the number of removed items.
+func (set *HashSet[T]) RemoveIf(predicate func(item T) bool) (removed int) {
+	var removedItems []T
+	defer func() { set.notifyRemove(removedItems) }()
 	set.mu.Lock()
 	defer set.mu.Unlock()
-	dataChanged := false
-	if set.data != nil {
-		items.ForEach(func(item T) bool {
-			delete(set.data, item)
-			dataChanged = true
-			return true
-		})
+	for k := range set.data {
+		if predicate(k) {
+			delete(set.data, k)
+			set.unstoreWithoutLock(k)
+			removed++
+			removedItems = set.appendIfWatched(removedItems, set.onRemove, k)
+		}
 	}
-	return dataChanged
+	return
 }
 
 // Size returns the size of the set.
@@ -201,9 +307,17 @@ func (set *HashSet[T]) Size() int {
 
 // Clear deletes all items of the set.
 func (set *HashSet[T]) Clear() {
+	var removed []T
 	set.mu.Lock()
+	if len(set.onRemove) > 0 {
+		for k := range set.data {
+			removed = append(removed, k)
+		}
+	}
 	set.data = make(map[T]struct{})
+	set.stored = nil
 	set.mu.Unlock()
+	set.notifyRemove(removed)
 }
 
 func (set *HashSet[T]) Clone() Collection[T] {
@@ -214,8 +328,9 @@ func (set *HashSet[T]) Clone() Collection[T] {
 		m[k] = struct{}{}
 	}
 	return &HashSet[T]{
-		data: m,
-		mu:   rwmutex.Create(set.mu.IsSafe()),
+		data:       m,
+		mu:         rwmutex.Create(set.mu.IsSafe()),
+		marshalCmp: set.marshalCmp,
 	}
 }
 
@@ -286,10 +401,55 @@ func (set *HashSet[T]) String() string {
 	return buffer.String()
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (set *HashSet[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, set)
+}
+
+// formatEntries implements the interface formattable.
+func (set *HashSet[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = set.Size()
+	return collectFormatValues(set.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is insensitive to the order of elements.
+func (set *HashSet[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnordered(seed, set.ForEach)
+}
+
+// Any returns true if `predicate` returns true for any item of the set, which stops at the first match.
+// It returns false for an empty set.
+func (set *HashSet[T]) Any(predicate func(v T) bool) bool {
+	return anyMatch(set.ForEach, predicate)
+}
+
+// All returns true if `predicate` returns true for all items of the set, which stops at the first mismatch.
+// It returns true for an empty set.
+func (set *HashSet[T]) All(predicate func(v T) bool) bool {
+	return !anyMatch(set.ForEach, func(v T) bool {
+		return !predicate(v)
+	})
+}
+
+// None returns true if `predicate` returns false for all items of the set, which stops at the first match.
+// It returns true for an empty set.
+func (set *HashSet[T]) None(predicate func(v T) bool) bool {
+	return !anyMatch(set.ForEach, predicate)
+}
+
+// CountIf returns the number of items of the set for which `predicate` returns true.
+func (set *HashSet[T]) CountIf(predicate func(v T) bool) int {
+	return countMatch(set.ForEach, predicate)
+}
+
 // LockFunc locks writing with callback function `f`.
 func (set *HashSet[T]) LockFunc(f func(m map[T]struct{})) {
 	set.mu.Lock()
 	defer set.mu.Unlock()
+	// The index of Get is dropped, as `f` may modify the items.
+	set.stored = nil
 	f(set.data)
 }
 
@@ -434,8 +594,26 @@ func (set *HashSet[T]) Complement(full *HashSet[T]) (newSet *HashSet[T]) {
 	return
 }
 
+// Combinations calls `f` with every combination of `k` distinct items of the set, until `f` returns false.
+// The slice passed to `f` is reused between the calls, so it must be copied to be retained.
+// The items are taken as a snapshot, so `f` can modify the set.
+func (set *HashSet[T]) Combinations(k int, f func(combination []T) bool) {
+	forEachCombination(set.Slice(), k, f)
+}
+
+// PowerSet calls `f` with every subset of the set as a new HashSet, including the empty set and the set itself,
+// in ascending order of their sizes, until `f` returns false. It makes 2^n calls for a set of size n,
+// so it's meant for small sets.
+func (set *HashSet[T]) PowerSet(f func(subset Set[T]) bool) {
+	forEachSubset(set.Slice(), func(items []T) Set[T] {
+		return NewHashSetFrom(items)
+	}, f)
+}
+
 // Merge adds items from `others` sets into `set`.
 func (set *HashSet[T]) Merge(others ...*HashSet[T]) *HashSet[T] {
+	var added []T
+	defer func() { set.notifyAdd(added) }()
 	set.mu.Lock()
 	defer set.mu.Unlock()
 	for _, other := range others {
@@ -443,6 +621,10 @@ func (set *HashSet[T]) Merge(others ...*HashSet[T]) *HashSet[T] {
 			other.mu.RLock()
 		}
 		for k, v := range other.data {
+			if _, found := set.data[k]; !found {
+				set.storeWithoutLock(k)
+				added = set.appendIfWatched(added, set.onAdd, k)
+			}
 			set.data[k] = v
 		}
 		if set != other {
@@ -452,6 +634,55 @@ func (set *HashSet[T]) Merge(others ...*HashSet[T]) *HashSet[T] {
 	return set
 }
 
+// DiffInPlace removes the items which are in any of `others` from `set`, and returns `set`.
+// It's the in-place variant of Diff, which allocates no new set.
+func (set *HashSet[T]) DiffInPlace(others ...*HashSet[T]) *HashSet[T] {
+	var removed []T
+	defer func() { set.notifyRemove(removed) }()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	for _, other := range others {
+		if set != other {
+			other.mu.RLock()
+		}
+		for k := range set.data {
+			if _, ok := other.data[k]; ok {
+				delete(set.data, k)
+				set.unstoreWithoutLock(k)
+				removed = set.appendIfWatched(removed, set.onRemove, k)
+			}
+		}
+		if set != other {
+			other.mu.RUnlock()
+		}
+	}
+	return set
+}
+
+// IntersectInPlace removes the items which are not in all of `others` from `set`, and returns `set`.
+// It's the in-place variant of Intersect, which allocates no new set.
+func (set *HashSet[T]) IntersectInPlace(others ...*HashSet[T]) *HashSet[T] {
+	var removed []T
+	defer func() { set.notifyRemove(removed) }()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	for _, other := range others {
+		if set == other {
+			continue
+		}
+		other.mu.RLock()
+		for k := range set.data {
+			if _, ok := other.data[k]; !ok {
+				delete(set.data, k)
+				set.unstoreWithoutLock(k)
+				removed = set.appendIfWatched(removed, set.onRemove, k)
+			}
+		}
+		other.mu.RUnlock()
+	}
+	return set
+}
+
 // Sum sums items.
 // Note: The items should be converted to int type,
 // or you'd get a result that you unexpected.
@@ -466,10 +697,14 @@ func (set *HashSet[T]) Sum() (sum int) {
 
 // Pop randomly pops an item from set.
 func (set *HashSet[T]) Pop() (value T) {
+	var removed []T
+	defer func() { set.notifyRemove(removed) }()
 	set.mu.Lock()
 	defer set.mu.Unlock()
 	for k := range set.data {
 		delete(set.data, k)
+		set.unstoreWithoutLock(k)
+		removed = set.appendIfWatched(removed, set.onRemove, k)
 		return k
 	}
 	return
@@ -477,7 +712,8 @@ func (set *HashSet[T]) Pop() (value T) {
 
 // Pops randomly pops `size` items from set.
 // It returns all items if size == -1.
-func (set *HashSet[T]) Pops(size int) []T {
+func (set *HashSet[T]) Pops(size int) (array []T) {
+	defer func() { set.notifyRemove(array) }()
 	set.mu.Lock()
 	defer set.mu.Unlock()
 	if size > len(set.data) || size == -1 {
@@ -487,9 +723,10 @@ func (set *HashSet[T]) Pops(size int) []T {
 		return nil
 	}
 	index := 0
-	array := make([]T, size)
+	array = make([]T, size)
 	for k := range set.data {
 		delete(set.data, k)
+		set.unstoreWithoutLock(k)
 		array[index] = k
 		index++
 		if index == size {
@@ -499,6 +736,45 @@ func (set *HashSet[T]) Pops(size int) []T {
 	return array
 }
 
+// PopsWhere pops at most `limit` items for which `predicate` returns true from set, and returns them.
+// It pops all the matched items if limit == -1.
+// The items are matched and removed with the set locked, so concurrent callers never pop the same item,
+// e.g. when claiming the pending jobs whose ids are held in the set.
+func (set *HashSet[T]) PopsWhere(predicate func(item T) bool, limit int) (array []T) {
+	defer func() { set.notifyRemove(array) }()
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if limit == 0 {
+		return nil
+	}
+	for k := range set.data {
+		if !predicate(k) {
+			continue
+		}
+		delete(set.data, k)
+		set.unstoreWithoutLock(k)
+		array = append(array, k)
+		if len(array) == limit {
+			break
+		}
+	}
+	return array
+}
+
+// Filter returns a new set with the items for which `predicate` returns true.
+// The new set is concurrent-safe if the set is.
+func (set *HashSet[T]) Filter(predicate func(item T) bool) Set[T] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	newSet := NewHashSet[T](set.mu.IsSafe())
+	for k := range set.data {
+		if predicate(k) {
+			newSet.data[k] = struct{}{}
+		}
+	}
+	return newSet
+}
+
 // Walk applies a user supplied function `f` to every item of set.
 func (set *HashSet[T]) Walk(f func(item T) T) *HashSet[T] {
 	set.mu.Lock()
@@ -508,12 +784,41 @@ func (set *HashSet[T]) Walk(f func(item T) T) *HashSet[T] {
 		m[f(k)] = v
 	}
 	set.data = m
+	set.stored = nil
 	return set
 }
 
+// SetMarshalSorted makes MarshalJSON emit the items in the order sorted by `comparator`,
+// so that the json of equal sets is identical, e.g. for content hashing or golden files.
+// The items are emitted in random order if `comparator` is nil, which is the default.
+// The setting is kept by Clone.
+func (set *HashSet[T]) SetMarshalSorted(comparator comparators.Comparator[T]) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.marshalCmp = comparator
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (set *HashSet[T]) SetMarshalOptions(options JSONOptions) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (set HashSet[T]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(set.Slice())
+	// The items and the settings are read under the same lock, as SetMarshalSorted may be called concurrently.
+	set.mu.RLock()
+	array := make([]T, 0, len(set.data))
+	for item := range set.data {
+		array = append(array, item)
+	}
+	comparator, options := set.marshalCmp, set.jsonOptions
+	set.mu.RUnlock()
+	if comparator != nil {
+		slices.SortFunc(array, comparator)
+	}
+	return marshalJSONSlice(options, array)
 }
 
 // UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
@@ -529,6 +834,7 @@ func (set *HashSet[T]) UnmarshalJSON(b []byte) error {
 	}
 	for _, v := range array {
 		set.data[v] = struct{}{}
+		set.storeWithoutLock(v)
 	}
 	return nil
 }
@@ -549,6 +855,7 @@ func (set *HashSet[T]) UnmarshalValue(value interface{}) (err error) {
 	}
 	for _, v := range array {
 		set.data[v] = struct{}{}
+		set.storeWithoutLock(v)
 	}
 	return
 }
@@ -566,3 +873,29 @@ func (set *HashSet[T]) DeepCopy() Collection[T] {
 	}
 	return NewHashSetFrom[T](data, set.mu.IsSafe())
 }
+
+// MapSet returns a new HashSet with the results of calling `f` on every item of `set`,
+// in which the duplicated results are merged.
+// The parameter `safe` is used to specify whether using the new set in concurrent-safety,
+// which is false in default.
+func MapSet[T, U comparable](set Set[T], f func(item T) U, safe ...bool) *HashSet[U] {
+	newSet := NewHashSet[U](safe...)
+	set.ForEach(func(item T) bool {
+		newSet.data[f(item)] = struct{}{}
+		return true
+	})
+	return newSet
+}
+
+// ReduceSet folds the items of `set` into a single value by calling `f` on every item with the accumulated
+// value, which starts from `init`, and returns the final accumulated value, or `init` if the set is empty.
+// As the iteration order of sets is undefined, `f` should not depend on the order of the items,
+// like sum, min and max.
+func ReduceSet[T comparable, A any](set Set[T], init A, f func(acc A, item T) A) A {
+	result := init
+	set.ForEach(func(item T) bool {
+		result = f(result, item)
+		return true
+	})
+	return result
+}
@@ -0,0 +1,67 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// OnAdd registers observer `f`, which is called with each item added to the set by Add, AddAll and Merge.
+// Items which are already in the set are not notified.
+//
+// The observers are called after the set is unlocked, so they can call methods of the set,
+// but the set may have been changed again by other goroutines when they are called.
+// Changes made by LockFunc, Walk and unmarshalling are not notified, and the observers are not copied by Clone.
+func (set *HashSet[T]) OnAdd(f func(item T)) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.onAdd = append(set.onAdd, f)
+}
+
+// OnRemove registers observer `f`, which is called with each item removed from the set
+// by Remove, RemoveAll, RemoveIf, Clear, Pop and Pops. Items which are not in the set are not notified.
+// The observers are called after the set is unlocked like the ones of OnAdd.
+func (set *HashSet[T]) OnRemove(f func(item T)) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.onRemove = append(set.onRemove, f)
+}
+
+// appendIfWatched appends `item` to `items` only if there are `observers` to notify,
+// so that the mutations don't collect the items without observers.
+func (set *HashSet[T]) appendIfWatched(items []T, observers []func(item T), item T) []T {
+	if len(observers) == 0 {
+		return items
+	}
+	return append(items, item)
+}
+
+// notifyAdd calls the observers registered by OnAdd with `items`. It must be called without the lock.
+func (set *HashSet[T]) notifyAdd(items []T) {
+	if len(items) == 0 {
+		return
+	}
+	set.mu.RLock()
+	observers := set.onAdd
+	set.mu.RUnlock()
+	for _, item := range items {
+		for _, f := range observers {
+			f(item)
+		}
+	}
+}
+
+// notifyRemove calls the observers registered by OnRemove with `items`. It must be called without the lock.
+func (set *HashSet[T]) notifyRemove(items []T) {
+	if len(items) == 0 {
+		return
+	}
+	set.mu.RLock()
+	observers := set.onRemove
+	set.mu.RUnlock()
+	for _, item := range items {
+		for _, f := range observers {
+			f(item)
+		}
+	}
+}
@@ -9,6 +9,8 @@
 package g_test
 
 import (
+	"math"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -17,6 +19,7 @@ import (
 	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/internal/gtest"
 	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/utils/comparators"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
@@ -470,6 +473,350 @@ func TestHashSet_UnmarshalValue(t *testing.T) {
 	})
 }
 
+func TestHashSet_Combinations(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]string{"a", "b", "c", "d", "e"})
+		seen := g.NewHashSet[string]()
+		s.Combinations(3, func(combination []string) bool {
+			t.Assert(len(combination), 3)
+			sorted := append([]string(nil), combination...)
+			slices.Sort(sorted)
+			seen.Add(strings.Join(sorted, ""))
+			return true
+		})
+		t.Assert(seen.Size(), 10)
+
+		subsets := 0
+		s.PowerSet(func(subset g.Set[string]) bool {
+			t.Assert(s.ContainsAll(subset), true)
+			subsets++
+			return true
+		})
+		t.Assert(subsets, 32)
+	})
+}
+
+func TestHashSet_Predicates(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4})
+		even := func(v int) bool { return v%2 == 0 }
+		positive := func(v int) bool { return v > 0 }
+		t.Assert(s.Any(even), true)
+		t.Assert(s.All(even), false)
+		t.Assert(s.All(positive), true)
+		t.Assert(s.None(even), false)
+		t.Assert(s.None(func(v int) bool { return v > 4 }), true)
+		t.Assert(s.CountIf(even), 2)
+
+		calls := 0
+		s.Any(func(v int) bool {
+			calls++
+			return true
+		})
+		t.Assert(calls, 1)
+
+		empty := g.NewHashSet[int]()
+		t.Assert(empty.Any(positive), false)
+		t.Assert(empty.All(positive), true)
+		t.Assert(empty.None(positive), true)
+		t.Assert(empty.CountIf(positive), 0)
+	})
+}
+
+func TestHashSet_FilterMap(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4, 5})
+		odd := s.Filter(func(v int) bool { return v%2 == 1 })
+		t.Assert(odd.Size(), 3)
+		t.Assert(odd.ContainsAll(g.NewArrayListFrom([]int{1, 3, 5})), true)
+		t.Assert(odd.Contains(2), false)
+		t.Assert(s.Size(), 5)
+
+		type user struct {
+			id   int
+			name string
+		}
+		users := g.NewHashSetFrom([]user{{1, "a"}, {2, "b"}, {3, "a"}})
+		names := g.MapSet[user](users, func(u user) string { return u.name })
+		t.Assert(names.Size(), 2)
+		t.Assert(names.Contains("a"), true)
+		t.Assert(names.Contains("b"), true)
+
+		squares := g.MapSet[int](g.NewTreeSetFrom([]int{-2, 2, 3}, comparators.ComparatorInt), func(v int) int { return v * v })
+		t.Assert(squares.Size(), 2)
+		t.Assert(squares.Contains(4), true)
+		t.Assert(squares.Contains(9), true)
+	})
+}
+
+func TestHashSet_NewFromMap(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetOf(1, 2, 2, 3)
+		t.Assert(s.Size(), 3)
+		t.Assert(s.Contains(2), true)
+		t.Assert(g.NewHashSetOf[int]().IsEmpty(), true)
+
+		m := g.NewHashMapFrom(map[string]int{"a": 1, "b": 2, "c": 1})
+		keys := g.NewHashSetFromKeys[string, int](m)
+		t.Assert(keys.Size(), 3)
+		t.Assert(keys.ContainsAll(g.NewArrayListFrom([]string{"a", "b", "c"})), true)
+		values := g.NewHashSetFromValues[string, int](m, true)
+		t.Assert(values.Size(), 2)
+		t.Assert(values.Contains(1), true)
+		t.Assert(values.Contains(2), true)
+	})
+}
+
+func TestHashSet_RemoveIf(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4, 5, 6}, true)
+		t.Assert(s.RemoveIf(func(v int) bool { return v%2 == 0 }), 3)
+		t.Assert(s.Size(), 3)
+		t.Assert(s.ContainsAll(g.NewArrayListFrom([]int{1, 3, 5})), true)
+		t.Assert(s.RemoveIf(func(v int) bool { return v > 10 }), 0)
+		t.Assert(s.Size(), 3)
+	})
+}
+
+func TestHashSet_OnAddRemove(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			s       = g.NewHashSet[int](true)
+			added   = g.NewArrayList[int]()
+			removed = g.NewArrayList[int]()
+		)
+		s.OnAdd(func(item int) {
+			// The observers are called outside the lock, so they can read the set.
+			t.Assert(s.Contains(item), true)
+			added.Add(item)
+		})
+		s.OnRemove(func(item int) {
+			removed.Add(item)
+		})
+		s.Add(1, 2, 2)
+		s.AddAll(g.NewArrayListFrom([]int{2, 3}))
+		s.Merge(g.NewHashSetFrom([]int{3, 4}))
+		t.Assert(added.Slice(), []int{1, 2, 3, 4})
+
+		s.Remove(1, 5)
+		s.RemoveAll(g.NewArrayListFrom([]int{2, 6}))
+		t.Assert(removed.Slice(), []int{1, 2})
+		s.RemoveIf(func(v int) bool { return v == 3 })
+		t.Assert(removed.Slice(), []int{1, 2, 3})
+		s.Pop()
+		t.Assert(removed.Slice(), []int{1, 2, 3, 4})
+
+		s.Add(7, 8)
+		s.Pops(1)
+		t.Assert(removed.Len(), 5)
+		s.Clear()
+		t.Assert(removed.Len(), 6)
+		t.Assert(removed.Slice()[4]+removed.Slice()[5], 15)
+		t.Assert(added.Len(), 6)
+	})
+}
+
+func TestHashSet_Get(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// Positive and negative zeros are equal, but distinguishable by the sign bit.
+		negZero := math.Copysign(0, -1)
+		s := g.NewHashSetFrom([]float64{0, 1})
+		v, ok := s.Get(negZero)
+		t.Assert(ok, true)
+		t.Assert(math.Signbit(v), false)
+		_, ok = s.Get(2)
+		t.Assert(ok, false)
+
+		s.Remove(0)
+		_, ok = s.Get(negZero)
+		t.Assert(ok, false)
+		s.Add(negZero)
+		v, ok = s.Get(0)
+		t.Assert(ok, true)
+		t.Assert(math.Signbit(v), true)
+
+		s.Clear()
+		s.Add(0)
+		v, ok = s.Get(negZero)
+		t.Assert(ok, true)
+		t.Assert(math.Signbit(v), false)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]string{"ab", "cd"}, true)
+		v, ok := s.Get(strings.Repeat("a", 1) + "b")
+		t.Assert(ok, true)
+		t.Assert(v, "ab")
+		s.RemoveIf(func(item string) bool { return item == "ab" })
+		_, ok = s.Get("ab")
+		t.Assert(ok, false)
+		v, ok = s.Get("cd")
+		t.Assert(ok, true)
+		t.Assert(v, "cd")
+	})
+}
+
+func TestHashSet_AddRetainRemoveAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3}, true)
+		t.Assert(s.AddAll(g.NewArrayListFrom([]int{3, 4})), true)
+		t.Assert(s.AddAll(g.NewArrayListFrom([]int{1, 4})), false)
+		t.Assert(s.AddAll(s), false)
+		t.Assert(s.Size(), 4)
+
+		t.Assert(s.RemoveAll(g.NewArrayListFrom([]int{5, 6})), false)
+		t.Assert(s.Remove(5), false)
+		t.Assert(s.RemoveAll(g.NewArrayListFrom([]int{4, 5})), true)
+		t.Assert(s.Size(), 3)
+
+		t.Assert(s.RetainAll(g.NewHashSetFrom([]int{1, 2, 3, 4})), false)
+		t.Assert(s.RetainAll(s), false)
+		t.Assert(s.RetainAll(g.NewArrayListFrom([]int{2, 3, 5})), true)
+		t.Assert(s.Contains(1), false)
+		t.Assert(s.Size(), 2)
+
+		t.Assert(s.RemoveAll(s), true)
+		t.Assert(s.IsEmpty(), true)
+		t.Assert(s.RetainAll(g.NewHashSet[int]()), false)
+	})
+}
+
+func TestHashSet_SetMarshalSorted(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{5, 3, 9, 1, 7}, true)
+		s.SetMarshalSorted(comparators.ComparatorInt)
+		for i := 0; i < 10; i++ {
+			b, err := json.Marshal(s)
+			t.AssertNil(err)
+			t.Assert(string(b), `[1,3,5,7,9]`)
+		}
+		b, err := json.Marshal(s.Clone())
+		t.AssertNil(err)
+		t.Assert(string(b), `[1,3,5,7,9]`)
+
+		s.SetMarshalSorted(func(a, b int) int { return b - a })
+		b, err = json.Marshal(s)
+		t.AssertNil(err)
+		t.Assert(string(b), `[9,7,5,3,1]`)
+
+		s.SetMarshalSorted(nil)
+		var s2 g.HashSet[int]
+		b, err = json.Marshal(s)
+		t.AssertNil(err)
+		t.AssertNil(json.Unmarshal(b, &s2))
+		t.Assert(s2.Equals(s), true)
+	})
+}
+
+func TestReduceSet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4})
+		t.Assert(g.ReduceSet[int](s, 0, func(acc int, item int) int { return acc + item }), 10)
+		t.Assert(g.ReduceSet[int](s, 100, func(acc int, item int) int { return min(acc, item) }), 1)
+		t.Assert(g.ReduceSet[int](g.NewHashSet[int](), 7, func(acc int, item int) int { return acc + item }), 7)
+
+		words := g.NewTreeSetFrom([]string{"b", "c", "a"}, comparators.ComparatorString)
+		t.Assert(g.ReduceSet[string](words, "", func(acc string, item string) string { return acc + item }), "abc")
+		t.Assert(g.ReduceSet[string](words, 0, func(acc int, item string) int { return acc + len(item) }), 3)
+	})
+}
+
+func TestHashSet_PopsWhere(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4, 5, 6}, true)
+		even := func(item int) bool { return item%2 == 0 }
+		t.Assert(s.PopsWhere(even, 0), nil)
+		array := s.PopsWhere(even, 2)
+		t.Assert(len(array), 2)
+		for _, v := range array {
+			t.Assert(v%2, 0)
+			t.Assert(s.Contains(v), false)
+		}
+		t.Assert(s.Size(), 4)
+		t.Assert(len(s.PopsWhere(even, 10)), 1)
+		t.Assert(s.PopsWhere(even, -1), nil)
+
+		array = s.PopsWhere(func(item int) bool { return true }, -1)
+		slices.Sort(array)
+		t.Assert(array, []int{1, 3, 5})
+		t.Assert(s.IsEmpty(), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			s       = g.NewHashSet[int](true)
+			wg      sync.WaitGroup
+			claimed = make([][]int, 4)
+		)
+		for i := 0; i < 1000; i++ {
+			s.Add(i)
+		}
+		for i := range claimed {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					array := s.PopsWhere(func(item int) bool { return item%3 != 0 }, 10)
+					if len(array) == 0 {
+						return
+					}
+					claimed[i] = append(claimed[i], array...)
+				}
+			}()
+		}
+		wg.Wait()
+		all := g.NewHashSet[int]()
+		total := 0
+		for _, array := range claimed {
+			all.Add(array...)
+			total += len(array)
+		}
+		t.Assert(total, 666)
+		t.Assert(all.Size(), 666)
+		t.Assert(s.Size(), 334)
+	})
+}
+
+func TestHashSet_InPlace(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4, 5}, true)
+		s1 := g.NewHashSetFrom([]int{1, 2, 6}, true)
+		s2 := g.NewHashSetFrom([]int{5}, true)
+		t.Assert(s.DiffInPlace(s1, s2) == s, true)
+		t.Assert(s.Equals(g.NewHashSetFrom([]int{3, 4})), true)
+		t.Assert(s1.Size(), 3)
+		s.DiffInPlace(s)
+		t.Assert(s.IsEmpty(), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2, 3, 4, 5}, true)
+		s1 := g.NewHashSetFrom([]int{1, 2, 3, 6}, true)
+		s2 := g.NewHashSetFrom([]int{2, 3, 4}, true)
+		t.Assert(s.IntersectInPlace(s1, s, s2) == s, true)
+		t.Assert(s.Equals(g.NewHashSetFrom([]int{2, 3})), true)
+		t.Assert(s.Equals(s1.Intersect(s2)), true)
+		s.IntersectInPlace()
+		t.Assert(s.Size(), 2)
+		s.IntersectInPlace(g.NewHashSet[int]())
+		t.Assert(s.IsEmpty(), true)
+	})
+}
+
+func TestHashSet_AddRemoveAllCount(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewHashSetFrom([]int{1, 2}, true)
+		t.Assert(s.AddAllCount(2, 3, 4, 3), 2)
+		t.Assert(s.AddAllCount(1, 2), 0)
+		t.Assert(s.AddAllCount(), 0)
+		t.Assert(s.Size(), 4)
+		t.Assert(s.RemoveAllCount(4, 5, 1, 1), 2)
+		t.Assert(s.RemoveAllCount(6), 0)
+		t.Assert(s.Equals(g.NewHashSetFrom([]int{2, 3})), true)
+
+		var s2 g.HashSet[string]
+		t.Assert(s2.RemoveAllCount("a"), 0)
+		t.Assert(s2.AddAllCount("a", "b"), 2)
+	})
+}
+
 func TestHashSet_DeepCopy(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		set := g.NewHashSet[int]()
@@ -0,0 +1,83 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"hash/maphash"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/utils/comparators"
+)
+
+func TestHash64_Array(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		seed := maphash.MakeSeed()
+		a1 := g.NewArrayListFrom([]int{1, 2, 3})
+		a2 := g.NewArrayListFrom([]int{1, 2, 3})
+		a3 := g.NewArrayListFrom([]int{3, 2, 1})
+		t.Assert(a1.Hash64(seed), a2.Hash64(seed))
+		t.AssertNE(a1.Hash64(seed), a3.Hash64(seed))
+		t.Assert(a1.Hash64(seed), g.NewLinkedListFrom([]int{1, 2, 3}).Hash64(seed))
+
+		s1 := g.NewArrayListFrom([]string{"ab", "c"})
+		s2 := g.NewArrayListFrom([]string{"a", "bc"})
+		t.AssertNE(s1.Hash64(seed), s2.Hash64(seed))
+		t.AssertNE(g.NewArrayList[string]().Hash64(seed), g.NewArrayListFrom([]string{""}).Hash64(seed))
+	})
+}
+
+func TestHash64_Set(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		seed := maphash.MakeSeed()
+		s1 := g.NewHashSetFrom([]string{"a", "b", "c"})
+		s2 := g.NewTreeSetFrom([]string{"c", "b", "a"}, comparators.ComparatorString)
+		s3 := g.NewSmallSetFrom([]string{"b", "a", "c"})
+		t.Assert(s1.Hash64(seed), s2.Hash64(seed))
+		t.Assert(s1.Hash64(seed), s3.Hash64(seed))
+		s3.Remove("c")
+		t.AssertNE(s1.Hash64(seed), s3.Hash64(seed))
+	})
+}
+
+func TestHash64_Map(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		seed := maphash.MakeSeed()
+		data := map[string]int{"a": 1, "b": 2, "c": 3}
+		m1 := g.NewHashMapFrom(data)
+		m2 := g.NewTreeMapFrom[string, int](comparators.ComparatorString, data)
+		m3 := g.NewListMap[string, int]()
+		m3.Put("c", 3)
+		m3.Put("a", 1)
+		m3.Put("b", 2)
+		t.Assert(m1.Hash64(seed), m2.Hash64(seed))
+		t.Assert(m1.Hash64(seed), m3.Hash64(seed))
+		m3.Put("b", 4)
+		t.AssertNE(m1.Hash64(seed), m3.Hash64(seed))
+		// Swapped keys and values.
+		t.AssertNE(
+			g.NewHashMapFrom(map[int]int{1: 2}).Hash64(seed),
+			g.NewHashMapFrom(map[int]int{2: 1}).Hash64(seed),
+		)
+	})
+}
+
+func TestHash64_Nested(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		seed := maphash.MakeSeed()
+		m1 := g.NewHashMap[string, *g.HashSet[int]]()
+		m1.Put("x", g.NewHashSetFrom([]int{1, 2}))
+		m2 := g.NewHashMap[string, *g.HashSet[int]]()
+		m2.Put("x", g.NewHashSetFrom([]int{2, 1}))
+		t.Assert(m1.Hash64(seed), m2.Hash64(seed))
+		m2.Get("x").Add(3)
+		t.AssertNE(m1.Hash64(seed), m2.Hash64(seed))
+	})
+}
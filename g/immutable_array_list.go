@@ -0,0 +1,260 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/utils/equal"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+	"github.com/wesleywu/gcontainer/utils/gstr"
+)
+
+// ImmutableArrayList is a read-only array, which has no mutating methods at all, so it can be shared
+// with any code, e.g. plugins, without defensive copies. It is concurrent-safe without any lock,
+// as its elements are never changed after it is created.
+//
+// It is created by ArrayList.Freeze or ArrayListBuilder.Build.
+// Note that elements of pointer or reference types are not frozen themselves.
+type ImmutableArrayList[T any] struct {
+	array       []T
+	jsonOptions *JSONOptions // Options of MarshalJSON set by WithMarshalOptions, nil for the default.
+}
+
+// ArrayListBuilder is used to construct an ImmutableArrayList by appending elements.
+// It is not concurrent-safe.
+type ArrayListBuilder[T any] struct {
+	array []T
+}
+
+// Freeze returns an ImmutableArrayList with a copy of current array.
+func (a *ArrayList[T]) Freeze() *ImmutableArrayList[T] {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	array := make([]T, len(a.array))
+	copy(array, a.array)
+	return &ImmutableArrayList[T]{array: array}
+}
+
+// NewArrayListBuilder creates and returns a builder of ImmutableArrayList.
+// The optional parameter `capacity` is used to preallocate space for elements.
+func NewArrayListBuilder[T any](capacity ...int) *ArrayListBuilder[T] {
+	b := &ArrayListBuilder[T]{}
+	if len(capacity) > 0 && capacity[0] > 0 {
+		b.array = make([]T, 0, capacity[0])
+	}
+	return b
+}
+
+// Add appends `values` to the builder.
+func (b *ArrayListBuilder[T]) Add(values ...T) *ArrayListBuilder[T] {
+	b.array = append(b.array, values...)
+	return b
+}
+
+// AddAll appends all the elements of `values` to the builder.
+func (b *ArrayListBuilder[T]) AddAll(values Collection[T]) *ArrayListBuilder[T] {
+	values.ForEach(func(value T) bool {
+		b.array = append(b.array, value)
+		return true
+	})
+	return b
+}
+
+// Len returns the number of elements appended to the builder.
+func (b *ArrayListBuilder[T]) Len() int {
+	return len(b.array)
+}
+
+// Build returns an ImmutableArrayList of the appended elements, and resets the builder,
+// so that the builder can be reused without affecting the built array.
+func (b *ArrayListBuilder[T]) Build() *ImmutableArrayList[T] {
+	array := b.array
+	b.array = nil
+	return &ImmutableArrayList[T]{array: array}
+}
+
+// Get returns the value by the specified index.
+// If the given `index` is out of range of the array, the `found` is false.
+func (a *ImmutableArrayList[T]) Get(index int) (value T, found bool) {
+	if index < 0 || index >= len(a.array) {
+		return
+	}
+	return a.array[index], true
+}
+
+// MustGet returns the value by the specified index.
+// If the given `index` is out of range of the array, it returns empty value of type T.
+func (a *ImmutableArrayList[T]) MustGet(index int) (value T) {
+	value, _ = a.Get(index)
+	return
+}
+
+// Len returns the length of array.
+func (a *ImmutableArrayList[T]) Len() int {
+	return len(a.array)
+}
+
+// Size returns the length of array.
+func (a *ImmutableArrayList[T]) Size() int {
+	return len(a.array)
+}
+
+// IsEmpty checks whether the array is empty.
+func (a *ImmutableArrayList[T]) IsEmpty() bool {
+	return len(a.array) == 0
+}
+
+// Slice returns a copy of the elements of array.
+func (a *ImmutableArrayList[T]) Slice() []T {
+	array := make([]T, len(a.array))
+	copy(array, a.array)
+	return array
+}
+
+// Search searches array by `value`, returns the index of `value`,
+// or returns -1 if not exists.
+func (a *ImmutableArrayList[T]) Search(value T) int {
+	return doSearchSlice(a.array, value)
+}
+
+// Contains checks whether a value exists in the array.
+func (a *ImmutableArrayList[T]) Contains(value T) bool {
+	return a.Search(value) != -1
+}
+
+// ForEach iterates the array with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (a *ImmutableArrayList[T]) ForEach(f func(value T) bool) {
+	for _, v := range a.array {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// ForEachAsc iterates the array in ascending order with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (a *ImmutableArrayList[T]) ForEachAsc(f func(index int, value T) bool) {
+	for i, v := range a.array {
+		if !f(i, v) {
+			return
+		}
+	}
+}
+
+// ForEachDesc iterates the array in descending order with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (a *ImmutableArrayList[T]) ForEachDesc(f func(index int, value T) bool) {
+	for i := len(a.array) - 1; i >= 0; i-- {
+		if !f(i, a.array[i]) {
+			return
+		}
+	}
+}
+
+// Equals checks whether the two arrays have equal elements in the same order.
+func (a *ImmutableArrayList[T]) Equals(another *ImmutableArrayList[T]) bool {
+	if a == another {
+		return true
+	}
+	if len(a.array) != len(another.array) {
+		return false
+	}
+	for i, v := range a.array {
+		if !equal.Equals(v, another.array[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Thaw returns a mutable ArrayList with a copy of the elements.
+// The parameter `safe` is used to specify whether using array in concurrent-safety,
+// which is false in default.
+func (a *ImmutableArrayList[T]) Thaw(safe ...bool) *ArrayList[T] {
+	return NewArrayListFrom[T](a.Slice(), safe...)
+}
+
+// Join joins array elements with a string `glue`.
+func (a *ImmutableArrayList[T]) Join(glue string) string {
+	buffer := bytes.NewBuffer(nil)
+	for i, v := range a.array {
+		if i > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(gconv.String(v))
+	}
+	return buffer.String()
+}
+
+// JoinFunc joins array elements with a string `glue`, each of which is formatted by `format`.
+func (a *ImmutableArrayList[T]) JoinFunc(glue string, format func(value T) string) string {
+	buffer := bytes.NewBuffer(nil)
+	for i, v := range a.array {
+		if i > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(format(v))
+	}
+	return buffer.String()
+}
+
+// String returns current array as a string, which implements like json.Marshal does.
+func (a *ImmutableArrayList[T]) String() string {
+	if a == nil {
+		return ""
+	}
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteByte('[')
+	for i, v := range a.array {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		s := gconv.String(v)
+		if gstr.IsNumeric(s) {
+			buffer.WriteString(s)
+		} else {
+			buffer.WriteString(`"` + gstr.QuoteMeta(s, `"\`) + `"`)
+		}
+	}
+	buffer.WriteByte(']')
+	return buffer.String()
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (a *ImmutableArrayList[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, a)
+}
+
+// formatEntries implements the interface formattable.
+func (a *ImmutableArrayList[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	return collectFormatValues(a.ForEach, len(a.array), limit), false, len(a.array)
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is sensitive to the order of elements.
+func (a *ImmutableArrayList[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashOrdered(seed, a.ForEach)
+}
+
+// WithMarshalOptions returns a copy of the array sharing the same elements, of which MarshalJSON applies
+// `options` to the elements, see JSONOptions. The array itself is not changed as it is immutable.
+func (a *ImmutableArrayList[T]) WithMarshalOptions(options JSONOptions) *ImmutableArrayList[T] {
+	return &ImmutableArrayList[T]{
+		array:       a.array,
+		jsonOptions: newJSONOptions(options),
+	}
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (a *ImmutableArrayList[T]) MarshalJSON() ([]byte, error) {
+	return marshalJSONSlice(a.jsonOptions, a.array)
+}
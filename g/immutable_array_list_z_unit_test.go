@@ -0,0 +1,80 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestImmutableArrayList_Freeze(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]int{1, 2, 3})
+		frozen := array.Freeze()
+		array.Set(0, 100)
+		array.Add(4)
+		t.Assert(frozen.Slice(), []int{1, 2, 3})
+		t.Assert(frozen.Len(), 3)
+		t.Assert(frozen.Size(), 3)
+		t.Assert(frozen.IsEmpty(), false)
+		t.Assert(frozen.MustGet(2), 3)
+		_, found := frozen.Get(3)
+		t.Assert(found, false)
+		t.Assert(frozen.Search(2), 1)
+		t.Assert(frozen.Contains(4), false)
+
+		// Slice returns a copy.
+		frozen.Slice()[0] = 100
+		t.Assert(frozen.MustGet(0), 1)
+
+		var desc []int
+		frozen.ForEachDesc(func(index int, value int) bool {
+			desc = append(desc, value)
+			return true
+		})
+		t.Assert(desc, []int{3, 2, 1})
+
+		thawed := frozen.Thaw()
+		thawed.Add(4)
+		t.Assert(thawed.Slice(), []int{1, 2, 3, 4})
+		t.Assert(frozen.Len(), 3)
+		t.Assert(frozen.Equals(thawed.Freeze()), false)
+		t.Assert(frozen.Equals(g.NewArrayListFrom([]int{1, 2, 3}).Freeze()), true)
+	})
+}
+
+func TestImmutableArrayList_Builder(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		builder := g.NewArrayListBuilder[string](4)
+		builder.Add("a", "b").AddAll(g.NewArrayListFrom([]string{"c"}))
+		t.Assert(builder.Len(), 3)
+		frozen := builder.Build()
+		t.Assert(builder.Len(), 0)
+
+		// Reusing the builder does not affect the built array.
+		builder.Add("x")
+		t.Assert(frozen.Slice(), []string{"a", "b", "c"})
+		t.Assert(builder.Build().Slice(), []string{"x"})
+
+		t.Assert(frozen.Join("-"), "a-b-c")
+		t.Assert(frozen.String(), `["a","b","c"]`)
+		t.Assert(fmt.Sprint(frozen), frozen.String())
+		b, err := json.Marshal(frozen)
+		t.AssertNil(err)
+		t.Assert(string(b), `["a","b","c"]`)
+
+		empty := g.NewArrayListBuilder[int]().Build()
+		t.Assert(empty.IsEmpty(), true)
+		t.Assert(empty.Join(","), "")
+	})
+}
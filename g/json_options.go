@@ -0,0 +1,429 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"encoding"
+	json2 "encoding/json"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+// NonFiniteFloatMode specifies how NaN and ±Inf float values are marshaled to json.
+type NonFiniteFloatMode int
+
+const (
+	// NonFiniteFloatError fails the marshaling, which is the behavior of stdlib json.
+	NonFiniteFloatError NonFiniteFloatMode = iota
+	// NonFiniteFloatNull marshals NaN and ±Inf as json null.
+	NonFiniteFloatNull
+	// NonFiniteFloatString marshals NaN and ±Inf as json strings "NaN", "+Inf" and "-Inf".
+	NonFiniteFloatString
+)
+
+// maxJSONSafeInteger is the max integer that can be represented exactly by float64, which is 2^53.
+const maxJSONSafeInteger = 1 << 53
+
+// maxJSONConvertDepth is the max depth of nested values converted by JSONOptions,
+// beyond which the values are marshaled as they are, e.g. for the cyclic pointers.
+const maxJSONConvertDepth = 1000
+
+// JSONOptions is the options applied by the MarshalJSON of containers to the elements, or to the values of maps.
+// The options are applied recursively to the nested values, like the fields of structs and the values of
+// slices and maps, except the values implementing json.Marshaler or encoding.TextMarshaler,
+// which are marshaled by themselves. The options are set for each container by its SetMarshalOptions.
+type JSONOptions struct {
+	// LargeIntAsString marshals integers beyond ±2^53 as json strings,
+	// so that they are not rounded by the consumers decoding numbers as float64, like javascript.
+	LargeIntAsString bool
+
+	// NonFiniteFloat specifies how NaN and ±Inf float values are marshaled.
+	NonFiniteFloat NonFiniteFloatMode
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json2.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// newJSONOptions returns the options to be stored by containers, which is nil for the default options,
+// so that the marshaling of containers with default options does not convert the values at all.
+func newJSONOptions(options JSONOptions) *JSONOptions {
+	if options == (JSONOptions{}) {
+		return nil
+	}
+	return &options
+}
+
+// marshalJSONValue marshals `value` to json with `options`, which can be nil.
+func marshalJSONValue(options *JSONOptions, value interface{}) ([]byte, error) {
+	if options != nil {
+		value = options.convert(value)
+	}
+	return json.Marshal(value)
+}
+
+// marshalJSONSlice marshals `values` to json array with `options`, which can be nil.
+func marshalJSONSlice[T any](options *JSONOptions, values []T) ([]byte, error) {
+	if options == nil {
+		return json.Marshal(values)
+	}
+	converted := make([]interface{}, len(values))
+	for i, v := range values {
+		converted[i] = options.convert(v)
+	}
+	return json.Marshal(converted)
+}
+
+// marshalJSONMap marshals `data` to json object with `options`, which can be nil.
+func marshalJSONMap(options *JSONOptions, data map[string]interface{}) ([]byte, error) {
+	if options != nil {
+		for k, v := range data {
+			data[k] = options.convert(v)
+		}
+	}
+	return json.Marshal(data)
+}
+
+// convert converts `value` to the value to be marshaled according to the options.
+func (options *JSONOptions) convert(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, string, bool:
+		return value
+	case float64:
+		return options.convertFloat(v, value)
+	case float32:
+		return options.convertFloat(float64(v), value)
+	case int:
+		return options.convertInt(int64(v), value)
+	case int64:
+		return options.convertInt(v, value)
+	case uint:
+		return options.convertUint(uint64(v), value)
+	case uint64:
+		return options.convertUint(v, value)
+	}
+	return options.convertValue(reflect.ValueOf(value), 0)
+}
+
+// convertValue converts the nested value `v` like convert, which returns the value marshaled
+// the same as `v` by json if nothing is converted.
+func (options *JSONOptions) convertValue(v reflect.Value, depth int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	t := v.Type()
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		return v.Interface()
+	}
+	if v.CanAddr() {
+		if pt := reflect.PointerTo(t); pt.Implements(jsonMarshalerType) || pt.Implements(textMarshalerType) {
+			return v.Addr().Interface()
+		}
+	}
+	if depth > maxJSONConvertDepth {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return options.convertFloat(v.Float(), v.Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return options.convertInt(v.Int(), v.Interface())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return options.convertUint(v.Uint(), v.Interface())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return options.convertValue(v.Elem(), depth+1)
+	case reflect.Slice:
+		// The byte slices are marshaled as base64 strings.
+		if v.IsNil() || t.Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		converted := make([]interface{}, v.Len())
+		for i := range converted {
+			converted[i] = options.convertValue(v.Index(i), depth+1)
+		}
+		return converted
+	case reflect.Map:
+		if v.IsNil() {
+			return v.Interface()
+		}
+		// The keys are kept in their original type, so that they are marshaled the same.
+		converted := reflect.MakeMapWithSize(reflect.MapOf(t.Key(), reflect.TypeOf((*interface{})(nil)).Elem()), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			value := options.convertValue(it.Value(), depth+1)
+			if value == nil {
+				converted.SetMapIndex(it.Key(), reflect.Zero(converted.Type().Elem()))
+			} else {
+				converted.SetMapIndex(it.Key(), reflect.ValueOf(value))
+			}
+		}
+		return converted.Interface()
+	case reflect.Struct:
+		return options.convertStruct(v, depth)
+	}
+	return v.Interface()
+}
+
+// convertStruct converts the fields of struct `v` to a jsonObject, following the rules of json
+// for the field names, the embedded structs and the tag options "-", "omitempty" and "string".
+func (options *JSONOptions) convertStruct(v reflect.Value, depth int) interface{} {
+	// Copies the struct to make its fields addressable like json does for the pointer receivers.
+	if !v.CanAddr() {
+		addressable := reflect.New(v.Type()).Elem()
+		addressable.Set(v)
+		v = addressable
+	}
+	object := make(jsonObject, 0, v.NumField())
+	for _, field := range jsonFields(v.Type()) {
+		fieldValue, err := v.FieldByIndexErr(field.index)
+		if err != nil {
+			// The field is promoted by a nil embedded pointer.
+			continue
+		}
+		if !fieldValue.CanInterface() {
+			// The field is promoted by an unexported embedded struct, which is marshaled by json as well.
+			fieldValue = reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
+		}
+		if field.omitEmpty && isEmptyJSONValue(fieldValue) {
+			continue
+		}
+		value := options.convertValue(fieldValue, depth+1)
+		if field.quoted {
+			value = quoteJSONValue(fieldValue, value)
+		}
+		object = append(object, jsonObjectField{name: field.name, value: value})
+	}
+	return object
+}
+
+// jsonField is a field of struct marshaled by json.
+type jsonField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+}
+
+// jsonFields returns the fields of struct type `t` marshaled by json in order.
+// Among the fields with the same name, the shallowest one wins, then the tagged one;
+// all of them are dropped if it's still ambiguous, as json does.
+func jsonFields(t reflect.Type) []jsonField {
+	var (
+		fields []jsonField
+		byName = make(map[string][]int)
+	)
+	collectJSONFields(t, nil, map[reflect.Type]bool{t: true}, &fields)
+	for i, field := range fields {
+		byName[field.name] = append(byName[field.name], i)
+	}
+	result := make([]jsonField, 0, len(fields))
+	for i, field := range fields {
+		if dominant, ok := dominantJSONField(fields, byName[field.name]); ok && dominant == i {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// collectJSONFields collects the fields of struct type `t` at `index` into `fields`,
+// including the ones promoted by the embedded structs without name in tag.
+func collectJSONFields(t reflect.Type, index []int, visiting map[reflect.Type]bool, fields *[]jsonField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous {
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := append(slices.Clip(index), i)
+		if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+			if !visiting[ft] {
+				visiting[ft] = true
+				collectJSONFields(ft, fieldIndex, visiting, fields)
+				delete(visiting, ft)
+			}
+			continue
+		}
+		field := jsonField{
+			name:   name,
+			index:  fieldIndex,
+			tagged: name != "",
+		}
+		if field.name == "" {
+			field.name = sf.Name
+		}
+		for opts != "" {
+			var opt string
+			opt, opts, _ = strings.Cut(opts, ",")
+			switch opt {
+			case "omitempty":
+				field.omitEmpty = true
+			case "string":
+				switch ft.Kind() {
+				case reflect.Bool, reflect.String,
+					reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+					reflect.Float32, reflect.Float64:
+					field.quoted = true
+				}
+			}
+		}
+		*fields = append(*fields, field)
+	}
+}
+
+// dominantJSONField returns the index of the dominant field among the fields of `indexes` with the same name.
+func dominantJSONField(fields []jsonField, indexes []int) (int, bool) {
+	if len(indexes) == 1 {
+		return indexes[0], true
+	}
+	depth := len(fields[indexes[0]].index)
+	for _, i := range indexes[1:] {
+		depth = min(depth, len(fields[i].index))
+	}
+	dominant, tagged, count := -1, 0, 0
+	for _, i := range indexes {
+		if len(fields[i].index) != depth {
+			continue
+		}
+		count++
+		if fields[i].tagged {
+			tagged++
+			dominant = i
+		} else if dominant == -1 {
+			dominant = i
+		}
+	}
+	if count == 1 || tagged == 1 {
+		return dominant, true
+	}
+	return -1, false
+}
+
+// isEmptyJSONValue checks whether `v` is empty for the tag option "omitempty" of json.
+func isEmptyJSONValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// quoteJSONValue encodes the `converted` value of field `v` with tag option "string" as json string,
+// unless it's converted to a string or null by the options already.
+func quoteJSONValue(v reflect.Value, converted interface{}) interface{} {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if converted == nil || (v.Kind() != reflect.String && reflect.TypeOf(converted).Kind() == reflect.String) {
+		return converted
+	}
+	b, err := json.Marshal(converted)
+	if err != nil {
+		return converted
+	}
+	return string(b)
+}
+
+// jsonObject is a json object of which the fields are marshaled in order.
+type jsonObject []jsonObjectField
+
+type jsonObjectField struct {
+	name  string
+	value interface{}
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (object jsonObject) MarshalJSON() ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	buffer.WriteByte('{')
+	for i, field := range object {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		nameBytes, err := json.Marshal(field.name)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(nameBytes)
+		buffer.WriteByte(':')
+		buffer.Write(valueBytes)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func (options *JSONOptions) convertFloat(f float64, value interface{}) interface{} {
+	if !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return value
+	}
+	switch options.NonFiniteFloat {
+	case NonFiniteFloatNull:
+		return nil
+	case NonFiniteFloatString:
+		switch {
+		case math.IsNaN(f):
+			return "NaN"
+		case f > 0:
+			return "+Inf"
+		default:
+			return "-Inf"
+		}
+	}
+	return value
+}
+
+func (options *JSONOptions) convertInt(i int64, value interface{}) interface{} {
+	if options.LargeIntAsString && (i > maxJSONSafeInteger || i < -maxJSONSafeInteger) {
+		return strconv.FormatInt(i, 10)
+	}
+	return value
+}
+
+func (options *JSONOptions) convertUint(u uint64, value interface{}) interface{} {
+	if options.LargeIntAsString && u > maxJSONSafeInteger {
+		return strconv.FormatUint(u, 10)
+	}
+	return value
+}
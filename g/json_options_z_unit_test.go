@@ -0,0 +1,154 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/utils/comparators"
+)
+
+func Test_JSONOptions_NonFiniteFloat(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		array := g.NewArrayListFrom([]float64{1.5, math.NaN(), math.Inf(1), math.Inf(-1)})
+		m := g.NewHashMapFrom(map[string]float64{"a": math.NaN()})
+		tree := g.NewTreeMap[int, float64](comparators.ComparatorInt)
+		tree.Put(1, math.Inf(1))
+
+		_, err := json.Marshal(array)
+		t.AssertNE(err, nil)
+		_, err = json.Marshal(m)
+		t.AssertNE(err, nil)
+		_, err = json.Marshal(tree)
+		t.AssertNE(err, nil)
+
+		array.SetMarshalOptions(g.JSONOptions{NonFiniteFloat: g.NonFiniteFloatNull})
+		m.SetMarshalOptions(g.JSONOptions{NonFiniteFloat: g.NonFiniteFloatNull})
+		b, err := json.Marshal(array)
+		t.AssertNil(err)
+		t.Assert(string(b), `[1.5,null,null,null]`)
+		b, err = json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"a":null}`)
+		// The options are per container.
+		_, err = json.Marshal(tree)
+		t.AssertNE(err, nil)
+
+		array.SetMarshalOptions(g.JSONOptions{NonFiniteFloat: g.NonFiniteFloatString})
+		tree.SetMarshalOptions(g.JSONOptions{NonFiniteFloat: g.NonFiniteFloatString})
+		b, err = json.Marshal(array)
+		t.AssertNil(err)
+		t.Assert(string(b), `[1.5,"NaN","+Inf","-Inf"]`)
+		b, err = json.Marshal(tree)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"1":"+Inf"}`)
+
+		array.SetMarshalOptions(g.JSONOptions{})
+		_, err = json.Marshal(array)
+		t.AssertNE(err, nil)
+	})
+}
+
+func Test_JSONOptions_LargeIntAsString(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		list := g.NewLinkedListFrom([]int64{1, 1 << 53, 1<<53 + 1, -(1<<53 + 1)})
+		set := g.NewHashSetFrom([]uint64{math.MaxUint64})
+
+		b, err := json.Marshal(list)
+		t.AssertNil(err)
+		t.Assert(string(b), `[1,9007199254740992,9007199254740993,-9007199254740993]`)
+
+		list.SetMarshalOptions(g.JSONOptions{LargeIntAsString: true})
+		set.SetMarshalOptions(g.JSONOptions{LargeIntAsString: true})
+		b, err = json.Marshal(list)
+		t.AssertNil(err)
+		t.Assert(string(b), `[1,9007199254740992,"9007199254740993","-9007199254740993"]`)
+		b, err = json.Marshal(set)
+		t.AssertNil(err)
+		t.Assert(string(b), `["18446744073709551615"]`)
+
+		frozen := g.NewArrayListFrom([]uint64{math.MaxUint64}).Freeze()
+		b, err = json.Marshal(frozen.WithMarshalOptions(g.JSONOptions{LargeIntAsString: true}))
+		t.AssertNil(err)
+		t.Assert(string(b), `["18446744073709551615"]`)
+		b, err = json.Marshal(frozen)
+		t.AssertNil(err)
+		t.Assert(string(b), `[18446744073709551615]`)
+	})
+}
+
+type jsonOptionsPrice struct {
+	Amount float64 `json:"amount"`
+}
+
+type jsonOptionsBase struct {
+	ID    int64 `json:"id"`
+	Label string
+}
+
+type jsonOptionsItem struct {
+	jsonOptionsBase
+	Price     jsonOptionsPrice             `json:"price"`
+	Prices    []float64                    `json:"prices"`
+	History   map[string]*jsonOptionsPrice `json:"history"`
+	Ratio     float64                      `json:"ratio,omitempty"`
+	Count     int64                        `json:"count,string"`
+	Skipped   float64                      `json:"-"`
+	Raw       []byte                       `json:"raw"`
+	CreatedAt time.Time                    `json:"created_at"`
+	hidden    float64
+}
+
+func Test_JSONOptions_Nested(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		item := jsonOptionsItem{
+			jsonOptionsBase: jsonOptionsBase{ID: 1<<53 + 1, Label: "a"},
+			Price:           jsonOptionsPrice{Amount: math.NaN()},
+			Prices:          []float64{1, math.Inf(1)},
+			History:         map[string]*jsonOptionsPrice{"x": {Amount: math.Inf(-1)}, "y": nil},
+			Count:           1<<53 + 1,
+			Skipped:         math.NaN(),
+			Raw:             []byte("raw"),
+			CreatedAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			hidden:          math.NaN(),
+		}
+		m := g.NewHashMap[string, jsonOptionsItem]()
+		m.Put("item", item)
+		_, err := json.Marshal(m)
+		t.AssertNE(err, nil)
+
+		m.SetMarshalOptions(g.JSONOptions{NonFiniteFloat: g.NonFiniteFloatString, LargeIntAsString: true})
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"item":{"id":"9007199254740993","Label":"a","price":{"amount":"NaN"},`+
+			`"prices":[1,"+Inf"],"history":{"x":{"amount":"-Inf"},"y":null},"count":"9007199254740993",`+
+			`"raw":"cmF3","created_at":"2024-01-02T03:04:05Z"}}`)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		// The nested values not to be converted are marshaled the same as json.
+		item := jsonOptionsItem{
+			jsonOptionsBase: jsonOptionsBase{ID: 1, Label: "a"},
+			Ratio:           0.5,
+			Count:           2,
+			Prices:          []float64{1.5},
+		}
+		expected, err := json.Marshal(map[string]any{"item": item, "list": []any{item, nil}})
+		t.AssertNil(err)
+		m := g.NewTreeMap[string, any](comparators.ComparatorString)
+		m.Put("item", item)
+		m.Put("list", []any{item, nil})
+		m.SetMarshalOptions(g.JSONOptions{NonFiniteFloat: g.NonFiniteFloatNull})
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), string(expected))
+	})
+}
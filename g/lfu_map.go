@@ -0,0 +1,552 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// LFUMap is a map bounded by a capacity, which evicts the least frequently used entry when an entry is put
+// into the full map, and the least recently used one among the least frequently used entries on ties.
+// It suits the workloads in which the popularity of keys is stable and heavy-tailed, where a burst of
+// one-off keys would flush the popular entries out of an LRUMap.
+//
+// The entries are kept in buckets of the same use count, which are linked in ascending order of the counts,
+// so that all of its lookups and updates are O(1).
+//
+// Put, Get, Search, GetOrPut and GetOrPutFunc count as uses of the entries, and the lookups update the
+// hit/miss counters, while Peek, ContainsKey and the iterations don't.
+// Note that, as the uses modify the buckets, the map must be created concurrent-safe to be read concurrently.
+type LFUMap[K comparable, V any] struct {
+	mu          rwmutex.RWMutex
+	data        map[K]*lfuMapEntry[K, V]
+	buckets     *LinkedList[*lfuMapBucket[K, V]] // In ascending order of the use counts.
+	capacity    int
+	hits        uint64
+	misses      uint64
+	onEvict     []func(key K, value V) // Observers registered by OnEvict.
+	jsonOptions *JSONOptions           // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// lfuMapBucket holds the entries used `count` times, from the most recently used to the least recently used.
+type lfuMapBucket[K comparable, V any] struct {
+	count   int
+	entries *LinkedList[*lfuMapEntry[K, V]]
+}
+
+// lfuMapEntry is an entry of LFUMap, which knows its positions in the buckets.
+type lfuMapEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	bucket *Element[*lfuMapBucket[K, V]]
+	elem   *Element[*lfuMapEntry[K, V]]
+}
+
+// NewLFUMap creates and returns an empty LFU map holding at most `capacity` entries,
+// which is taken as 1 if it's less than 1.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewLFUMap[K comparable, V any](capacity int, safe ...bool) *LFUMap[K, V] {
+	return &LFUMap[K, V]{
+		mu:       rwmutex.Create(safe...),
+		data:     make(map[K]*lfuMapEntry[K, V]),
+		buckets:  NewLinkedList[*lfuMapBucket[K, V]](),
+		capacity: max(capacity, 1),
+	}
+}
+
+// OnEvict registers `f` to be called with every entry evicted for the capacity, after the map is unlocked.
+// It's not called for the entries removed by Remove, Removes or Clear.
+func (m *LFUMap[K, V]) OnEvict(f func(key K, value V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEvict = append(m.onEvict, f)
+}
+
+// notifyEvict calls the observers registered by OnEvict with `evicted`, which must be called without lock.
+func (m *LFUMap[K, V]) notifyEvict(evicted []*lfuMapEntry[K, V]) {
+	if len(evicted) == 0 {
+		return
+	}
+	m.mu.RLock()
+	observers := m.onEvict
+	m.mu.RUnlock()
+	for _, entry := range evicted {
+		for _, f := range observers {
+			f(entry.key, entry.value)
+		}
+	}
+}
+
+// Capacity returns the max number of entries of the map.
+func (m *LFUMap[K, V]) Capacity() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.capacity
+}
+
+// SetCapacity changes the max number of entries of the map to `capacity`, which is taken as 1
+// if it's less than 1, and evicts the least frequently used entries beyond it.
+func (m *LFUMap[K, V]) SetCapacity(capacity int) {
+	var evicted []*lfuMapEntry[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.capacity = max(capacity, 1)
+	for len(m.data) > m.capacity {
+		evicted = append(evicted, m.evictWithoutLock())
+	}
+}
+
+// Hits returns the number of the lookups which found the key.
+func (m *LFUMap[K, V]) Hits() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hits
+}
+
+// Misses returns the number of the lookups which didn't find the key.
+func (m *LFUMap[K, V]) Misses() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.misses
+}
+
+// ResetStats resets the hit and miss counters to zero.
+func (m *LFUMap[K, V]) ResetStats() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hits, m.misses = 0, 0
+}
+
+// Frequency returns the number of uses of `key`, or 0 if the key does not exist.
+func (m *LFUMap[K, V]) Frequency(key K) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if entry, ok := m.data[key]; ok {
+		return entry.bucket.Value.count
+	}
+	return 0
+}
+
+// Put sets key-value to the map as a use of the entry,
+// and evicts the least frequently used entry if the map is full.
+func (m *LFUMap[K, V]) Put(key K, value V) {
+	var evicted []*lfuMapEntry[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	evicted = m.putWithoutLock(key, value, evicted)
+}
+
+// Puts batch sets key-values to the map, and evicts the least frequently used entries beyond the capacity.
+func (m *LFUMap[K, V]) Puts(data map[K]V) {
+	var evicted []*lfuMapEntry[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range data {
+		evicted = m.putWithoutLock(k, v, evicted)
+	}
+}
+
+// PutAll batch sets key-values to the map, which is alias of Puts.
+func (m *LFUMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the map is locked.
+func (m *LFUMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	var evicted []*lfuMapEntry[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		evicted = m.putWithoutLock(entry.Key, entry.Value, evicted)
+	}
+}
+
+// putWithoutLock sets key-value to the map as a use of the entry, and appends the entry evicted
+// for it to `evicted`.
+func (m *LFUMap[K, V]) putWithoutLock(key K, value V, evicted []*lfuMapEntry[K, V]) []*lfuMapEntry[K, V] {
+	if entry, ok := m.data[key]; ok {
+		entry.value = value
+		m.touchWithoutLock(entry)
+		return evicted
+	}
+	if len(m.data) >= m.capacity {
+		evicted = append(evicted, m.evictWithoutLock())
+	}
+	bucket := m.buckets.Front()
+	if bucket == nil || bucket.Value.count != 1 {
+		bucket = m.buckets.PushFront(&lfuMapBucket[K, V]{
+			count:   1,
+			entries: NewLinkedList[*lfuMapEntry[K, V]](),
+		})
+	}
+	entry := &lfuMapEntry[K, V]{key: key, value: value, bucket: bucket}
+	entry.elem = bucket.Value.entries.PushFront(entry)
+	m.data[key] = entry
+	return evicted
+}
+
+// touchWithoutLock moves `entry` to the bucket of the next use count.
+func (m *LFUMap[K, V]) touchWithoutLock(entry *lfuMapEntry[K, V]) {
+	current := entry.bucket
+	next := current.Next()
+	if next == nil || next.Value.count != current.Value.count+1 {
+		next = m.buckets.InsertAfter(current, &lfuMapBucket[K, V]{
+			count:   current.Value.count + 1,
+			entries: NewLinkedList[*lfuMapEntry[K, V]](),
+		})
+	}
+	m.unlinkWithoutLock(entry)
+	entry.bucket = next
+	entry.elem = next.Value.entries.PushFront(entry)
+}
+
+// unlinkWithoutLock removes `entry` from its bucket, and removes the bucket if it becomes empty.
+func (m *LFUMap[K, V]) unlinkWithoutLock(entry *lfuMapEntry[K, V]) {
+	entries := entry.bucket.Value.entries
+	entries.remove(entry.elem)
+	if entries.Len() == 0 {
+		m.buckets.remove(entry.bucket)
+	}
+}
+
+// evictWithoutLock removes and returns the least recently used entry of the least frequently used ones.
+// The map must not be empty.
+func (m *LFUMap[K, V]) evictWithoutLock() *lfuMapEntry[K, V] {
+	entry := m.buckets.Front().Value.entries.Back().Value
+	m.unlinkWithoutLock(entry)
+	delete(m.data, entry.key)
+	return entry
+}
+
+// Search searches the map with given `key`, and counts a use of the entry if it's found.
+// Second return parameter `found` is true if key was found, otherwise false.
+func (m *LFUMap[K, V]) Search(key K) (value V, found bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.data[key]
+	if !ok {
+		m.misses++
+		return
+	}
+	m.hits++
+	m.touchWithoutLock(entry)
+	return entry.value, true
+}
+
+// Get returns the value by given `key`, or empty value of type V if the key is not found in the map.
+// It counts a use of the entry if it's found.
+func (m *LFUMap[K, V]) Get(key K) (value V) {
+	value, _ = m.Search(key)
+	return
+}
+
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *LFUMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *LFUMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
+// Peek returns the value by given `key` like Search, but neither counts a use of the entry nor the lookup.
+func (m *LFUMap[K, V]) Peek(key K) (value V, found bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if entry, ok := m.data[key]; ok {
+		return entry.value, true
+	}
+	return
+}
+
+// GetOrPut returns the value by key,
+// or sets value with given `value` if it does not exist and then returns this value.
+func (m *LFUMap[K, V]) GetOrPut(key K, value V) V {
+	v, _ := m.getOrPutFunc(key, func() V { return value })
+	return v
+}
+
+// GetOrPutFunc returns the value by key,
+// or sets value with returned value of callback function `f` if it does not exist
+// and then returns this value.
+// Note that `f` is called with the map locked.
+func (m *LFUMap[K, V]) GetOrPutFunc(key K, f func() V) V {
+	v, _ := m.getOrPutFunc(key, f)
+	return v
+}
+
+// PutIfAbsent sets `value` to the map if the `key` does not exist, and then returns true.
+// It returns false if `key` exists, and `value` would be ignored.
+func (m *LFUMap[K, V]) PutIfAbsent(key K, value V) bool {
+	_, put := m.getOrPutFunc(key, func() V { return value })
+	return put
+}
+
+// PutIfAbsentFunc sets value with return value of callback function `f`, and then returns true.
+// It returns false if `key` exists, and `f` would not be called.
+func (m *LFUMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
+	_, put := m.getOrPutFunc(key, f)
+	return put
+}
+
+// getOrPutFunc returns the value by key as a use of the entry,
+// or sets value with returned value of `f` if it does not exist, in which case `put` is true.
+func (m *LFUMap[K, V]) getOrPutFunc(key K, f func() V) (value V, put bool) {
+	var evicted []*lfuMapEntry[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry, ok := m.data[key]; ok {
+		m.hits++
+		m.touchWithoutLock(entry)
+		return entry.value, false
+	}
+	m.misses++
+	value = f()
+	evicted = m.putWithoutLock(key, value, evicted)
+	return value, true
+}
+
+// Remove deletes value from map by given `key`, and return this deleted value.
+func (m *LFUMap[K, V]) Remove(key K) (value V, removed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if entry, ok := m.data[key]; ok {
+		m.unlinkWithoutLock(entry)
+		delete(m.data, key)
+		return entry.value, true
+	}
+	return
+}
+
+// Removes batch deletes values of the map by keys.
+func (m *LFUMap[K, V]) Removes(keys []K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if entry, ok := m.data[key]; ok {
+			m.unlinkWithoutLock(entry)
+			delete(m.data, key)
+		}
+	}
+}
+
+// ForEach iterates the map readonly from the most frequently used entry to the least frequently used one,
+// and from the most recently used to the least recently used for the entries used the same times,
+// with custom callback function `f`, which doesn't count as uses of the entries.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LFUMap[K, V]) ForEach(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.buckets.ForEachDesc(func(bucket *lfuMapBucket[K, V]) bool {
+		next := true
+		bucket.entries.ForEachAsc(func(entry *lfuMapEntry[K, V]) bool {
+			next = f(entry.key, entry.value)
+			return next
+		})
+		return next
+	})
+}
+
+// Entries returns all key-value pairs of the map as a slice of entries, in the same order as ForEach.
+func (m *LFUMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *LFUMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LFUMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LFUMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
+// ContainsKey checks whether a key exists, which doesn't count as a use of the entry.
+func (m *LFUMap[K, V]) ContainsKey(key K) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.data[key]
+	return ok
+}
+
+// Size returns the size of the map.
+func (m *LFUMap[K, V]) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.data)
+}
+
+// IsEmpty checks whether the map is empty.
+// It returns true if map is empty, or else false.
+func (m *LFUMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Keys returns all keys of the map as a slice, in the order of ForEach.
+func (m *LFUMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Size())
+	m.ForEach(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values of the map as a slice, in the order of ForEach.
+func (m *LFUMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Size())
+	m.ForEach(func(_ K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Map returns a copy of the underlying data of the map.
+func (m *LFUMap[K, V]) Map() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[K]V, len(m.data))
+	for k, entry := range m.data {
+		data[k] = entry.value
+	}
+	return data
+}
+
+// MapStrAny returns a copy of the underlying data of the map as map[string]V.
+func (m *LFUMap[K, V]) MapStrAny() map[string]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[string]V, len(m.data))
+	for k, entry := range m.data {
+		data[gconv.String(k)] = entry.value
+	}
+	return data
+}
+
+// Clear deletes all data of the map, which keeps the capacity and the hit/miss counters.
+func (m *LFUMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = make(map[K]*lfuMapEntry[K, V])
+	m.buckets = NewLinkedList[*lfuMapBucket[K, V]]()
+}
+
+// Replace the data of the map with given `data`, of which the entries beyond the capacity are evicted.
+func (m *LFUMap[K, V]) Replace(data map[K]V) {
+	m.Clear()
+	m.Puts(data)
+}
+
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// all done under one lock. It doesn't count as uses of the entries.
+func (m *LFUMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range m.data {
+		entry.value = f(entry.key, entry.value)
+	}
+}
+
+// Clone returns a new LFU map with the same capacity and copy of current map data with their use counts.
+// The hit/miss counters and the observers are not copied.
+func (m *LFUMap[K, V]) Clone(safe ...bool) Map[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := NewLFUMap[K, V](m.capacity, safe...)
+	m.buckets.ForEachAsc(func(bucket *lfuMapBucket[K, V]) bool {
+		newBucket := newMap.buckets.PushBack(&lfuMapBucket[K, V]{
+			count:   bucket.count,
+			entries: NewLinkedList[*lfuMapEntry[K, V]](),
+		})
+		bucket.entries.ForEachAsc(func(entry *lfuMapEntry[K, V]) bool {
+			newEntry := &lfuMapEntry[K, V]{key: entry.key, value: entry.value, bucket: newBucket}
+			newEntry.elem = newBucket.Value.entries.PushBack(newEntry)
+			newMap.data[entry.key] = newEntry
+			return true
+		})
+		return true
+	})
+	return newMap
+}
+
+// String returns the map as a string.
+func (m *LFUMap[K, V]) String() string {
+	if m == nil {
+		return ""
+	}
+	b, _ := m.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *LFUMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *LFUMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *LFUMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *LFUMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (m *LFUMap[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
+}
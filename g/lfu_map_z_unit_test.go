@@ -0,0 +1,163 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestLFUMap_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLFUMap[string, int](3)
+		var _ g.Map[string, int] = m
+		var _ g.ExtendedMap[string, int] = m
+		t.Assert(m.Capacity(), 3)
+		m.Put("a", 1)
+		m.Put("b", 2)
+		m.Put("c", 3)
+		t.Assert(m.Get("a"), 1)
+		t.Assert(m.Get("a"), 1)
+		t.Assert(m.Get("b"), 2)
+		t.Assert(m.Frequency("a"), 3)
+		t.Assert(m.Frequency("b"), 2)
+		t.Assert(m.Frequency("c"), 1)
+		t.Assert(m.Frequency("x"), 0)
+		t.Assert(m.Keys(), []string{"a", "b", "c"})
+
+		// "c" is the least frequently used.
+		m.Put("d", 4)
+		t.Assert(m.ContainsKey("c"), false)
+		t.Assert(m.Keys(), []string{"a", "b", "d"})
+
+		// "d" and "e" are both used once, of which "d" is the least recently used.
+		m.Put("e", 5)
+		t.Assert(m.Keys(), []string{"a", "b", "e"})
+		m.Put("e", 50)
+		t.Assert(m.Frequency("e"), 2)
+		t.Assert(m.Keys(), []string{"a", "e", "b"})
+		t.Assert(m.Values(), []int{1, 50, 2})
+
+		v, found := m.Peek("b")
+		t.Assert(v, 2)
+		t.Assert(found, true)
+		t.Assert(m.Frequency("b"), 2)
+
+		t.Assert(m.PutIfAbsent("b", 20), false)
+		t.Assert(m.Frequency("b"), 3)
+		t.Assert(m.GetOrPutFunc("f", func() int { return 6 }), 6)
+		t.Assert(m.ContainsKey("e"), false)
+
+		v, removed := m.Remove("a")
+		t.Assert(v, 1)
+		t.Assert(removed, true)
+		m.Removes([]string{"b", "x"})
+		t.Assert(m.Map(), map[string]int{"f": 6})
+		m.Clear()
+		t.Assert(m.IsEmpty(), true)
+		m.Put("g", 7)
+		t.Assert(m.Frequency("g"), 1)
+	})
+}
+
+func TestLFUMap_OnEvict(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var evicted []string
+		m := g.NewLFUMap[string, int](2, true)
+		m.OnEvict(func(key string, value int) {
+			evicted = append(evicted, fmt.Sprintf("%s=%d", key, value))
+			m.Size()
+		})
+		m.Put("a", 1)
+		m.Get("a")
+		m.Put("b", 2)
+		m.Put("c", 3)
+		t.Assert(evicted, []string{"b=2"})
+		m.GetOrPut("d", 4)
+		t.Assert(evicted, []string{"b=2", "c=3"})
+
+		m.SetCapacity(1)
+		t.Assert(m.Keys(), []string{"a"})
+		t.Assert(evicted, []string{"b=2", "c=3", "d=4"})
+	})
+}
+
+func TestLFUMap_Stats(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLFUMap[int, string](2)
+		m.Put(1, "a")
+		m.Get(1)
+		m.Get(2)
+		m.Search(1)
+		m.GetOrPut(3, "c")
+		m.Peek(3)
+		t.Assert(m.Hits(), 2)
+		t.Assert(m.Misses(), 2)
+		m.ResetStats()
+		t.Assert(m.Hits(), 0)
+	})
+}
+
+func TestLFUMap_Clone(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLFUMap[int, int](3)
+		m.Put(1, 1)
+		m.Put(2, 2)
+		m.Put(3, 3)
+		m.Get(2)
+		c := m.Clone().(*g.LFUMap[int, int])
+		t.Assert(c.Keys(), m.Keys())
+		t.Assert(c.Frequency(2), 2)
+		c.Put(4, 4)
+		t.Assert(c.ContainsKey(1), false)
+		t.Assert(m.ContainsKey(1), true)
+	})
+}
+
+func TestLFUMap_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			m  = g.NewLFUMap[int, int](16, true)
+			wg sync.WaitGroup
+		)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					m.Put(j%32, j)
+					m.Get((j + i) % 32)
+				}
+			}()
+		}
+		wg.Wait()
+		t.Assert(m.Size(), 16)
+		t.Assert(m.Hits()+m.Misses(), 4000)
+	})
+}
+
+func TestLFUMap_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLFUMap[string, int](2)
+		m.Put("a", 1)
+		t.Assert(m.String(), `{"a":1}`)
+		t.Assert(fmt.Sprint(m), m.String())
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"a":1}`)
+
+		var nilMap *g.LFUMap[string, int]
+		t.Assert(nilMap.String(), "")
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	json2 "encoding/json"
 	"fmt"
+	"hash/maphash"
 
 	"github.com/wesleywu/gcontainer/internal/deepcopy"
 	"github.com/wesleywu/gcontainer/internal/json"
@@ -26,9 +27,10 @@ import (
 //
 // Reference: http://en.wikipedia.org/wiki/Associative_array
 type LinkedHashMap[K comparable, V any] struct {
-	mu   rwmutex.RWMutex
-	data map[K]*Element[*gListMapNode[K, V]]
-	list *LinkedList[*gListMapNode[K, V]]
+	mu          rwmutex.RWMutex
+	data        map[K]*Element[*gListMapNode[K, V]]
+	list        *LinkedList[*gListMapNode[K, V]]
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 type gListMapNode[K comparable, V any] struct {
@@ -62,6 +64,34 @@ func (m *LinkedHashMap[K, V]) ForEach(f func(key K, value V) bool) {
 	m.ForEachAsc(f)
 }
 
+// Entries returns all key-value pairs of the map as a slice of entries, in insertion order.
+func (m *LinkedHashMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *LinkedHashMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LinkedHashMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LinkedHashMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
 // ForEachAsc iterates the map readonly in ascending order with given callback function `f`.
 // If `f` returns true, then it continues iterating; or false to stop.
 func (m *LinkedHashMap[K, V]) ForEachAsc(f func(key K, value V) bool) {
@@ -114,6 +144,19 @@ func (m *LinkedHashMap[K, V]) Replace(data map[K]V) {
 	m.mu.Unlock()
 }
 
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// in insertion order and under one lock.
+func (m *LinkedHashMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.list != nil {
+		m.list.ForEachAsc(func(node *gListMapNode[K, V]) bool {
+			node.value = f(node.key, node.value)
+			return true
+		})
+	}
+}
+
 // Map returns a copy of the underlying data of the map.
 func (m *LinkedHashMap[K, V]) Map() map[K]V {
 	m.mu.RLock()
@@ -169,6 +212,47 @@ func (m *LinkedHashMap[K, V]) FilterEmpty() {
 	m.mu.Unlock()
 }
 
+// Filtered returns a new link map containing all key-value pairs for which `f` returns true,
+// in the same order as current map.
+// Different from FilterInPlace, it does not change current map.
+func (m *LinkedHashMap[K, V]) Filtered(f func(key K, value V) bool) Map[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := NewListMap[K, V](m.mu.IsSafe())
+	if m.list != nil {
+		m.list.ForEachAsc(func(node *gListMapNode[K, V]) bool {
+			if f(node.key, node.value) {
+				newMap.data[node.key] = newMap.list.PushBack(&gListMapNode[K, V]{node.key, node.value})
+			}
+			return true
+		})
+	}
+	return newMap
+}
+
+// FilterInPlace deletes all key-value pairs for which `f` returns false,
+// which means only the key-value pairs for which `f` returns true are retained.
+func (m *LinkedHashMap[K, V]) FilterInPlace(f func(key K, value V) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.list == nil {
+		return
+	}
+	var keys = make([]K, 0)
+	m.list.ForEachAsc(func(node *gListMapNode[K, V]) bool {
+		if !f(node.key, node.value) {
+			keys = append(keys, node.key)
+		}
+		return true
+	})
+	for _, key := range keys {
+		if e, ok := m.data[key]; ok {
+			delete(m.data, key)
+			m.list.Remove(e.Value)
+		}
+	}
+}
+
 // Put sets key-value to the map.
 func (m *LinkedHashMap[K, V]) Put(key K, value V) {
 	m.mu.Lock()
@@ -201,6 +285,30 @@ func (m *LinkedHashMap[K, V]) Puts(data map[K]V) {
 	m.mu.Unlock()
 }
 
+// PutAll batch sets key-values to the map, which is alias of Puts.
+func (m *LinkedHashMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the map is locked.
+func (m *LinkedHashMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		m.data = make(map[K]*Element[*gListMapNode[K, V]])
+		m.list = NewLinkedList[*gListMapNode[K, V]]()
+	}
+	for _, entry := range entries {
+		if e, ok := m.data[entry.Key]; !ok {
+			m.data[entry.Key] = m.list.PushBack(&gListMapNode[K, V]{entry.Key, entry.Value})
+		} else {
+			e.Value = &gListMapNode[K, V]{entry.Key, entry.Value}
+		}
+	}
+}
+
 // Search searches the map with given `key`.
 // Second return parameter `found` is true if key was found, otherwise false.
 func (m *LinkedHashMap[K, V]) Search(key K) (value V, found bool) {
@@ -227,6 +335,24 @@ func (m *LinkedHashMap[K, V]) Get(key K) (value V) {
 	return
 }
 
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *LinkedHashMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *LinkedHashMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
 // Pop retrieves and deletes an item from the map.
 func (m *LinkedHashMap[K, V]) Pop() (key K, value V) {
 	m.mu.Lock()
@@ -365,6 +491,62 @@ func (m *LinkedHashMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
 	return false
 }
 
+// Compute computes the new value of `key` with `f` in one operation with the map locked, so it's atomic
+// to the other goroutines. The function `f` is called with the current value and whether the key exists,
+// and the key is set to the returned `newValue` if `f` returns true as `keep`, or else removed.
+// It returns the value of `key` after the call, and whether the key exists.
+func (m *LinkedHashMap[K, V]) Compute(key K, f func(value V, exists bool) (newValue V, keep bool)) (value V, exists bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		m.data = make(map[K]*Element[*gListMapNode[K, V]])
+		m.list = NewLinkedList[*gListMapNode[K, V]]()
+	}
+	var oldValue V
+	e, found := m.data[key]
+	if found {
+		oldValue = e.Value.value
+	}
+	newValue, keep := f(oldValue, found)
+	switch {
+	case !keep:
+		if found {
+			delete(m.data, key)
+			m.list.Remove(e.Value)
+		}
+		return
+	case found:
+		e.Value = &gListMapNode[K, V]{key, newValue}
+	default:
+		m.data[key] = m.list.PushBack(&gListMapNode[K, V]{key, newValue})
+	}
+	return newValue, true
+}
+
+// ComputeIfPresent computes the new value of `key` with `f` like Compute, but only if the key exists.
+// It returns the value of `key` after the call, and whether the key exists.
+func (m *LinkedHashMap[K, V]) ComputeIfPresent(key K, f func(value V) (newValue V, keep bool)) (value V, exists bool) {
+	return m.Compute(key, func(value V, exists bool) (V, bool) {
+		if !exists {
+			return value, false
+		}
+		return f(value)
+	})
+}
+
+// MergeValue sets `value` to `key` if the key does not exist, or else sets the result of `f` called with
+// the current value and `value`, like counting with `m.MergeValue(key, 1, func(a, b int) int { return a + b })`.
+// It's done with the map locked, and returns the new value of `key`.
+func (m *LinkedHashMap[K, V]) MergeValue(key K, value V, f func(oldValue, value V) V) V {
+	newValue, _ := m.Compute(key, func(oldValue V, exists bool) (V, bool) {
+		if !exists {
+			return value, true
+		}
+		return f(oldValue, value), true
+	})
+	return newValue
+}
+
 // Remove deletes value from map by given `key`, and return this deleted value.
 func (m *LinkedHashMap[K, V]) Remove(key K) (value V, removed bool) {
 	m.mu.Lock()
@@ -487,15 +669,43 @@ func (m *LinkedHashMap[K, V]) String() string {
 	return string(b)
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *LinkedHashMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *LinkedHashMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *LinkedHashMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *LinkedHashMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (m LinkedHashMap[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 	if m.data == nil {
 		return []byte("null"), nil
 	}
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
 	buffer := bytes.NewBuffer(nil)
 	buffer.WriteByte('{')
 	m.ForEach(func(key K, value V) bool {
-		valueBytes, valueJsonErr := json.Marshal(value)
+		valueBytes, valueJsonErr := marshalJSONValue(options, value)
 		if valueJsonErr != nil {
 			err = valueJsonErr
 			return false
@@ -507,6 +717,9 @@ func (m LinkedHashMap[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 		return true
 	})
 	buffer.WriteByte('}')
+	if err != nil {
+		return nil, err
+	}
 	return buffer.Bytes(), nil
 }
 
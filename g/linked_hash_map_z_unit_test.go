@@ -12,6 +12,7 @@ import (
 	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/internal/gtest"
 	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/utils/comparators"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
@@ -179,6 +180,27 @@ func Test_ListMap_FilterEmpty(t *testing.T) {
 	})
 }
 
+func Test_ListMap_Filtered(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMap[int, string]()
+		m.Put(4, "d")
+		m.Put(1, "a")
+		m.Put(3, "c")
+		m.Put(2, "b")
+		filtered := m.Filtered(func(k int, v string) bool {
+			return k > 1
+		})
+		t.Assert(filtered.Keys(), []int{4, 3, 2})
+		t.Assert(m.Size(), 4)
+
+		m.FilterInPlace(func(k int, v string) bool {
+			return v != "c"
+		})
+		t.Assert(m.Keys(), []int{4, 1, 2})
+		t.Assert(m.Values(), []string{"d", "a", "b"})
+	})
+}
+
 func Test_ListMap_Json(t *testing.T) {
 	// Marshal
 	gtest.C(t, func(t *gtest.T) {
@@ -383,3 +405,135 @@ func TestListMap_DeepCopy(t *testing.T) {
 		t.AssertNE(m.Get(1), n.Get(1))
 	})
 }
+
+func TestListMap_Compute(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMap[string, int](true)
+		m.Put("a", 1)
+		m.Put("b", 2)
+		v, exists := m.Compute("c", func(value int, exists bool) (int, bool) {
+			t.Assert(exists, false)
+			return 3, true
+		})
+		t.Assert(v, 3)
+		t.Assert(exists, true)
+		m.Compute("a", func(value int, exists bool) (int, bool) {
+			return value + 10, true
+		})
+		t.Assert(m.Keys(), []string{"a", "b", "c"})
+		t.Assert(m.Values(), []int{11, 2, 3})
+		_, exists = m.Compute("b", func(value int, exists bool) (int, bool) {
+			return 0, false
+		})
+		t.Assert(exists, false)
+		t.Assert(m.Keys(), []string{"a", "c"})
+
+		_, exists = m.ComputeIfPresent("b", func(value int) (int, bool) {
+			return 1, true
+		})
+		t.Assert(exists, false)
+		_, exists = m.ComputeIfPresent("c", func(value int) (int, bool) {
+			return 0, false
+		})
+		t.Assert(exists, false)
+
+		sum := func(a, b int) int { return a + b }
+		t.Assert(m.MergeValue("d", 5, sum), 5)
+		t.Assert(m.MergeValue("a", 5, sum), 16)
+		t.Assert(m.Keys(), []string{"a", "d"})
+		t.Assert(m.Values(), []int{16, 5})
+	})
+}
+
+func TestListMap_GetOrDefault_MustGet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMapFrom[int, int](map[int]int{1: 0})
+		t.Assert(m.GetOrDefault(1, -1), 0)
+		t.Assert(m.GetOrDefault(2, -1), -1)
+		t.Assert(m.Size(), 1)
+		t.Assert(m.MustGet(1), 0)
+
+		func() {
+			defer func() {
+				t.Assert(recover(), "key not found: 2")
+			}()
+			m.MustGet(2)
+		}()
+	})
+}
+
+func TestListMap_Entries(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMap[string, int]()
+		m.Put("b", 2)
+		m.Put("a", 1)
+		m.Put("c", 3)
+		t.Assert(m.Entries(), []g.Entry[string, int]{{"b", 2}, {"a", 1}, {"c", 3}})
+
+		var keys []string
+		m.ForEachEntry(func(entry g.Entry[string, int]) bool {
+			keys = append(keys, entry.Key)
+			return entry.Key != "a"
+		})
+		t.Assert(keys, []string{"b", "a"})
+	})
+}
+
+func TestListMap_IterKeysValues(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMap[string, int]()
+		m.Put("b", 2)
+		m.Put("a", 1)
+		m.Put("c", 3)
+
+		var keys []string
+		m.IterKeys(func(key string) bool {
+			keys = append(keys, key)
+			return true
+		})
+		t.Assert(keys, m.Keys())
+
+		var values []int
+		m.IterValues(func(value int) bool {
+			values = append(values, value)
+			return value != 1
+		})
+		t.Assert(values, []int{2, 1})
+	})
+}
+
+func TestListMap_ReplaceAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMap[string, string]()
+		m.Put("b", " B ")
+		m.Put("a", "A")
+		var keys []string
+		m.ReplaceAll(func(key string, value string) string {
+			keys = append(keys, key)
+			return key + value
+		})
+		t.Assert(keys, []string{"b", "a"})
+		t.Assert(m.Values(), []string{"b B ", "aA"})
+		g.NewListMap[string, string]().ReplaceAll(func(key string, value string) string {
+			t.Error("should not be called for empty map")
+			return value
+		})
+	})
+}
+
+func TestListMap_PutAllFrom(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		tree := g.NewTreeMap[int, int](comparators.ComparatorInt)
+		for i := 5; i > 0; i-- {
+			tree.Put(i, i*10)
+		}
+		m := g.NewListMap[int, int]()
+		m.Put(3, 0)
+		m.PutAllFrom(tree)
+		t.Assert(m.Keys(), []int{3, 1, 2, 4, 5})
+		t.Assert(m.Get(3), 30)
+
+		m.PutAll(map[int]int{6: 60})
+		t.Assert(m.Size(), 6)
+	})
+}
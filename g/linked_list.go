@@ -9,7 +9,13 @@ package g
 
 import (
 	"bytes"
+	"context"
+	"encoding/gob"
 	json2 "encoding/json"
+	"errors"
+	"fmt"
+	"hash/maphash"
+	"unsafe"
 
 	"github.com/wesleywu/gcontainer/internal/deepcopy"
 	"github.com/wesleywu/gcontainer/internal/json"
@@ -18,12 +24,18 @@ import (
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
+const (
+	// defaultForEachChunkSize is the default number of elements copied at a time by ForEachChunked.
+	defaultForEachChunkSize = 64
+)
+
 // LinkedList represents a doubly linked list.
 // The zero value for LinkedList is an empty list ready to use.
 type LinkedList[T any] struct {
-	mu   rwmutex.RWMutex
-	root Element[T] // sentinel list element, only &root, root.prev, and root.next are used
-	len  int        // current list length excluding (this) sentinel element
+	mu          rwmutex.RWMutex
+	root        Element[T]   // sentinel list element, only &root, root.prev, and root.next are used
+	len         int          // current list length excluding (this) sentinel element
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // Element is an element of a linked list.
@@ -42,6 +54,17 @@ type Element[T any] struct {
 	Value T
 }
 
+// LinkedListIterator is an iterator of LinkedList in ascending order created by LinkedList.Iter,
+// which allows removing the current element during the iteration.
+// It is not concurrent-safe itself, but it can be used concurrently with other operations of the list,
+// as it locks the list in each call.
+type LinkedListIterator[T any] struct {
+	list    *LinkedList[T]
+	current *Element[T] // The current element, nil before the first call of Next or after the end.
+	next    *Element[T] // The successor of the current element when it is removed by Remove.
+	started bool
+}
+
 // Init initializes or clears list l.
 func (l *LinkedList[T]) Init() *LinkedList[T] {
 	l.root.next = &l.root
@@ -68,6 +91,25 @@ func NewLinkedListFrom[T any](array []T, safe ...bool) *LinkedList[T] {
 	return l
 }
 
+// CollectLinkedList creates and returns a list of the values received from channel `ch` in order,
+// until `ch` is closed or `ctx` is done. The values received before `ctx` is done are kept in the list.
+// The parameter `safe` is used to specify whether using list in concurrent-safety,
+// which is false in default.
+func CollectLinkedList[T any](ctx context.Context, ch <-chan T, safe ...bool) *LinkedList[T] {
+	l := NewLinkedList[T](safe...)
+	for {
+		select {
+		case <-ctx.Done():
+			return l
+		case v, ok := <-ch:
+			if !ok {
+				return l
+			}
+			l.PushBack(v)
+		}
+	}
+}
+
 // Next returns the next list element or nil.
 func (e *Element[T]) Next() *Element[T] {
 	if p := e.next; e.list != nil && p != &e.list.root {
@@ -86,8 +128,8 @@ func (e *Element[T]) Prev() *Element[T] {
 
 // Add append a new element e with value v at the back of list l and returns true.
 func (l *LinkedList[T]) Add(values ...T) bool {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.lazyInit()
 	for _, value := range values {
 		_ = l.insertValue(value, l.root.prev)
@@ -98,13 +140,14 @@ func (l *LinkedList[T]) Add(values ...T) bool {
 // AddAll adds all the elements in the specified collection to this list.
 // Returns true if this collection changed as a result of the call
 func (l *LinkedList[T]) AddAll(values Collection[T]) bool {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	// The values are copied before locking, as `values` may be the list itself.
+	array := values.Slice()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.lazyInit()
-	values.ForEach(func(value T) bool {
+	for _, value := range array {
 		_ = l.insertValue(value, l.root.prev)
-		return true
-	})
+	}
 	return true
 }
 
@@ -191,6 +234,47 @@ func (l *LinkedList[T]) search(value T) *Element[T] {
 	return nil
 }
 
+// Find returns the first element for which `predicate` returns true, and true as `found`,
+// or nil and false if there is no such element.
+func (l *LinkedList[T]) Find(predicate func(value T) bool) (e *Element[T], found bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.lazyInit()
+	for e = l.root.next; e != &l.root; e = e.next {
+		if predicate(e.Value) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// FindLast returns the last element for which `predicate` returns true, and true as `found`,
+// or nil and false if there is no such element.
+func (l *LinkedList[T]) FindLast(predicate func(value T) bool) (e *Element[T], found bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.lazyInit()
+	for e = l.root.prev; e != &l.root; e = e.prev {
+		if predicate(e.Value) {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// IndexOf returns the position of the first element equal to `value`, or -1 if not exists.
+func (l *LinkedList[T]) IndexOf(value T) int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.lazyInit()
+	for i, e := 0, l.root.next; e != &l.root; i, e = i+1, e.next {
+		if equal.Equals(e.Value, value) {
+			return i
+		}
+	}
+	return -1
+}
+
 // Len returns the number of elements of list l.
 // The complexity is O(1).
 func (l *LinkedList[T]) Len() int {
@@ -275,8 +359,8 @@ func (l *LinkedList[T]) move(e, at *Element[T]) {
 // if it is present.
 // Returns true if this collection changed as a result of the call
 func (l *LinkedList[T]) Remove(values ...T) (changed bool) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	changed = false
 	for _, value := range values {
 		existing := l.search(value)
@@ -291,53 +375,206 @@ func (l *LinkedList[T]) Remove(values ...T) (changed bool) {
 // RemoveAll removes all of this list's elements that are also contained in the specified collection
 // Returns true if this collection changed as a result of the call
 func (l *LinkedList[T]) RemoveAll(values Collection[T]) (changed bool) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	// The values are copied before locking, as `values` may be the list itself.
+	array := values.Slice()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	changed = false
-	values.ForEach(func(value T) bool {
+	for _, value := range array {
 		existing := l.search(value)
 		if existing != nil {
 			l.remove(existing)
 			changed = true
 		}
-		return true
-	})
+	}
 	return
 }
 
-// PushBack inserts a new element e with value v at the back of list l and returns e.
-func (l *LinkedList[T]) PushBack(v T) *Element[T] {
+// RemoveIf removes all the elements for which `predicate` returns true in a single traversal,
+// and returns the number of removed elements.
+func (l *LinkedList[T]) RemoveIf(predicate func(value T) bool) (removed int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	for e := l.root.next; e != &l.root; {
+		next := e.next
+		if predicate(e.Value) {
+			l.remove(e)
+			removed++
+		}
+		e = next
+	}
+	return
+}
+
+// RetainAll retains only the elements that are contained in the specified collection `values`
+// in a single traversal, in which `values.Contains` is called for each element.
+// Returns true if this list changed as a result of the call.
+func (l *LinkedList[T]) RetainAll(values Collection[T]) bool {
+	if Collection[T](l) == values {
+		return false
+	}
+	return l.RemoveIf(func(value T) bool {
+		return !values.Contains(value)
+	}) > 0
+}
+
+// Iter returns an iterator of the list in ascending order, which allows removing the current element
+// during the iteration, e.g. `for it := l.Iter(); it.Next(); { if ... { it.Remove() } }`.
+//
+// Unlike ForEach, the list is locked only within each call of the iterator, so that the loop body can
+// operate the list freely. Note that if the current element is removed by anything but the iterator,
+// or moved to another position, the iteration stops or continues from its new position.
+func (l *LinkedList[T]) Iter() *LinkedListIterator[T] {
+	return &LinkedListIterator[T]{list: l}
+}
+
+// Next moves the iterator to the next element, and returns true if there is one, or false at the end.
+func (it *LinkedListIterator[T]) Next() bool {
+	l := it.list
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	l.lazyInit()
+	var e *Element[T]
+	switch {
+	case !it.started:
+		it.started = true
+		e = l.root.next
+	case it.current == nil:
+		e = it.next
+	case it.current.list == l:
+		e = it.current.next
+	}
+	it.current, it.next = nil, nil
+	if e == nil || e == &l.root || e.list != l {
+		return false
+	}
+	it.current = e
+	return true
+}
+
+// Value returns the value of the current element.
+// It returns empty value of type T if Next is not called or returns false, or the current element is removed.
+func (it *LinkedListIterator[T]) Value() (value T) {
+	if it.current != nil {
+		value = it.current.Value
+	}
+	return
+}
+
+// Element returns the current element, or nil if Next is not called or returns false,
+// or the current element is removed.
+func (it *LinkedListIterator[T]) Element() *Element[T] {
+	return it.current
+}
+
+// Remove removes the current element from the list, and the next call of Next moves to its successor.
+// It returns false if there is no current element or it is not in the list anymore.
+func (it *LinkedListIterator[T]) Remove() bool {
+	l := it.list
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if it.current == nil || it.current.list != l {
+		return false
+	}
+	it.next = it.current.next
+	l.remove(it.current)
+	it.current = nil
+	return true
+}
+
+// Unique removes the elements equal to any previous element in a single traversal with an auxiliary set,
+// keeping the first occurrences in their order, and returns the number of removed elements.
+// Note that the values are used as map keys like ArrayList.Unique, so T must be a comparable type dynamically,
+// or use UniqueFunc instead.
+func (l *LinkedList[T]) Unique() (removed int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	seen := make(map[any]struct{}, l.len)
+	for e := l.root.next; e != &l.root; {
+		next := e.next
+		if _, ok := seen[e.Value]; ok {
+			l.remove(e)
+			removed++
+		} else {
+			seen[e.Value] = struct{}{}
+		}
+		e = next
+	}
+	return
+}
+
+// UniqueFunc removes the elements for which `eq` returns true with any previous element,
+// keeping the first occurrences in their order, and returns the number of removed elements.
+// It costs O(n^2), but works for any type T.
+func (l *LinkedList[T]) UniqueFunc(eq func(v1, v2 T) bool) (removed int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	for e := l.root.next; e != &l.root; {
+		next := e.next
+		for u := l.root.next; u != e; u = u.next {
+			if eq(u.Value, e.Value) {
+				l.remove(e)
+				removed++
+				break
+			}
+		}
+		e = next
+	}
+	return
+}
+
+// PushBack inserts a new element e with value v at the back of list l and returns e.
+func (l *LinkedList[T]) PushBack(v T) *Element[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
 	return l.insertValue(v, l.root.prev)
 }
 
 // PushFront inserts a new element e with value v at the front of list l and returns e.
 func (l *LinkedList[T]) PushFront(v T) *Element[T] {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.lazyInit()
 	return l.insertValue(v, &l.root)
 }
 
+// PushFrontOrMove moves the first element equal to `v` to the front of list l if exists,
+// or else inserts a new element with value `v` at the front, and returns the element at the front.
+// The lookup and update are done in one locked operation, which is the primitive of LRU caches.
+// Note that the lookup costs O(n). For big lists, keep the returned elements in a map by key,
+// and use MoveToFront with them instead.
+func (l *LinkedList[T]) PushFrontOrMove(v T) *Element[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	if e := l.search(v); e != nil {
+		l.move(e, &l.root)
+		return e
+	}
+	return l.insertValue(v, &l.root)
+}
+
 // PushBacks inserts multiple new elements with values `values` at the back of list `l`.
 func (l *LinkedList[T]) PushBacks(values []T) {
 	l.mu.Lock()
-	l.mu.Unlock()
+	defer l.mu.Unlock()
 	l.lazyInit()
 	for _, v := range values {
-		l.PushBack(v)
+		l.insertValue(v, l.root.prev)
 	}
 }
 
 // PushFronts inserts multiple new elements with values `values` at the front of list `l`.
 func (l *LinkedList[T]) PushFronts(values []T) {
 	l.mu.Lock()
-	defer l.mu.RUnlock()
+	defer l.mu.Unlock()
 	l.lazyInit()
 	for _, v := range values {
-		l.PushFront(v)
+		l.insertValue(v, &l.root)
 	}
 }
 
@@ -381,7 +618,7 @@ func (l *LinkedList[T]) PopBacks(max int) (values []T) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.lazyInit()
-	length := l.Len()
+	length := l.len
 	if length > 0 {
 		if max > 0 && max < length {
 			length = max
@@ -406,7 +643,7 @@ func (l *LinkedList[T]) PopFronts(max int) (values []T) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	l.lazyInit()
-	length := l.Len()
+	length := l.len
 	if length > 0 {
 		if max > 0 && max < length {
 			length = max
@@ -442,7 +679,7 @@ func (l *LinkedList[T]) FrontAll() (values []T) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	l.lazyInit()
-	length := l.Len()
+	length := l.len
 	if length > 0 {
 		values = make([]T, length)
 		for i, e := 0, l.root.next; i < length; i, e = i+1, e.Next() {
@@ -457,7 +694,7 @@ func (l *LinkedList[T]) BackAll() (values []T) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 	l.lazyInit()
-	length := l.Len()
+	length := l.len
 	if length > 0 {
 		values = make([]T, length)
 		for i, e := 0, l.root.prev; i < length; i, e = i+1, e.Prev() {
@@ -493,8 +730,8 @@ func (l *LinkedList[T]) BackValue() (value T) {
 // If mark is not an element of l, the list is not modified.
 // The mark must not be nil.
 func (l *LinkedList[T]) InsertBefore(mark *Element[T], v T) *Element[T] {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if mark.list != l {
 		return nil
 	}
@@ -506,8 +743,8 @@ func (l *LinkedList[T]) InsertBefore(mark *Element[T], v T) *Element[T] {
 // If mark is not an element of l, the list is not modified.
 // The mark must not be nil.
 func (l *LinkedList[T]) InsertAfter(mark *Element[T], v T) *Element[T] {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if mark.list != l {
 		return nil
 	}
@@ -519,8 +756,8 @@ func (l *LinkedList[T]) InsertAfter(mark *Element[T], v T) *Element[T] {
 // If e is not an element of l, the list is not modified.
 // The element must not be nil.
 func (l *LinkedList[T]) MoveToFront(e *Element[T]) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if e.list != l || l.root.next == e {
 		return
 	}
@@ -532,8 +769,8 @@ func (l *LinkedList[T]) MoveToFront(e *Element[T]) {
 // If e is not an element of l, the list is not modified.
 // The element must not be nil.
 func (l *LinkedList[T]) MoveToBack(e *Element[T]) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if e.list != l || l.root.prev == e {
 		return
 	}
@@ -545,8 +782,8 @@ func (l *LinkedList[T]) MoveToBack(e *Element[T]) {
 // If e or mark is not an element of l, or e == mark, the list is not modified.
 // The element and mark must not be nil.
 func (l *LinkedList[T]) MoveBefore(e, mark *Element[T]) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if e.list != l || e == mark || mark.list != l {
 		return
 	}
@@ -557,14 +794,294 @@ func (l *LinkedList[T]) MoveBefore(e, mark *Element[T]) {
 // If e or mark is not an element of l, or e == mark, the list is not modified.
 // The element and mark must not be nil.
 func (l *LinkedList[T]) MoveAfter(e, mark *Element[T]) {
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	if e.list != l || e == mark || mark.list != l {
 		return
 	}
 	l.move(e, mark)
 }
 
+// Swap swaps the positions of elements `e1` and `e2` in the list, so that the elements stay valid
+// with their values. If `e1` or `e2` is not an element of the list, the list is not modified.
+func (l *LinkedList[T]) Swap(e1, e2 *Element[T]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e1.list != l || e2.list != l || e1 == e2 {
+		return
+	}
+	prev1 := e1.prev
+	if prev1 == e2 {
+		l.move(e2, e1)
+		return
+	}
+	l.move(e1, e2)
+	l.move(e2, prev1)
+}
+
+// MoveToIndex moves element `e` to position `index` of the list, counting from the front with `e` excluded,
+// which costs O(n) to walk to the position. If `e` is not an element of the list or `index` is out of range,
+// the list is not modified.
+func (l *LinkedList[T]) MoveToIndex(e *Element[T], index int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e.list != l || index < 0 || index >= l.len {
+		return
+	}
+	// Unlinks `e` first, so that the position is counted without it.
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	l.len--
+	at := l.root.prev
+	if index < l.len {
+		at = l.elementAt(index).prev
+	}
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	l.len++
+}
+
+// Get returns the value of the element at the specified position `index` in the list,
+// walking from whichever end is closer, which costs O(n).
+// If given `index` is out of range, returns empty `value` for type T and bool value false as `found`.
+func (l *LinkedList[T]) Get(index int) (value T, found bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if e := l.elementAt(index); e != nil {
+		return e.Value, true
+	}
+	return
+}
+
+// MustGet returns the value of the element at the specified position `index` in the list.
+// If given `index` is out of range, returns empty `value` for type T.
+func (l *LinkedList[T]) MustGet(index int) (value T) {
+	value, _ = l.Get(index)
+	return
+}
+
+// Set replaces the value of the element at the specified position `index` in the list with `v`.
+// It returns an error if `index` is out of range.
+func (l *LinkedList[T]) Set(index int, v T) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := l.elementAt(index)
+	if e == nil {
+		return errors.New(fmt.Sprintf("index %d out of list range %d", index, l.len))
+	}
+	e.Value = v
+	return nil
+}
+
+// InsertAt inserts a new element e with value `v` at the specified position `index` and returns e,
+// so that e has the position `index` after insertion. It appends `v` to the list if `index` equals its length.
+// If `index` is out of range [0, length], the list is not modified and it returns nil.
+func (l *LinkedList[T]) InsertAt(index int, v T) *Element[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	if index == l.len {
+		return l.insertValue(v, l.root.prev)
+	}
+	e := l.elementAt(index)
+	if e == nil {
+		return nil
+	}
+	return l.insertValue(v, e.prev)
+}
+
+// RemoveAt removes the element at the specified position `index` and returns its value.
+// If given `index` is out of range, the `found` is false.
+func (l *LinkedList[T]) RemoveAt(index int) (value T, found bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := l.elementAt(index)
+	if e == nil {
+		return
+	}
+	l.remove(e)
+	return e.Value, true
+}
+
+// SubList returns a new list with a copy of the elements in range [`fromIndex`, `toIndex`).
+// The range is truncated to the bounds of the list like ArrayList.Range,
+// and it returns an empty list if the range is empty.
+// The returned list is independent of l, and uses the same concurrent-safety as l.
+func (l *LinkedList[T]) SubList(fromIndex, toIndex int) *LinkedList[T] {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	sub := NewLinkedList[T](l.mu.IsSafe())
+	fromIndex = max(fromIndex, 0)
+	toIndex = min(toIndex, l.len)
+	for i, e := fromIndex, l.elementAt(fromIndex); i < toIndex; i, e = i+1, e.next {
+		sub.insertValue(e.Value, sub.root.prev)
+	}
+	return sub
+}
+
+// Filter returns a new list with the values for which `predicate` returns true, in their order.
+// The new list is concurrent-safe if the list is.
+func (l *LinkedList[T]) Filter(predicate func(value T) bool) *LinkedList[T] {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.lazyInit()
+	filtered := NewLinkedList[T](l.mu.IsSafe())
+	for e := l.root.next; e != &l.root; e = e.next {
+		if predicate(e.Value) {
+			filtered.insertValue(e.Value, filtered.root.prev)
+		}
+	}
+	return filtered
+}
+
+// MapLinkedList returns a new list of type U with the results of calling `f` on every value of `list`
+// in order. The new list is concurrent-safe if `list` is.
+func MapLinkedList[T any, U any](list *LinkedList[T], f func(value T) U) *LinkedList[U] {
+	list.mu.RLock()
+	defer list.mu.RUnlock()
+	list.lazyInit()
+	mapped := NewLinkedList[U](list.mu.IsSafe())
+	for e := list.root.next; e != &list.root; e = e.next {
+		mapped.insertValue(f(e.Value), mapped.root.prev)
+	}
+	return mapped
+}
+
+// elementAt returns the element at position `index` walking from whichever end is closer,
+// or nil if `index` is out of range.
+func (l *LinkedList[T]) elementAt(index int) *Element[T] {
+	if index < 0 || index >= l.len {
+		return nil
+	}
+	if index < l.len/2 {
+		e := l.root.next
+		for i := 0; i < index; i++ {
+			e = e.next
+		}
+		return e
+	}
+	e := l.root.prev
+	for i := l.len - 1; i > index; i-- {
+		e = e.prev
+	}
+	return e
+}
+
+// Sort sorts the list in place in the order of `comparator` with a stable bottom-up merge sort,
+// which relinks the elements instead of copying values, costs O(n log n) and allocates no memory.
+// The elements keep their identity, so that the *Element[T] held by callers are still valid.
+func (l *LinkedList[T]) Sort(comparator func(v1, v2 T) int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	if l.len < 2 {
+		return
+	}
+	// Sorts the elements as a nil-terminated singly linked list, and then restores the prev links and the ring.
+	head := l.root.next
+	l.root.prev.next = nil
+	for width := 1; width < l.len; width *= 2 {
+		var (
+			dummy Element[T]
+			tail  = &dummy
+			rest  = head
+		)
+		for rest != nil {
+			left := rest
+			right := splitElements(left, width)
+			rest = splitElements(right, width)
+			tail = mergeElements(tail, left, right, comparator)
+		}
+		head = dummy.next
+	}
+	prev := &l.root
+	for e := head; e != nil; e = e.next {
+		e.prev = prev
+		prev.next = e
+		prev = e
+	}
+	prev.next = &l.root
+	l.root.prev = prev
+}
+
+// Reverse reverses the list in place by swapping the next and prev links of the elements, which costs O(n).
+func (l *LinkedList[T]) Reverse() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	e := &l.root
+	for {
+		e.next, e.prev = e.prev, e.next
+		if e = e.prev; e == &l.root {
+			return
+		}
+	}
+}
+
+// Rotate rotates the list by `n` positions, so that the first `n` elements are moved to the back in order,
+// or the last -`n` elements are moved to the front if `n` is negative.
+// It relinks the list only at the new front, which costs O(min(n, len-n)) regardless of the value of `n`.
+func (l *LinkedList[T]) Rotate(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.len == 0 {
+		return
+	}
+	n %= l.len
+	if n < 0 {
+		n += l.len
+	}
+	if n == 0 {
+		return
+	}
+	front := l.elementAt(n)
+	// Unlinks the root from the ring, and links it back before the new front.
+	l.root.prev.next = l.root.next
+	l.root.next.prev = l.root.prev
+	l.root.prev = front.prev
+	l.root.next = front
+	front.prev.next = &l.root
+	front.prev = &l.root
+}
+
+// splitElements cuts the nil-terminated elements starting at `e` after `n` elements,
+// and returns the first element of the rest, or nil if there are no more elements.
+func splitElements[T any](e *Element[T], n int) *Element[T] {
+	for i := 1; e != nil && i < n; i++ {
+		e = e.next
+	}
+	if e == nil {
+		return nil
+	}
+	rest := e.next
+	e.next = nil
+	return rest
+}
+
+// mergeElements merges the sorted nil-terminated elements `left` and `right` after `tail` in the order of
+// `comparator`, taking from `left` first for equal elements, and returns the last merged element.
+func mergeElements[T any](tail, left, right *Element[T], comparator func(v1, v2 T) int) *Element[T] {
+	for left != nil && right != nil {
+		if comparator(left.Value, right.Value) <= 0 {
+			tail.next, left = left, left.next
+		} else {
+			tail.next, right = right, right.next
+		}
+		tail = tail.next
+	}
+	if left != nil {
+		tail.next = left
+	} else {
+		tail.next = right
+	}
+	for tail.next != nil {
+		tail = tail.next
+	}
+	return tail
+}
+
 // PushBackList inserts a copy of another list at the back of list l.
 // The lists l and other may be the same. They must not be nil.
 func (l *LinkedList[T]) PushBackList(other *LinkedList[T]) {
@@ -572,8 +1089,8 @@ func (l *LinkedList[T]) PushBackList(other *LinkedList[T]) {
 		other.mu.RLock()
 		defer other.mu.RUnlock()
 	}
-	l.mu.RLock()
-	defer l.mu.RUnlock()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	l.lazyInit()
 	for i, e := other.len, other.root.next; i > 0; i, e = i-1, e.Next() {
 		l.insertValue(e.Value, l.root.prev)
@@ -595,6 +1112,98 @@ func (l *LinkedList[T]) PushFrontList(other *LinkedList[T]) {
 	}
 }
 
+// SpliceBack moves all the elements of another list `other` to the back of list l, leaving `other` empty.
+// Unlike PushBackList, it relinks the elements instead of copying values, so that the elements keep their
+// identity. It costs O(k) for the k moved elements, as each element records the list it belongs to.
+// It does nothing if l and `other` are the same list.
+func (l *LinkedList[T]) SpliceBack(other *LinkedList[T]) {
+	if l == other {
+		return
+	}
+	unlock := lockBoth(l, other)
+	defer unlock()
+	l.lazyInit()
+	other.lazyInit()
+	if other.len == 0 {
+		return
+	}
+	first, last, n := other.root.next, other.root.prev, other.len
+	other.Init()
+	l.linkRange(first, last, n, l.root.prev)
+}
+
+// TransferRange moves the elements from `first` to `last` inclusive of list l to list `dst`,
+// immediately before the element `at` of `dst`, or to the back of `dst` if `at` is nil.
+// It relinks the elements instead of copying values, which costs O(k) for the k moved elements.
+// The list `dst` may be l itself, in which case `at` must not be in the range.
+//
+// If `first` or `last` is not an element of l, `last` is not after `first`, `at` is not an element of `dst`,
+// or `at` is in the range, the lists are not modified.
+func (l *LinkedList[T]) TransferRange(first, last *Element[T], dst *LinkedList[T], at *Element[T]) {
+	if dst == nil {
+		return
+	}
+	unlock := lockBoth(l, dst)
+	defer unlock()
+	dst.lazyInit()
+	if first.list != l || last.list != l || (at != nil && at.list != dst) {
+		return
+	}
+	n := 1
+	for e := first; e != last; e = e.next {
+		if e == &l.root || e == at {
+			return
+		}
+		n++
+	}
+	if last == at {
+		return
+	}
+	first.prev.next = last.next
+	last.next.prev = first.prev
+	l.len -= n
+	mark := dst.root.prev
+	if at != nil {
+		mark = at.prev
+	}
+	dst.linkRange(first, last, n, mark)
+}
+
+// lockBoth locks the lists `a` and `b` for writing, and returns the function unlocking them.
+// The lists are always locked in the order of their addresses, so that the functions moving elements
+// between two lists, like SpliceBack and TransferRange, never deadlock with each other in either direction.
+func lockBoth[T any](a, b *LinkedList[T]) (unlock func()) {
+	if a == b {
+		a.mu.Lock()
+		return a.mu.Unlock
+	}
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.mu.Lock()
+	b.mu.Lock()
+	return func() {
+		b.mu.Unlock()
+		a.mu.Unlock()
+	}
+}
+
+// linkRange links the `n` elements from `first` to `last` after the element `at` of list l,
+// which are linked together but not in any list of l.
+func (l *LinkedList[T]) linkRange(first, last *Element[T], n int, at *Element[T]) {
+	for e := first; ; e = e.next {
+		e.list = l
+		if e == last {
+			break
+		}
+	}
+	first.prev = at
+	last.next = at.next
+	at.next.prev = last
+	at.next = first
+	l.len += n
+}
+
 // Clear removes all the elements from this collection.
 func (l *LinkedList[T]) Clear() {
 	l.mu.Lock()
@@ -613,36 +1222,47 @@ func (l *LinkedList[T]) Clone() Collection[T] {
 	return NewLinkedListFrom(values, l.mu.IsSafe())
 }
 
+// Equals checks whether the list equals to collection `another` element-wise, in the order of
+// iterating `another`, e.g. ascending order of another list, or sorted order of a TreeSet.
 func (l *LinkedList[T]) Equals(another Collection[T]) bool {
 	if l == another {
 		return true
 	}
-	var (
-		ano *LinkedList[T]
-		ok  bool
-	)
-	if ano, ok = another.(*LinkedList[T]); !ok {
+	values := another.Slice()
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.len != len(values) {
 		return false
 	}
+	for i, e := 0, l.root.next; i < l.len; i, e = i+1, e.next {
+		if !equal.Equals(e.Value, values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualsIgnoreOrder checks whether the list and collection `another` contain the same elements
+// with the same number of occurrences, regardless of their order.
+func (l *LinkedList[T]) EqualsIgnoreOrder(another Collection[T]) bool {
+	if l == another {
+		return true
+	}
+	values := another.Slice()
 	l.mu.RLock()
 	defer l.mu.RUnlock()
-	ano.mu.RLock()
-	defer ano.mu.RUnlock()
-	if l.len != ano.len {
+	if l.len != len(values) {
 		return false
 	}
-	values := make([]T, l.len)
-	for i, e := 0, l.root.next; i < l.len; i, e = i+1, e.Next() {
-		values[i] = e.Value
+	counts := make(map[any]int, l.len)
+	for e := l.root.next; e != &l.root && e != nil; e = e.next {
+		counts[e.Value]++
 	}
-	valuesAno := make([]T, l.len)
-	for i, e := 0, ano.root.next; i < ano.len; i, e = i+1, e.Next() {
-		valuesAno[i] = e.Value
-	}
-	for i := 0; i < l.len; i++ {
-		if !equal.Equals(values[i], valuesAno[i]) {
+	for _, v := range values {
+		if counts[v] == 0 {
 			return false
 		}
+		counts[v]--
 	}
 	return true
 }
@@ -673,6 +1293,74 @@ func (l *LinkedList[T]) ForEachDesc(f func(e T) bool) {
 	}
 }
 
+// ForEachChunked iterates the list readonly in ascending order with given callback function `f`.
+// Unlike ForEach, it copies at most `chunkSize` elements at a time under a short read lock,
+// and calls `f` without holding the lock, so that slow callbacks do not block writers of big lists.
+// The default chunk size is used if `chunkSize` <= 0.
+// If `f` returns true, then it continues iterating; or false to stop.
+//
+// The iteration is weakly consistent. Each chunk is a consistent snapshot, but the list may be modified
+// between chunks: an element which stays in place during the whole iteration is visited exactly once,
+// while elements inserted, removed or moved concurrently may or may not be visited.
+// If the last visited element is removed concurrently, the iteration resumes at its index,
+// so that some elements may be skipped or visited twice.
+func (l *LinkedList[T]) ForEachChunked(chunkSize int, f func(e T) bool) {
+	if chunkSize <= 0 {
+		chunkSize = defaultForEachChunkSize
+	}
+	var (
+		chunk = make([]T, 0, chunkSize)
+		last  *Element[T] // The last visited element.
+		index int         // The index of the next element to visit.
+	)
+	for {
+		chunk = chunk[:0]
+		l.mu.RLock()
+		l.lazyInit()
+		e := l.root.next
+		if last != nil {
+			if last.list == l {
+				e = last.next
+			} else {
+				for i := 0; i < index && e != &l.root; i++ {
+					e = e.next
+				}
+			}
+		}
+		for ; e != &l.root && len(chunk) < chunkSize; e = e.next {
+			chunk = append(chunk, e.Value)
+			last = e
+		}
+		l.mu.RUnlock()
+		index += len(chunk)
+		for _, v := range chunk {
+			if !f(v) {
+				return
+			}
+		}
+		if len(chunk) < chunkSize {
+			return
+		}
+	}
+}
+
+// StreamTo sends the values of the list to channel `ch` in ascending order, and returns nil after all the
+// values are sent, or the error of `ctx` if it is done before that. It does not close `ch`.
+// The list is not locked while sending, and it is iterated like ForEachChunked, so that the list can be
+// modified concurrently by the receivers.
+func (l *LinkedList[T]) StreamTo(ctx context.Context, ch chan<- T) (err error) {
+	l.ForEachChunked(0, func(v T) bool {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return false
+		case ch <- v:
+			return true
+		}
+	})
+	return
+}
+
 // Join joins list elements with a string `glue`.
 func (l *LinkedList[T]) Join(glue string) string {
 	l.mu.RLock()
@@ -688,12 +1376,46 @@ func (l *LinkedList[T]) Join(glue string) string {
 	return buffer.String()
 }
 
+// JoinFunc joins list elements with a string `glue`, each of which is formatted by `format`.
+// Note that `format` is called with the list read-locked, so it must not modify the list.
+func (l *LinkedList[T]) JoinFunc(glue string, format func(value T) string) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.lazyInit()
+	buffer := bytes.NewBuffer(nil)
+	for i, e := 0, l.root.next; i < l.len; i, e = i+1, e.Next() {
+		if i > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(format(e.Value))
+	}
+	return buffer.String()
+}
+
 // String returns current list as a string.
 func (l *LinkedList[T]) String() string {
 	l.lazyInit()
 	return "[" + l.Join(",") + "]"
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (l *LinkedList[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, l)
+}
+
+// formatEntries implements the interface formattable.
+func (l *LinkedList[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = l.Size()
+	return collectFormatValues(l.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is sensitive to the order of elements.
+func (l *LinkedList[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashOrdered(seed, l.ForEach)
+}
+
 // Sum returns the sum of values in an array.
 func (l *LinkedList[T]) Sum() (sum int) {
 	l.mu.RLock()
@@ -704,9 +1426,19 @@ func (l *LinkedList[T]) Sum() (sum int) {
 	return
 }
 
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (l *LinkedList[T]) SetMarshalOptions(options JSONOptions) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (l LinkedList[T]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(l.FrontAll())
+	l.mu.RLock()
+	options := l.jsonOptions
+	l.mu.RUnlock()
+	return marshalJSONSlice(options, l.FrontAll())
 }
 
 // UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
@@ -719,11 +1451,43 @@ func (l *LinkedList[T]) UnmarshalJSON(b []byte) error {
 		return err
 	}
 	for _, v := range array {
-		l.PushBack(v)
+		l.insertValue(v, l.root.prev)
+	}
+	return nil
+}
+
+// MarshalBinary implements the interface encoding.BinaryMarshaler, which encodes the values of elements
+// with gob in order, so that the list can be persisted compactly and used in gob streams directly.
+func (l *LinkedList[T]) MarshalBinary() ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	if err := gob.NewEncoder(buffer).Encode(l.FrontAll()); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// UnmarshalBinary implements the interface encoding.BinaryUnmarshaler,
+// which replaces the elements of list with the ones decoded from `data` encoded by MarshalBinary.
+func (l *LinkedList[T]) UnmarshalBinary(data []byte) error {
+	var array []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&array); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.Init()
+	for _, v := range array {
+		l.insertValue(v, l.root.prev)
 	}
 	return nil
 }
 
+// RegisterLinkedListGob registers type *LinkedList[T] to gob, which is required when lists of type T
+// are encoded as interface values, e.g. elements of []any or fields of interface type.
+func RegisterLinkedListGob[T any]() {
+	gob.Register(&LinkedList[T]{})
+}
+
 // UnmarshalValue is an interface implement which sets any type of value for list.
 func (l *LinkedList[T]) UnmarshalValue(value interface{}) (err error) {
 	l.mu.Lock()
@@ -737,7 +1501,7 @@ func (l *LinkedList[T]) UnmarshalValue(value interface{}) (err error) {
 		array = gconv.SliceAny[T](value)
 	}
 	for _, v := range array {
-		l.PushBack(v)
+		l.insertValue(v, l.root.prev)
 	}
 	return err
 }
@@ -752,7 +1516,7 @@ func (l *LinkedList[T]) DeepCopy() Collection[T] {
 	defer l.mu.RUnlock()
 
 	var (
-		length = l.Len()
+		length = l.len
 		values = make([]T, length)
 	)
 	if length > 0 {
@@ -0,0 +1,144 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// LinkedListTx is the typed accessor of LinkedList passed to the callback of LinkedList.Do,
+// of which the operations are applied to the list directly without locking, as the list is
+// write-locked during the whole callback.
+// It must not be used after the callback returns.
+type LinkedListTx[T any] struct {
+	list *LinkedList[T]
+}
+
+// Do calls `f` with the list write-locked, so that the compound operations in `f` by `tx`
+// are applied atomically and not interleaved with other operations in concurrent-safe usage.
+// Note that `f` must not call the methods of the list itself, which would deadlock, but those of `tx`.
+func (l *LinkedList[T]) Do(f func(tx *LinkedListTx[T])) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lazyInit()
+	f(&LinkedListTx[T]{list: l})
+}
+
+// Len returns the number of elements of the list.
+func (tx *LinkedListTx[T]) Len() int {
+	return tx.list.len
+}
+
+// Front returns the first element of the list or nil if the list is empty.
+func (tx *LinkedListTx[T]) Front() *Element[T] {
+	if tx.list.len == 0 {
+		return nil
+	}
+	return tx.list.root.next
+}
+
+// Back returns the last element of the list or nil if the list is empty.
+func (tx *LinkedListTx[T]) Back() *Element[T] {
+	if tx.list.len == 0 {
+		return nil
+	}
+	return tx.list.root.prev
+}
+
+// Find returns the first element for which `predicate` returns true, or nil if there is no such element.
+func (tx *LinkedListTx[T]) Find(predicate func(value T) bool) *Element[T] {
+	l := tx.list
+	for e := l.root.next; e != &l.root; e = e.next {
+		if predicate(e.Value) {
+			return e
+		}
+	}
+	return nil
+}
+
+// PushBack inserts a new element with value `v` at the back of the list and returns it.
+func (tx *LinkedListTx[T]) PushBack(v T) *Element[T] {
+	return tx.list.insertValue(v, tx.list.root.prev)
+}
+
+// PushFront inserts a new element with value `v` at the front of the list and returns it.
+func (tx *LinkedListTx[T]) PushFront(v T) *Element[T] {
+	return tx.list.insertValue(v, &tx.list.root)
+}
+
+// InsertBefore inserts a new element with value `v` immediately before `mark` and returns it.
+// If `mark` is not an element of the list, the list is not modified and it returns nil.
+func (tx *LinkedListTx[T]) InsertBefore(mark *Element[T], v T) *Element[T] {
+	if mark.list != tx.list {
+		return nil
+	}
+	return tx.list.insertValue(v, mark.prev)
+}
+
+// InsertAfter inserts a new element with value `v` immediately after `mark` and returns it.
+// If `mark` is not an element of the list, the list is not modified and it returns nil.
+func (tx *LinkedListTx[T]) InsertAfter(mark *Element[T], v T) *Element[T] {
+	if mark.list != tx.list {
+		return nil
+	}
+	return tx.list.insertValue(v, mark)
+}
+
+// Remove removes element `e` from the list and returns true, or false if `e` is not an element of the list.
+func (tx *LinkedListTx[T]) Remove(e *Element[T]) bool {
+	if e.list != tx.list {
+		return false
+	}
+	tx.list.remove(e)
+	return true
+}
+
+// MoveToFront moves element `e` to the front of the list.
+// If `e` is not an element of the list, the list is not modified.
+func (tx *LinkedListTx[T]) MoveToFront(e *Element[T]) {
+	if e.list != tx.list {
+		return
+	}
+	tx.list.move(e, &tx.list.root)
+}
+
+// MoveToBack moves element `e` to the back of the list.
+// If `e` is not an element of the list, the list is not modified.
+func (tx *LinkedListTx[T]) MoveToBack(e *Element[T]) {
+	if e.list != tx.list {
+		return
+	}
+	tx.list.move(e, tx.list.root.prev)
+}
+
+// MoveBefore moves element `e` to its new position before `mark`.
+// If `e` or `mark` is not an element of the list, or `e` == `mark`, the list is not modified.
+func (tx *LinkedListTx[T]) MoveBefore(e, mark *Element[T]) {
+	if e.list != tx.list || e == mark || mark.list != tx.list {
+		return
+	}
+	tx.list.move(e, mark.prev)
+}
+
+// MoveAfter moves element `e` to its new position after `mark`.
+// If `e` or `mark` is not an element of the list, or `e` == `mark`, the list is not modified.
+func (tx *LinkedListTx[T]) MoveAfter(e, mark *Element[T]) {
+	if e.list != tx.list || e == mark || mark.list != tx.list {
+		return
+	}
+	tx.list.move(e, mark)
+}
+
+// ForEach iterates the elements of the list in ascending order with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+// It is safe to remove the current element `e` by the transaction in `f`.
+func (tx *LinkedListTx[T]) ForEach(f func(e *Element[T]) bool) {
+	l := tx.list
+	for e := l.root.next; e != &l.root; {
+		next := e.next
+		if !f(e) {
+			return
+		}
+		e = next
+	}
+}
@@ -9,6 +9,7 @@
 package g_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/wesleywu/gcontainer/g"
@@ -61,3 +62,34 @@ func Benchmark_PopBack(b *testing.B) {
 		}
 	})
 }
+
+func Benchmark_PushBack_Unsafe(b *testing.B) {
+	list := g.NewLinkedList[int]()
+	for i := 0; i < b.N; i++ {
+		list.PushBack(i)
+	}
+}
+
+// Benchmark_ReadWrite_Contention benchmarks a read-mostly workload, in which one of every
+// `writeEvery` operations pushes or pops an element and the others read the list concurrently.
+func Benchmark_ReadWrite_Contention(b *testing.B) {
+	for _, writeEvery := range []int{2, 10, 100} {
+		b.Run(fmt.Sprintf("WriteEvery%d", writeEvery), func(b *testing.B) {
+			list := g.NewLinkedListFrom(make([]int, 100), true)
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					switch {
+					case i%writeEvery != 0:
+						list.FrontValue()
+					case i%(2*writeEvery) == 0:
+						list.PushBack(i)
+					default:
+						list.PopFront()
+					}
+					i++
+				}
+			})
+		})
+	}
+}
@@ -7,11 +7,18 @@
 package g_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/internal/gtest"
 	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/utils/comparators"
 	"github.com/wesleywu/gcontainer/utils/gconv"
 )
 
@@ -451,6 +458,24 @@ func TestLinkedList_Equals(t *testing.T) {
 	})
 }
 
+func TestLinkedList_EqualsCollection(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{3, 1, 2, 1})
+		t.Assert(l.Equals(g.NewArrayListFrom([]int{3, 1, 2, 1})), true)
+		t.Assert(l.Equals(g.NewArrayListFrom([]int{1, 1, 2, 3})), false)
+		t.Assert(l.EqualsIgnoreOrder(g.NewArrayListFrom([]int{1, 1, 2, 3})), true)
+		t.Assert(l.EqualsIgnoreOrder(g.NewArrayListFrom([]int{1, 2, 2, 3})), false)
+		t.Assert(l.EqualsIgnoreOrder(g.NewArrayListFrom([]int{1, 2, 3})), false)
+		t.Assert(l.EqualsIgnoreOrder(l), true)
+
+		u := g.NewLinkedListFrom([]int{3, 1, 2})
+		t.Assert(u.Equals(g.NewTreeSetFrom([]int{1, 2, 3}, comparators.ComparatorInt)), false)
+		t.Assert(u.EqualsIgnoreOrder(g.NewTreeSetFrom([]int{1, 2, 3}, comparators.ComparatorInt)), true)
+		u.Sort(comparators.ComparatorInt)
+		t.Assert(u.Equals(g.NewTreeSetFrom([]int{3, 2, 1}, comparators.ComparatorInt)), true)
+	})
+}
+
 func TestLinkedList_PushFronts(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		l := g.NewLinkedList[int]()
@@ -671,6 +696,493 @@ func TestLinkedList_ForEach(t *testing.T) {
 	})
 }
 
+func TestLinkedList_IndexAccess(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5}, true)
+		for i := 0; i < 5; i++ {
+			t.Assert(l.MustGet(i), i+1)
+		}
+		_, found := l.Get(5)
+		t.Assert(found, false)
+		_, found = l.Get(-1)
+		t.Assert(found, false)
+
+		t.AssertNil(l.Set(3, 40))
+		t.AssertNE(l.Set(5, 50), nil)
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 40, 5})
+
+		t.Assert(l.InsertAt(0, 0).Value, 0)
+		t.Assert(l.InsertAt(6, 6).Value, 6)
+		t.Assert(l.InsertAt(4, 35).Value, 35)
+		t.Assert(l.InsertAt(9, 9), nil)
+		t.Assert(l.FrontAll(), []int{0, 1, 2, 3, 35, 40, 5, 6})
+
+		v, found := l.RemoveAt(5)
+		t.Assert(found, true)
+		t.Assert(v, 40)
+		_, found = l.RemoveAt(7)
+		t.Assert(found, false)
+		t.Assert(l.FrontAll(), []int{0, 1, 2, 3, 35, 5, 6})
+		t.Assert(l.Len(), 7)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		var l g.LinkedList[int]
+		t.Assert(l.InsertAt(0, 1).Value, 1)
+		t.Assert(l.MustGet(0), 1)
+	})
+}
+
+func TestLinkedList_Sort(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for _, n := range []int{0, 1, 2, 3, 7, 64, 100} {
+			values := make([]int, n)
+			for i := range values {
+				values[i] = (i * 37) % 11
+			}
+			l := g.NewLinkedListFrom(values, true)
+			l.Sort(func(v1, v2 int) int { return v1 - v2 })
+			checkListLen(t, l, n)
+			sorted := l.FrontAll()
+			for i := 1; i < len(sorted); i++ {
+				t.AssertLE(sorted[i-1], sorted[i])
+			}
+			// The prev links are restored.
+			back := l.BackAll()
+			for i := range back {
+				t.Assert(back[i], sorted[len(sorted)-1-i])
+			}
+		}
+	})
+	// Stable, and elements keep their identity.
+	gtest.C(t, func(t *gtest.T) {
+		type item struct {
+			Key, Seq int
+		}
+		l := g.NewLinkedList[item]()
+		e := l.PushBack(item{2, 0})
+		l.PushBack(item{1, 1})
+		l.PushBack(item{2, 2})
+		l.PushBack(item{1, 3})
+		l.Sort(func(v1, v2 item) int { return v1.Key - v2.Key })
+		t.Assert(l.FrontAll(), []item{{1, 1}, {1, 3}, {2, 0}, {2, 2}})
+		t.Assert(e.Next().Value, item{2, 2})
+		l.MoveToFront(e)
+		t.Assert(l.FrontValue(), item{2, 0})
+	})
+}
+
+func TestLinkedList_Reverse(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4}, true)
+		l.Reverse()
+		t.Assert(l.FrontAll(), []int{4, 3, 2, 1})
+		t.Assert(l.BackAll(), []int{1, 2, 3, 4})
+		checkListLen(t, l, 4)
+		l.PushBack(0)
+		l.PushFront(5)
+		t.Assert(l.FrontAll(), []int{5, 4, 3, 2, 1, 0})
+
+		empty := g.NewLinkedList[int]()
+		empty.Reverse()
+		t.Assert(empty.Len(), 0)
+		var zero g.LinkedList[int]
+		zero.Reverse()
+		t.Assert(zero.Len(), 0)
+	})
+}
+
+func TestLinkedList_RemoveIf(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5, 6}, true)
+		t.Assert(l.RemoveIf(func(v int) bool { return v%2 == 0 }), 3)
+		t.Assert(l.FrontAll(), []int{1, 3, 5})
+		t.Assert(l.RemoveIf(func(v int) bool { return v > 10 }), 0)
+		checkListLen(t, l, 3)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 2, 1}, true)
+		t.Assert(l.RetainAll(g.NewHashSetFrom([]int{1, 2})), true)
+		t.Assert(l.FrontAll(), []int{1, 2, 2, 1})
+		t.Assert(l.RetainAll(g.NewArrayListFrom([]int{1, 2, 3})), false)
+		t.Assert(l.RetainAll(l), false)
+		t.Assert(l.RetainAll(g.NewHashSet[int]()), true)
+		t.Assert(l.Len(), 0)
+	})
+}
+
+func TestLinkedList_Find(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5}, true)
+		even := func(v int) bool { return v%2 == 0 }
+		e, found := l.Find(even)
+		t.Assert(found, true)
+		t.Assert(e.Value, 2)
+		e, found = l.FindLast(even)
+		t.Assert(found, true)
+		t.Assert(e.Value, 4)
+		l.MoveToFront(e)
+		t.Assert(l.FrontAll(), []int{4, 1, 2, 3, 5})
+
+		e, found = l.Find(func(v int) bool { return v > 5 })
+		t.Assert(found, false)
+		t.Assert(e, nil)
+		_, found = l.FindLast(func(v int) bool { return v > 5 })
+		t.Assert(found, false)
+
+		t.Assert(l.IndexOf(2), 2)
+		t.Assert(l.IndexOf(6), -1)
+	})
+}
+
+func TestLinkedList_SpliceBack(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l1 := g.NewLinkedListFrom([]int{1, 2}, true)
+		l2 := g.NewLinkedListFrom([]int{3, 4}, true)
+		e := l2.Front()
+		l1.SpliceBack(l2)
+		checkListLen(t, l1, 4)
+		checkListLen(t, l2, 0)
+		t.Assert(l1.FrontAll(), []int{1, 2, 3, 4})
+		t.Assert(l1.BackAll(), []int{4, 3, 2, 1})
+		// The moved elements belong to l1 now.
+		l1.MoveToFront(e)
+		t.Assert(l1.FrontAll(), []int{3, 1, 2, 4})
+		l2.MoveToFront(l1.Back())
+		t.Assert(l2.Len(), 0)
+
+		l1.SpliceBack(l1)
+		l1.SpliceBack(l2)
+		t.Assert(l1.FrontAll(), []int{3, 1, 2, 4})
+		l2.PushBack(5)
+		t.Assert(l2.FrontAll(), []int{5})
+	})
+}
+
+func TestLinkedList_TransferRange(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		src := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5}, true)
+		dst := g.NewLinkedListFrom([]int{10, 20}, true)
+		first, _ := src.Find(func(v int) bool { return v == 2 })
+		last, _ := src.Find(func(v int) bool { return v == 4 })
+		src.TransferRange(first, last, dst, dst.Back())
+		t.Assert(src.FrontAll(), []int{1, 5})
+		t.Assert(dst.FrontAll(), []int{10, 2, 3, 4, 20})
+		t.Assert(dst.BackAll(), []int{20, 4, 3, 2, 10})
+		checkListLen(t, src, 2)
+		checkListLen(t, dst, 5)
+
+		// To the back.
+		src.TransferRange(src.Front(), src.Front(), dst, nil)
+		t.Assert(src.FrontAll(), []int{5})
+		t.Assert(dst.FrontAll(), []int{10, 2, 3, 4, 20, 1})
+
+		// Invalid ranges.
+		dst.TransferRange(last, first, src, nil)
+		dst.TransferRange(first, last, src, dst.Front())
+		src.TransferRange(first, last, dst, nil)
+		t.Assert(dst.FrontAll(), []int{10, 2, 3, 4, 20, 1})
+		t.Assert(src.FrontAll(), []int{5})
+	})
+	// Within the same list.
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5}, true)
+		first, _ := l.Find(func(v int) bool { return v == 3 })
+		l.TransferRange(first, l.Back(), l, l.Front())
+		t.Assert(l.FrontAll(), []int{3, 4, 5, 1, 2})
+		t.Assert(l.BackAll(), []int{2, 1, 5, 4, 3})
+		l.TransferRange(first, first.Next(), l, first.Next())
+		t.Assert(l.FrontAll(), []int{3, 4, 5, 1, 2})
+		checkListLen(t, l, 5)
+	})
+	// Moving elements in both directions concurrently.
+	gtest.C(t, func(t *gtest.T) {
+		a := g.NewLinkedListFrom([]int{1, 2, 3}, true)
+		b := g.NewLinkedListFrom([]int{4, 5, 6}, true)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100000; i++ {
+				a.SpliceBack(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100000; i++ {
+				if e := a.Front(); e != nil {
+					a.TransferRange(e, e, b, nil)
+				}
+			}
+		}()
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("SpliceBack and TransferRange deadlocked")
+		}
+		t.Assert(a.Len()+b.Len(), 6)
+	})
+}
+
+func TestLinkedList_PushFrontOrMove(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedList[string](true)
+		a := l.PushFrontOrMove("a")
+		l.PushFrontOrMove("b")
+		l.PushFrontOrMove("c")
+		t.Assert(l.FrontAll(), []string{"c", "b", "a"})
+		t.Assert(l.PushFrontOrMove("a"), a)
+		t.Assert(l.FrontAll(), []string{"a", "c", "b"})
+		t.Assert(l.PushFrontOrMove("a"), a)
+		checkListLen(t, l, 3)
+		t.Assert(l.BackAll(), []string{"b", "c", "a"})
+	})
+}
+
+func TestLinkedList_Iter(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5, 6}, true)
+		var visited []int
+		for it := l.Iter(); it.Next(); {
+			visited = append(visited, it.Value())
+			if it.Value()%2 == 0 {
+				t.Assert(it.Remove(), true)
+				t.Assert(it.Remove(), false)
+				t.Assert(it.Element(), nil)
+				// The list is not locked in the loop body.
+				l.PushBack(0)
+				t.Assert(l.RemoveIf(func(v int) bool { return v == 0 }), 1)
+			}
+		}
+		t.Assert(visited, []int{1, 2, 3, 4, 5, 6})
+		t.Assert(l.FrontAll(), []int{1, 3, 5})
+		checkListLen(t, l, 3)
+
+		it := l.Iter()
+		t.Assert(it.Remove(), false)
+		t.Assert(it.Value(), 0)
+		for it.Next() {
+			it.Remove()
+		}
+		t.Assert(it.Next(), false)
+		t.Assert(l.Len(), 0)
+		t.Assert(g.NewLinkedList[int]().Iter().Next(), false)
+	})
+}
+
+func TestLinkedList_SubList(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{0, 1, 2, 3, 4, 5}, true)
+		sub := l.SubList(1, 4)
+		t.Assert(sub.FrontAll(), []int{1, 2, 3})
+		checkListLen(t, sub, 3)
+		sub.PushBack(10)
+		t.Assert(l.Len(), 6)
+		t.Assert(l.SubList(4, 100).FrontAll(), []int{4, 5})
+		t.Assert(l.SubList(-1, 2).FrontAll(), []int{0, 1})
+		t.Assert(l.SubList(3, 3).Len(), 0)
+		t.Assert(l.SubList(5, 2).Len(), 0)
+		t.Assert(g.NewLinkedList[int]().SubList(0, 1).Len(), 0)
+	})
+}
+
+func TestLinkedList_Unique(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 1, 3, 2, 1}, true)
+		t.Assert(l.Unique(), 3)
+		t.Assert(l.FrontAll(), []int{1, 2, 3})
+		t.Assert(l.Unique(), 0)
+		checkListLen(t, l, 3)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([][]string{{"a"}, {"B"}, {"A"}, {"b", "c"}, {"a"}}, true)
+		t.Assert(l.UniqueFunc(func(v1, v2 []string) bool {
+			return len(v1) == len(v2) && strings.EqualFold(v1[0], v2[0])
+		}), 2)
+		t.Assert(l.FrontAll(), [][]string{{"a"}, {"B"}, {"b", "c"}})
+	})
+}
+
+func TestLinkedList_Stream(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3}, true)
+		ch := make(chan int)
+		go func() {
+			t.AssertNil(l.StreamTo(context.Background(), ch))
+			close(ch)
+		}()
+		collected := g.CollectLinkedList(context.Background(), ch, true)
+		t.Assert(collected.FrontAll(), []int{1, 2, 3})
+	})
+	gtest.C(t, func(t *gtest.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan int, 1)
+		l := g.NewLinkedListFrom([]int{1, 2, 3})
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+		t.Assert(l.StreamTo(ctx, ch), context.Canceled)
+		t.Assert(<-ch, 1)
+
+		ch <- 4
+		collected := g.CollectLinkedList(ctx, ch)
+		t.AssertLE(collected.Len(), 1)
+	})
+}
+
+func TestLinkedList_Do(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{0}, true)
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				l.Do(func(tx *g.LinkedListTx[int]) {
+					tx.PushBack(tx.Back().Value + 1)
+				})
+			}()
+		}
+		wg.Wait()
+		checkListLen(t, l, 101)
+		t.Assert(l.BackValue(), 100)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4}, true)
+		other := g.NewLinkedList[int]().PushBack(9)
+		l.Do(func(tx *g.LinkedListTx[int]) {
+			tx.ForEach(func(e *g.Element[int]) bool {
+				if e.Value%2 == 0 {
+					tx.Remove(e)
+				}
+				return true
+			})
+			t.Assert(tx.Len(), 2)
+			tx.MoveToFront(tx.Find(func(v int) bool { return v == 3 }))
+			tx.InsertAfter(tx.Front(), 5)
+			tx.PushFront(0)
+			tx.MoveToBack(tx.Front())
+			t.Assert(tx.Remove(other), false)
+			t.Assert(tx.InsertBefore(other, 1), nil)
+		})
+		t.Assert(l.FrontAll(), []int{3, 5, 1, 0})
+		checkListLen(t, l, 4)
+	})
+}
+
+func TestLinkedList_ConcurrentMutators(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedList[int](true)
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					l.PushBack(j)
+					l.PushFront(j)
+					l.Add(j)
+					l.PushBacks([]int{j})
+					l.PushFronts([]int{j})
+					l.PopBacks(1)
+				}
+			}()
+		}
+		wg.Wait()
+		checkListLen(t, l, 10*100*4)
+		l.AddAll(l)
+		t.Assert(l.Len(), 10*100*8)
+		t.Assert(l.RemoveAll(l), true)
+		t.Assert(l.Len(), 0)
+	})
+}
+
+func TestLinkedList_FilterMap(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5}, true)
+		even := l.Filter(func(v int) bool { return v%2 == 0 })
+		t.Assert(even.FrontAll(), []int{2, 4})
+		t.Assert(l.Len(), 5)
+		even.PushBack(6)
+		t.Assert(l.Len(), 5)
+
+		strs := g.MapLinkedList(l, func(v int) string { return strings.Repeat("a", v) })
+		t.Assert(strs.FrontAll(), []string{"a", "aa", "aaa", "aaaa", "aaaaa"})
+
+		var empty g.LinkedList[int]
+		t.Assert(empty.Filter(func(v int) bool { return true }).Len(), 0)
+		t.Assert(g.MapLinkedList(&empty, func(v int) int { return v }).Len(), 0)
+	})
+}
+
+func TestLinkedList_Rotate(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5})
+		l.Rotate(2)
+		t.Assert(l.FrontAll(), []int{3, 4, 5, 1, 2})
+		t.Assert(l.BackAll(), []int{2, 1, 5, 4, 3})
+		l.Rotate(-2)
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 4, 5})
+		l.Rotate(4)
+		t.Assert(l.FrontAll(), []int{5, 1, 2, 3, 4})
+		l.Rotate(11)
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 4, 5})
+		l.Rotate(-5)
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 4, 5})
+		t.Assert(l.Len(), 5)
+		l.PushBack(6)
+		l.Rotate(-1)
+		t.Assert(l.FrontAll(), []int{6, 1, 2, 3, 4, 5})
+
+		var empty g.LinkedList[int]
+		empty.Rotate(3)
+		t.Assert(empty.Len(), 0)
+	})
+}
+
+func TestLinkedList_SwapMoveToIndex(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedList[int]()
+		e1 := l.PushBack(1)
+		e2 := l.PushBack(2)
+		e3 := l.PushBack(3)
+		e4 := l.PushBack(4)
+
+		l.Swap(e1, e4)
+		t.Assert(l.FrontAll(), []int{4, 2, 3, 1})
+		l.Swap(e2, e3)
+		t.Assert(l.FrontAll(), []int{4, 3, 2, 1})
+		l.Swap(e2, e3)
+		t.Assert(l.FrontAll(), []int{4, 2, 3, 1})
+		l.Swap(e1, e4)
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 4})
+		l.Swap(e2, e2)
+		l.Swap(e2, g.NewLinkedList[int]().PushBack(5))
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 4})
+		t.Assert(l.BackAll(), []int{4, 3, 2, 1})
+
+		l.MoveToIndex(e1, 3)
+		t.Assert(l.FrontAll(), []int{2, 3, 4, 1})
+		l.MoveToIndex(e1, 0)
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 4})
+		l.MoveToIndex(e4, 1)
+		t.Assert(l.FrontAll(), []int{1, 4, 2, 3})
+		l.MoveToIndex(e4, 2)
+		t.Assert(l.FrontAll(), []int{1, 2, 4, 3})
+		l.MoveToIndex(e4, 4)
+		l.MoveToIndex(e4, -1)
+		t.Assert(l.FrontAll(), []int{1, 2, 4, 3})
+		t.Assert(l.BackAll(), []int{3, 4, 2, 1})
+		t.Assert(l.Len(), 4)
+		t.Assert(e4.Value, 4)
+	})
+}
+
 func TestLinkedList_Join(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		l := g.NewLinkedListFrom[any]([]any{1, 2, "a", `"b"`, `\c`})
@@ -679,6 +1191,14 @@ func TestLinkedList_Join(t *testing.T) {
 	})
 }
 
+func TestLinkedList_JoinFunc(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3})
+		t.Assert(l.JoinFunc(" | ", func(v int) string { return gconv.String(v * 10) }), `10 | 20 | 30`)
+		t.Assert(g.NewLinkedList[int]().JoinFunc(",", func(v int) string { return gconv.String(v) }), "")
+	})
+}
+
 func TestLinkedList_String(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		l := g.NewLinkedListFrom[any]([]any{1, 2, "a", `"b"`, `\c`})
@@ -720,6 +1240,44 @@ func TestLinkedList_Json(t *testing.T) {
 	})
 }
 
+func TestLinkedList_Binary(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		type Point struct {
+			X, Y int
+		}
+		l := g.NewLinkedListFrom([]Point{{1, 2}, {3, 4}}, true)
+		b, err := l.MarshalBinary()
+		t.AssertNil(err)
+		l2 := g.NewLinkedListFrom([]Point{{0, 0}})
+		t.AssertNil(l2.UnmarshalBinary(b))
+		t.Assert(l2.FrontAll(), []Point{{1, 2}, {3, 4}})
+		checkListLen(t, l2, 2)
+		t.AssertNE(l2.UnmarshalBinary([]byte("invalid")), nil)
+	})
+	// Gob streams.
+	gtest.C(t, func(t *gtest.T) {
+		type Payload struct {
+			Values *g.LinkedList[int]
+			Any    interface{}
+		}
+		g.RegisterLinkedListGob[string]()
+		var (
+			buffer = bytes.NewBuffer(nil)
+			input  = Payload{
+				Values: g.NewLinkedListFrom([]int{1, 2, 3}),
+				Any:    g.NewLinkedListFrom([]string{"x"}),
+			}
+			output Payload
+		)
+		t.AssertNil(gob.NewEncoder(buffer).Encode(input))
+		t.AssertNil(gob.NewDecoder(buffer).Decode(&output))
+		t.Assert(output.Values.FrontAll(), []int{1, 2, 3})
+		t.Assert(output.Any.(*g.LinkedList[string]).FrontAll(), []string{"x"})
+		output.Values.PushBack(4)
+		t.Assert(output.Values.Len(), 4)
+	})
+}
+
 func TestLinkedList_UnmarshalValue(t *testing.T) {
 	type TList struct {
 		Name string
@@ -761,3 +1319,48 @@ func TestLinkedList_DeepCopy(t *testing.T) {
 		t.AssertNE(l.Size(), copyList.Size())
 	})
 }
+
+func TestLinkedList_ForEachChunked(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5, 6, 7}, true)
+		var values []int
+		l.ForEachChunked(3, func(e int) bool {
+			values = append(values, e)
+			return true
+		})
+		t.Assert(values, []int{1, 2, 3, 4, 5, 6, 7})
+
+		values = values[:0]
+		l.ForEachChunked(0, func(e int) bool {
+			values = append(values, e)
+			return e < 4
+		})
+		t.Assert(values, []int{1, 2, 3, 4})
+
+		values = values[:0]
+		g.NewLinkedList[int]().ForEachChunked(2, func(e int) bool {
+			values = append(values, e)
+			return true
+		})
+		t.Assert(len(values), 0)
+	})
+	// Modifications between chunks.
+	gtest.C(t, func(t *gtest.T) {
+		l := g.NewLinkedListFrom([]int{1, 2, 3, 4, 5, 6}, true)
+		var values []int
+		l.ForEachChunked(2, func(e int) bool {
+			values = append(values, e)
+			switch e {
+			case 2:
+				// Writers are not blocked by the callback.
+				l.PushBack(7)
+			case 4:
+				// Removes the last visited element, so the iteration resumes at its index.
+				l.Remove(4)
+			}
+			return true
+		})
+		t.Assert(values, []int{1, 2, 3, 4, 6, 7})
+		t.Assert(l.FrontAll(), []int{1, 2, 3, 5, 6, 7})
+	})
+}
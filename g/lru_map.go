@@ -0,0 +1,474 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// LRUMap is a map bounded by a capacity, which evicts the least recently used entry when an entry is put
+// into the full map. It's backed by a hash table and a doubly-linked list in the order of recency,
+// like LinkedHashMap, so that all of its lookups and updates are O(1).
+//
+// Get, Search, GetOrPut and GetOrPutFunc count as uses of the entries and update the hit/miss counters,
+// while Peek, ContainsKey and the iterations don't.
+// Note that, as the uses modify the order, the map must be created concurrent-safe to be read concurrently.
+type LRUMap[K comparable, V any] struct {
+	mu          rwmutex.RWMutex
+	data        map[K]*Element[*gListMapNode[K, V]]
+	list        *LinkedList[*gListMapNode[K, V]] // From the most recently used to the least recently used.
+	capacity    int
+	hits        uint64
+	misses      uint64
+	onEvict     []func(key K, value V) // Observers registered by OnEvict.
+	jsonOptions *JSONOptions           // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// NewLRUMap creates and returns an empty LRU map holding at most `capacity` entries,
+// which is taken as 1 if it's less than 1.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewLRUMap[K comparable, V any](capacity int, safe ...bool) *LRUMap[K, V] {
+	return &LRUMap[K, V]{
+		mu:       rwmutex.Create(safe...),
+		data:     make(map[K]*Element[*gListMapNode[K, V]]),
+		list:     NewLinkedList[*gListMapNode[K, V]](),
+		capacity: max(capacity, 1),
+	}
+}
+
+// OnEvict registers `f` to be called with every entry evicted for the capacity, after the map is unlocked.
+// It's not called for the entries removed by Remove, Removes or Clear.
+func (m *LRUMap[K, V]) OnEvict(f func(key K, value V)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onEvict = append(m.onEvict, f)
+}
+
+// notifyEvict calls the observers registered by OnEvict with `evicted`, which must be called without lock.
+func (m *LRUMap[K, V]) notifyEvict(evicted []*gListMapNode[K, V]) {
+	if len(evicted) == 0 {
+		return
+	}
+	m.mu.RLock()
+	observers := m.onEvict
+	m.mu.RUnlock()
+	for _, node := range evicted {
+		for _, f := range observers {
+			f(node.key, node.value)
+		}
+	}
+}
+
+// Capacity returns the max number of entries of the map.
+func (m *LRUMap[K, V]) Capacity() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.capacity
+}
+
+// SetCapacity changes the max number of entries of the map to `capacity`, which is taken as 1
+// if it's less than 1, and evicts the least recently used entries beyond it.
+func (m *LRUMap[K, V]) SetCapacity(capacity int) {
+	var evicted []*gListMapNode[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.capacity = max(capacity, 1)
+	evicted = m.evictWithoutLock(evicted)
+}
+
+// Hits returns the number of the lookups which found the key.
+func (m *LRUMap[K, V]) Hits() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hits
+}
+
+// Misses returns the number of the lookups which didn't find the key.
+func (m *LRUMap[K, V]) Misses() uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.misses
+}
+
+// ResetStats resets the hit and miss counters to zero.
+func (m *LRUMap[K, V]) ResetStats() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hits, m.misses = 0, 0
+}
+
+// Put sets key-value to the map as the most recently used entry,
+// and evicts the least recently used entry if the map is full.
+func (m *LRUMap[K, V]) Put(key K, value V) {
+	var evicted []*gListMapNode[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putWithoutLock(key, value)
+	evicted = m.evictWithoutLock(evicted)
+}
+
+// Puts batch sets key-values to the map, and evicts the least recently used entries beyond the capacity.
+func (m *LRUMap[K, V]) Puts(data map[K]V) {
+	var evicted []*gListMapNode[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range data {
+		m.putWithoutLock(k, v)
+		evicted = m.evictWithoutLock(evicted)
+	}
+}
+
+// PutAll batch sets key-values to the map, which is alias of Puts.
+func (m *LRUMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the map is locked.
+func (m *LRUMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	var evicted []*gListMapNode[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		m.putWithoutLock(entry.Key, entry.Value)
+		evicted = m.evictWithoutLock(evicted)
+	}
+}
+
+// putWithoutLock sets key-value to the map as the most recently used entry without eviction.
+func (m *LRUMap[K, V]) putWithoutLock(key K, value V) {
+	if e, ok := m.data[key]; ok {
+		e.Value.value = value
+		m.list.MoveToFront(e)
+		return
+	}
+	m.data[key] = m.list.PushFront(&gListMapNode[K, V]{key, value})
+}
+
+// evictWithoutLock removes the least recently used entries beyond the capacity, and appends them to `evicted`.
+func (m *LRUMap[K, V]) evictWithoutLock(evicted []*gListMapNode[K, V]) []*gListMapNode[K, V] {
+	for len(m.data) > m.capacity {
+		e := m.list.Back()
+		delete(m.data, e.Value.key)
+		m.list.remove(e)
+		evicted = append(evicted, e.Value)
+	}
+	return evicted
+}
+
+// Search searches the map with given `key`, and marks the entry as the most recently used if it's found.
+// Second return parameter `found` is true if key was found, otherwise false.
+func (m *LRUMap[K, V]) Search(key K) (value V, found bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.data[key]
+	if !ok {
+		m.misses++
+		return
+	}
+	m.hits++
+	m.list.MoveToFront(e)
+	return e.Value.value, true
+}
+
+// Get returns the value by given `key`, or empty value of type V if the key is not found in the map.
+// It marks the entry as the most recently used if it's found.
+func (m *LRUMap[K, V]) Get(key K) (value V) {
+	value, _ = m.Search(key)
+	return
+}
+
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *LRUMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *LRUMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
+// Peek returns the value by given `key` like Search, but neither marks the entry as used nor counts the lookup.
+func (m *LRUMap[K, V]) Peek(key K) (value V, found bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if e, ok := m.data[key]; ok {
+		return e.Value.value, true
+	}
+	return
+}
+
+// GetOrPut returns the value by key,
+// or sets value with given `value` if it does not exist and then returns this value.
+func (m *LRUMap[K, V]) GetOrPut(key K, value V) V {
+	v, _ := m.getOrPutFunc(key, func() V { return value })
+	return v
+}
+
+// GetOrPutFunc returns the value by key,
+// or sets value with returned value of callback function `f` if it does not exist
+// and then returns this value.
+// Note that `f` is called with the map locked.
+func (m *LRUMap[K, V]) GetOrPutFunc(key K, f func() V) V {
+	v, _ := m.getOrPutFunc(key, f)
+	return v
+}
+
+// PutIfAbsent sets `value` to the map if the `key` does not exist, and then returns true.
+// It returns false if `key` exists, and `value` would be ignored.
+func (m *LRUMap[K, V]) PutIfAbsent(key K, value V) bool {
+	_, put := m.getOrPutFunc(key, func() V { return value })
+	return put
+}
+
+// PutIfAbsentFunc sets value with return value of callback function `f`, and then returns true.
+// It returns false if `key` exists, and `f` would not be called.
+func (m *LRUMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
+	_, put := m.getOrPutFunc(key, f)
+	return put
+}
+
+// getOrPutFunc returns the value by key as a use of the entry,
+// or sets value with returned value of `f` if it does not exist, in which case `put` is true.
+func (m *LRUMap[K, V]) getOrPutFunc(key K, f func() V) (value V, put bool) {
+	var evicted []*gListMapNode[K, V]
+	defer func() { m.notifyEvict(evicted) }()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.data[key]; ok {
+		m.hits++
+		m.list.MoveToFront(e)
+		return e.Value.value, false
+	}
+	m.misses++
+	value = f()
+	m.putWithoutLock(key, value)
+	evicted = m.evictWithoutLock(evicted)
+	return value, true
+}
+
+// Remove deletes value from map by given `key`, and return this deleted value.
+func (m *LRUMap[K, V]) Remove(key K) (value V, removed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.data[key]; ok {
+		delete(m.data, key)
+		m.list.remove(e)
+		return e.Value.value, true
+	}
+	return
+}
+
+// Removes batch deletes values of the map by keys.
+func (m *LRUMap[K, V]) Removes(keys []K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if e, ok := m.data[key]; ok {
+			delete(m.data, key)
+			m.list.remove(e)
+		}
+	}
+}
+
+// ForEach iterates the map readonly from the most recently used entry to the least recently used one
+// with custom callback function `f`, which doesn't count as uses of the entries.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LRUMap[K, V]) ForEach(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.list.ForEachAsc(func(node *gListMapNode[K, V]) bool {
+		return f(node.key, node.value)
+	})
+}
+
+// Entries returns all key-value pairs of the map as a slice of entries, in the same order as ForEach.
+func (m *LRUMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *LRUMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LRUMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *LRUMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
+// ContainsKey checks whether a key exists, which doesn't count as a use of the entry.
+func (m *LRUMap[K, V]) ContainsKey(key K) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.data[key]
+	return ok
+}
+
+// Size returns the size of the map.
+func (m *LRUMap[K, V]) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.data)
+}
+
+// IsEmpty checks whether the map is empty.
+// It returns true if map is empty, or else false.
+func (m *LRUMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Keys returns all keys of the map as a slice, from the most recently used to the least recently used.
+func (m *LRUMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Size())
+	m.ForEach(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values of the map as a slice, from the most recently used to the least recently used.
+func (m *LRUMap[K, V]) Values() []V {
+	values := make([]V, 0, m.Size())
+	m.ForEach(func(_ K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Map returns a copy of the underlying data of the map.
+func (m *LRUMap[K, V]) Map() map[K]V {
+	data := make(map[K]V, m.Size())
+	m.ForEach(func(k K, v V) bool {
+		data[k] = v
+		return true
+	})
+	return data
+}
+
+// MapStrAny returns a copy of the underlying data of the map as map[string]V.
+func (m *LRUMap[K, V]) MapStrAny() map[string]V {
+	data := make(map[string]V, m.Size())
+	m.ForEach(func(k K, v V) bool {
+		data[gconv.String(k)] = v
+		return true
+	})
+	return data
+}
+
+// Clear deletes all data of the map, which keeps the capacity and the hit/miss counters.
+func (m *LRUMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = make(map[K]*Element[*gListMapNode[K, V]])
+	m.list = NewLinkedList[*gListMapNode[K, V]]()
+}
+
+// Replace the data of the map with given `data`, of which the entries beyond the capacity are evicted.
+func (m *LRUMap[K, V]) Replace(data map[K]V) {
+	m.Clear()
+	m.Puts(data)
+}
+
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// all done under one lock. It doesn't count as uses of the entries, so the order of recency is kept.
+func (m *LRUMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.list.ForEachAsc(func(node *gListMapNode[K, V]) bool {
+		node.value = f(node.key, node.value)
+		return true
+	})
+}
+
+// Clone returns a new LRU map with the same capacity and copy of current map data in the same order.
+// The hit/miss counters and the observers are not copied.
+func (m *LRUMap[K, V]) Clone(safe ...bool) Map[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := NewLRUMap[K, V](m.capacity, safe...)
+	m.list.ForEachDesc(func(node *gListMapNode[K, V]) bool {
+		newMap.data[node.key] = newMap.list.PushFront(&gListMapNode[K, V]{node.key, node.value})
+		return true
+	})
+	return newMap
+}
+
+// String returns the map as a string.
+func (m *LRUMap[K, V]) String() string {
+	if m == nil {
+		return ""
+	}
+	b, _ := m.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *LRUMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *LRUMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *LRUMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *LRUMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (m *LRUMap[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
+}
@@ -0,0 +1,188 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestLRUMap_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLRUMap[string, int](3)
+		var _ g.Map[string, int] = m
+		var _ g.ExtendedMap[string, int] = m
+		t.Assert(m.Capacity(), 3)
+		m.Put("a", 1)
+		m.Put("b", 2)
+		m.Put("c", 3)
+		t.Assert(m.Keys(), []string{"c", "b", "a"})
+		t.Assert(m.Get("a"), 1)
+		t.Assert(m.Keys(), []string{"a", "c", "b"})
+
+		m.Put("d", 4)
+		t.Assert(m.Keys(), []string{"d", "a", "c"})
+		t.Assert(m.ContainsKey("b"), false)
+		t.Assert(m.Size(), 3)
+
+		m.Put("c", 30)
+		t.Assert(m.Keys(), []string{"c", "d", "a"})
+		t.Assert(m.Values(), []int{30, 4, 1})
+
+		v, found := m.Peek("a")
+		t.Assert(v, 1)
+		t.Assert(found, true)
+		t.Assert(m.Keys(), []string{"c", "d", "a"})
+
+		t.Assert(m.PutIfAbsent("d", 40), false)
+		t.Assert(m.Keys(), []string{"d", "c", "a"})
+		t.Assert(m.GetOrPutFunc("e", func() int { return 5 }), 5)
+		t.Assert(m.Keys(), []string{"e", "d", "c"})
+
+		v, removed := m.Remove("d")
+		t.Assert(v, 4)
+		t.Assert(removed, true)
+		m.Removes([]string{"c", "x"})
+		t.Assert(m.Map(), map[string]int{"e": 5})
+		m.Clear()
+		t.Assert(m.IsEmpty(), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLRUMap[int, int](0)
+		t.Assert(m.Capacity(), 1)
+		m.Put(1, 1)
+		m.Put(2, 2)
+		t.Assert(m.Keys(), []int{2})
+	})
+}
+
+func TestLRUMap_OnEvict(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var evicted []string
+		m := g.NewLRUMap[string, int](2, true)
+		m.OnEvict(func(key string, value int) {
+			evicted = append(evicted, fmt.Sprintf("%s=%d", key, value))
+			// The map is unlocked when the observers are called.
+			m.Size()
+		})
+		m.Put("a", 1)
+		m.Put("b", 2)
+		m.Remove("b")
+		m.Put("c", 3)
+		t.Assert(evicted, nil)
+		m.Put("d", 4)
+		t.Assert(evicted, []string{"a=1"})
+		m.GetOrPut("e", 5)
+		t.Assert(evicted, []string{"a=1", "c=3"})
+
+		m.Put("f", 6)
+		m.Put("g", 7)
+		m.SetCapacity(1)
+		t.Assert(m.Keys(), []string{"g"})
+		t.Assert(evicted, []string{"a=1", "c=3", "d=4", "e=5", "f=6"})
+
+		m.SetCapacity(3)
+		m.Replace(map[string]int{"x": 1})
+		t.Assert(m.Keys(), []string{"x"})
+		t.Assert(len(evicted), 5)
+	})
+}
+
+func TestLRUMap_Stats(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLRUMap[int, string](2)
+		m.Put(1, "a")
+		m.Get(1)
+		m.Get(2)
+		m.Search(1)
+		m.GetOrPut(3, "c")
+		m.Peek(3)
+		m.ContainsKey(2)
+		t.Assert(m.Hits(), 2)
+		t.Assert(m.Misses(), 2)
+		m.ResetStats()
+		t.Assert(m.Hits(), 0)
+		t.Assert(m.Misses(), 0)
+	})
+}
+
+func TestLRUMap_Clone(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLRUMap[int, int](3)
+		m.Put(1, 1)
+		m.Put(2, 2)
+		m.Put(3, 3)
+		m.Get(1)
+		c := m.Clone().(*g.LRUMap[int, int])
+		t.Assert(c.Keys(), []int{1, 3, 2})
+		t.Assert(c.Capacity(), 3)
+		c.Put(4, 4)
+		t.Assert(c.Keys(), []int{4, 1, 3})
+		t.Assert(m.Keys(), []int{1, 3, 2})
+	})
+}
+
+func TestLRUMap_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			m  = g.NewLRUMap[int, int](16, true)
+			wg sync.WaitGroup
+		)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					m.Put(j%32, j)
+					m.Get((j + i) % 32)
+				}
+			}()
+		}
+		wg.Wait()
+		t.Assert(m.Size(), 16)
+		t.Assert(m.Hits()+m.Misses(), 4000)
+	})
+}
+
+func TestLRUMap_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewLRUMap[string, int](2)
+		m.Put("a", 1)
+		t.Assert(m.String(), `{"a":1}`)
+		t.Assert(fmt.Sprint(m), m.String())
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"a":1}`)
+
+		var nilMap *g.LRUMap[string, int]
+		t.Assert(nilMap.String(), "")
+	})
+}
+
+func TestLRUMap_PutAllFrom(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var evicted []int
+		m := g.NewLRUMap[int, int](2)
+		m.OnEvict(func(key int, value int) {
+			evicted = append(evicted, key)
+		})
+		other := g.NewListMap[int, int]()
+		other.Put(1, 1)
+		other.Put(2, 2)
+		other.Put(3, 3)
+		m.PutAllFrom(other)
+		t.Assert(evicted, []int{1})
+		t.Assert(m.Keys(), []int{3, 2})
+	})
+}
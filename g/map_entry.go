@@ -0,0 +1,34 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// Entry is a key-value pair of a map, which keeps the key and its value together
+// for the callers snapshotting or sorting the content of a map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// collectEntries collects the key-value pairs iterated by `forEach` as entries.
+// The parameter `size` is the capacity hint of the returned slice.
+func collectEntries[K comparable, V any](forEach func(f func(key K, value V) bool), size int) []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, size)
+	forEach(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+		return true
+	})
+	return entries
+}
+
+// mapEntries returns the key-value pairs of `m` as entries, in the order of m.ForEach.
+// It uses Entries if `m` is an ExtendedMap, which snapshots the pairs under one lock.
+func mapEntries[K comparable, V any](m Map[K, V]) []Entry[K, V] {
+	if extended, ok := m.(ExtendedMap[K, V]); ok {
+		return extended.Entries()
+	}
+	return collectEntries(m.ForEach, m.Size())
+}
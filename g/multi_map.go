@@ -0,0 +1,288 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// MultiMap is a map which maps each key to a collection of values.
+// The values of a key are kept in an ArrayList, which keeps the duplicated values in insertion order,
+// if it's created by NewListMultiMap, or in a HashSet, which keeps the values unique, by NewSetMultiMap.
+//
+// The collections are created on the first Put of their keys, and removed with their last values,
+// so there's no key mapped to an empty collection. They are guarded by the lock of the map,
+// and only copies of them are returned.
+type MultiMap[K comparable, V comparable] struct {
+	mu            rwmutex.RWMutex
+	data          map[K]Collection[V]
+	size          int // Number of all the values.
+	newCollection func() Collection[V]
+	jsonOptions   *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// NewListMultiMap creates and returns an empty multimap, which keeps the values of each key in a list.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewListMultiMap[K comparable, V comparable](safe ...bool) *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		mu:   rwmutex.Create(safe...),
+		data: make(map[K]Collection[V]),
+		newCollection: func() Collection[V] {
+			return NewArrayList[V]()
+		},
+	}
+}
+
+// NewSetMultiMap creates and returns an empty multimap, which keeps the values of each key in a set.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewSetMultiMap[K comparable, V comparable](safe ...bool) *MultiMap[K, V] {
+	return &MultiMap[K, V]{
+		mu:   rwmutex.Create(safe...),
+		data: make(map[K]Collection[V]),
+		newCollection: func() Collection[V] {
+			return NewHashSet[V]()
+		},
+	}
+}
+
+// Put adds `values` to the collection of `key`.
+// It returns true if the multimap changed, which is false if all the values are already in the set of `key`.
+func (m *MultiMap[K, V]) Put(key K, values ...V) bool {
+	if len(values) == 0 {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.data[key]
+	if !ok {
+		c = m.newCollection()
+	}
+	size := c.Size()
+	c.Add(values...)
+	if c.Size() == size {
+		return false
+	}
+	m.data[key] = c
+	m.size += c.Size() - size
+	return true
+}
+
+// Get returns a copy of the collection of `key`, or nil if the key does not exist.
+func (m *MultiMap[K, V]) Get(key K) Collection[V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if c, ok := m.data[key]; ok {
+		return c.Clone()
+	}
+	return nil
+}
+
+// GetSlice returns the values of `key` as a slice, or nil if the key does not exist.
+func (m *MultiMap[K, V]) GetSlice(key K) []V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if c, ok := m.data[key]; ok {
+		return c.Slice()
+	}
+	return nil
+}
+
+// Remove removes one occurrence of `value` from the collection of `key`, and returns true if it's found.
+// The key is removed with its last value.
+func (m *MultiMap[K, V]) Remove(key K, value V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c, ok := m.data[key]
+	if !ok {
+		return false
+	}
+	size := c.Size()
+	c.Remove(value)
+	if c.Size() == size {
+		return false
+	}
+	m.size -= size - c.Size()
+	if c.IsEmpty() {
+		delete(m.data, key)
+	}
+	return true
+}
+
+// RemoveKey removes `key` with all its values, and returns the collection of them.
+func (m *MultiMap[K, V]) RemoveKey(key K) (values Collection[V], removed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if values, removed = m.data[key]; removed {
+		delete(m.data, key)
+		m.size -= values.Size()
+	}
+	return
+}
+
+// ContainsKey checks whether `key` has any value.
+func (m *MultiMap[K, V]) ContainsKey(key K) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.data[key]
+	return ok
+}
+
+// ContainsEntry checks whether `value` is in the collection of `key`.
+func (m *MultiMap[K, V]) ContainsEntry(key K, value V) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.data[key]
+	return ok && c.Contains(value)
+}
+
+// Count returns the number of values of `key`.
+func (m *MultiMap[K, V]) Count(key K) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if c, ok := m.data[key]; ok {
+		return c.Size()
+	}
+	return 0
+}
+
+// Size returns the number of all the values of the multimap.
+func (m *MultiMap[K, V]) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.size
+}
+
+// KeySize returns the number of keys of the multimap.
+func (m *MultiMap[K, V]) KeySize() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.data)
+}
+
+// IsEmpty checks whether the multimap is empty.
+func (m *MultiMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Keys returns all keys of the multimap as a slice.
+func (m *MultiMap[K, V]) Keys() []K {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]K, 0, len(m.data))
+	for k := range m.data {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ForEach iterates all the key-value pairs readonly with custom callback function `f`,
+// in which a key is passed with each of its values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *MultiMap[K, V]) ForEach(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for k, c := range m.data {
+		next := true
+		c.ForEach(func(v V) bool {
+			next = f(k, v)
+			return next
+		})
+		if !next {
+			break
+		}
+	}
+}
+
+// Map returns a copy of the multimap as map of the keys to the slices of their values.
+func (m *MultiMap[K, V]) Map() map[K][]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[K][]V, len(m.data))
+	for k, c := range m.data {
+		data[k] = c.Slice()
+	}
+	return data
+}
+
+// Clear deletes all data of the multimap.
+func (m *MultiMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data = make(map[K]Collection[V])
+	m.size = 0
+}
+
+// Clone returns a new multimap of the same variant with copy of current data.
+func (m *MultiMap[K, V]) Clone(safe ...bool) *MultiMap[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := &MultiMap[K, V]{
+		mu:            rwmutex.Create(safe...),
+		data:          make(map[K]Collection[V], len(m.data)),
+		size:          m.size,
+		newCollection: m.newCollection,
+	}
+	for k, c := range m.data {
+		newMap.data[k] = c.Clone()
+	}
+	return newMap
+}
+
+// String returns the multimap as a string.
+func (m *MultiMap[K, V]) String() string {
+	if m == nil {
+		return ""
+	}
+	b, _ := m.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *MultiMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable, which formats each key with the slice of its values.
+func (m *MultiMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.KeySize()
+	return collectFormatPairs(func(f func(key K, values []V) bool) {
+		for k, values := range m.Map() {
+			if !f(k, values) {
+				break
+			}
+		}
+	}, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *MultiMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *MultiMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal,
+// which marshals each key with the array of its values.
+func (m *MultiMap[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
+}
@@ -0,0 +1,133 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestMultiMap_List(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMultiMap[string, int]()
+		t.Assert(m.Put("a", 1, 2), true)
+		t.Assert(m.Put("a", 1), true)
+		t.Assert(m.Put("b"), false)
+		t.Assert(m.Put("b", 3), true)
+		t.Assert(m.GetSlice("a"), []int{1, 2, 1})
+		t.Assert(m.Get("a").Slice(), []int{1, 2, 1})
+		t.Assert(m.Get("x"), nil)
+		t.Assert(m.GetSlice("x"), nil)
+		t.Assert(m.Count("a"), 3)
+		t.Assert(m.Size(), 4)
+		t.Assert(m.KeySize(), 2)
+		t.Assert(m.ContainsKey("b"), true)
+		t.Assert(m.ContainsEntry("a", 2), true)
+		t.Assert(m.ContainsEntry("b", 2), false)
+
+		// The returned collection is a copy.
+		m.Get("a").Add(9)
+		t.Assert(m.Count("a"), 3)
+
+		t.Assert(m.Remove("a", 1), true)
+		t.Assert(m.GetSlice("a"), []int{2, 1})
+		t.Assert(m.Remove("a", 5), false)
+		t.Assert(m.Remove("x", 1), false)
+		t.Assert(m.Remove("b", 3), true)
+		t.Assert(m.ContainsKey("b"), false)
+		t.Assert(m.Size(), 2)
+
+		values, removed := m.RemoveKey("a")
+		t.Assert(removed, true)
+		t.Assert(values.Slice(), []int{2, 1})
+		_, removed = m.RemoveKey("a")
+		t.Assert(removed, false)
+		t.Assert(m.IsEmpty(), true)
+	})
+}
+
+func TestMultiMap_Set(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewSetMultiMap[string, int]()
+		t.Assert(m.Put("a", 1, 2, 1), true)
+		t.Assert(m.Put("a", 2), false)
+		t.Assert(m.Count("a"), 2)
+		t.Assert(m.Size(), 2)
+		values := m.GetSlice("a")
+		slices.Sort(values)
+		t.Assert(values, []int{1, 2})
+		t.Assert(m.Remove("a", 1), true)
+		t.Assert(m.Remove("a", 1), false)
+
+		m.Put("b", 3)
+		keys := m.Keys()
+		slices.Sort(keys)
+		t.Assert(keys, []string{"a", "b"})
+		t.Assert(m.Map(), map[string][]int{"a": {2}, "b": {3}})
+
+		c := m.Clone()
+		t.Assert(c.Put("b", 3), false)
+		c.Put("b", 4)
+		t.Assert(c.Size(), 3)
+		t.Assert(m.Size(), 2)
+
+		sum := 0
+		m.ForEach(func(key string, value int) bool {
+			sum += value
+			return true
+		})
+		t.Assert(sum, 5)
+		m.Clear()
+		t.Assert(m.IsEmpty(), true)
+		t.Assert(m.KeySize(), 0)
+	})
+}
+
+func TestMultiMap_Concurrent(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			m  = g.NewListMultiMap[int, int](true)
+			wg sync.WaitGroup
+		)
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					m.Put(j%10, i)
+				}
+			}()
+		}
+		wg.Wait()
+		t.Assert(m.Size(), 400)
+		t.Assert(m.KeySize(), 10)
+		t.Assert(m.Count(0), 40)
+	})
+}
+
+func TestMultiMap_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewListMultiMap[string, int]()
+		m.Put("a", 1, 1)
+		t.Assert(m.String(), `{"a":[1,1]}`)
+		t.Assert(fmt.Sprint(m), m.String())
+		b, err := json.Marshal(m)
+		t.AssertNil(err)
+		t.Assert(string(b), `{"a":[1,1]}`)
+
+		var nilMap *g.MultiMap[string, int]
+		t.Assert(nilMap.String(), "")
+	})
+}
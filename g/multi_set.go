@@ -0,0 +1,450 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/deepcopy"
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+// MultiSet implements the Collection interface as a bag, which contains items with multiplicity,
+// backed by a golang map from the distinct items to their counts.
+// The Collection methods treat each occurrence as an element, e.g. Size returns the total count,
+// and Slice and ForEach repeat each item by its count.
+// It makes no guarantees as to the iteration order of the distinct items.
+type MultiSet[T comparable] struct {
+	mu          rwmutex.RWMutex
+	data        map[T]int
+	size        int          // Total count of all the items.
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// NewMultiSet creates and returns an empty multiset.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewMultiSet[T comparable](safe ...bool) *MultiSet[T] {
+	return &MultiSet[T]{
+		data: make(map[T]int),
+		mu:   rwmutex.Create(safe...),
+	}
+}
+
+// NewMultiSetFrom creates and returns a multiset from `items`, in which each occurrence is counted.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewMultiSetFrom[T comparable](items []T, safe ...bool) *MultiSet[T] {
+	set := NewMultiSet[T](safe...)
+	for _, item := range items {
+		set.data[item]++
+	}
+	set.size = len(items)
+	return set
+}
+
+// lazyInit lazily initializes a zero MultiSet value.
+func (set *MultiSet[T]) lazyInit() {
+	if set.data == nil {
+		set.data = make(map[T]int)
+	}
+}
+
+// doAddWithoutLock adds `n` occurrences of `item`, and returns the count of `item` after adding.
+func (set *MultiSet[T]) doAddWithoutLock(item T, n int) int {
+	if n > 0 {
+		set.data[item] += n
+		set.size += n
+	}
+	return set.data[item]
+}
+
+// doRemoveWithoutLock removes at most `n` occurrences of `item`, and returns the number of removed occurrences.
+func (set *MultiSet[T]) doRemoveWithoutLock(item T, n int) int {
+	count := set.data[item]
+	if n <= 0 || count == 0 {
+		return 0
+	}
+	if n >= count {
+		delete(set.data, item)
+		n = count
+	} else {
+		set.data[item] = count - n
+	}
+	set.size -= n
+	return n
+}
+
+// countsOf returns a copy of the counts of the items in `items`.
+// It copies the counts of another MultiSet directly, or counts the occurrences of other collections.
+func countsOf[T comparable](items Collection[T]) map[T]int {
+	if other, ok := items.(*MultiSet[T]); ok {
+		other.mu.RLock()
+		defer other.mu.RUnlock()
+		counts := make(map[T]int, len(other.data))
+		for k, n := range other.data {
+			counts[k] = n
+		}
+		return counts
+	}
+	counts := make(map[T]int)
+	items.ForEach(func(item T) bool {
+		counts[item]++
+		return true
+	})
+	return counts
+}
+
+// Add adds one occurrence of each of `items` to the set, and returns true if `items` is not empty.
+func (set *MultiSet[T]) Add(items ...T) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.lazyInit()
+	for _, item := range items {
+		set.doAddWithoutLock(item, 1)
+	}
+	return len(items) > 0
+}
+
+// AddN adds `n` occurrences of `item` to the set, and returns the count of `item` after adding.
+// It does nothing if `n` <= 0.
+func (set *MultiSet[T]) AddN(item T, n int) (count int) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.lazyInit()
+	return set.doAddWithoutLock(item, n)
+}
+
+// AddAll adds all the occurrences of the elements in collection `items` to the set,
+// so that adding another MultiSet sums up the counts.
+// It returns true if the set changed as a result of the call.
+func (set *MultiSet[T]) AddAll(items Collection[T]) bool {
+	counts := countsOf(items)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.lazyInit()
+	for k, n := range counts {
+		set.doAddWithoutLock(k, n)
+	}
+	return len(counts) > 0
+}
+
+// Remove removes one occurrence of each of `items` from the set.
+// It returns true if any occurrence is removed.
+func (set *MultiSet[T]) Remove(items ...T) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	changed := false
+	for _, item := range items {
+		if set.doRemoveWithoutLock(item, 1) > 0 {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RemoveN removes at most `n` occurrences of `item` from the set, and returns the number of removed occurrences.
+func (set *MultiSet[T]) RemoveN(item T, n int) (removed int) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return set.doRemoveWithoutLock(item, n)
+}
+
+// RemoveAll removes all the occurrences of the items contained in collection `items` from the set.
+// It returns true if the set changed as a result of the call.
+func (set *MultiSet[T]) RemoveAll(items Collection[T]) bool {
+	counts := countsOf(items)
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	changed := false
+	for k := range counts {
+		if set.doRemoveWithoutLock(k, set.data[k]) > 0 {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// SetCount sets the count of `item` to `count`, removing `item` if `count` <= 0,
+// and returns the previous count of `item`.
+func (set *MultiSet[T]) SetCount(item T, count int) (previous int) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.lazyInit()
+	previous = set.data[item]
+	if count > previous {
+		set.doAddWithoutLock(item, count-previous)
+	} else {
+		set.doRemoveWithoutLock(item, previous-count)
+	}
+	return
+}
+
+// Count returns the number of occurrences of `item` in the set.
+func (set *MultiSet[T]) Count(item T) int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.data[item]
+}
+
+// Contains checks whether the set contains at least one occurrence of `item`.
+func (set *MultiSet[T]) Contains(item T) bool {
+	return set.Count(item) > 0
+}
+
+// ContainsAll returns true if the set contains at least as many occurrences of each item
+// as collection `items` does.
+func (set *MultiSet[T]) ContainsAll(items Collection[T]) bool {
+	counts := countsOf(items)
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for k, n := range counts {
+		if set.data[k] < n {
+			return false
+		}
+	}
+	return true
+}
+
+// Distinct returns the distinct items of the set as slice.
+func (set *MultiSet[T]) Distinct() []T {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	items := make([]T, 0, len(set.data))
+	for k := range set.data {
+		items = append(items, k)
+	}
+	return items
+}
+
+// DistinctSize returns the number of distinct items of the set.
+func (set *MultiSet[T]) DistinctSize() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return len(set.data)
+}
+
+// Size returns the total count of all the items of the set.
+func (set *MultiSet[T]) Size() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.size
+}
+
+// IsEmpty returns true if the set contains no items.
+func (set *MultiSet[T]) IsEmpty() bool {
+	return set.Size() == 0
+}
+
+// Clear deletes all items of the set.
+func (set *MultiSet[T]) Clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.data = make(map[T]int)
+	set.size = 0
+}
+
+// Clone returns a new multiset with the same counts of the items.
+func (set *MultiSet[T]) Clone() Collection[T] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	clone := NewMultiSet[T](set.mu.IsSafe())
+	for k, n := range set.data {
+		clone.data[k] = n
+	}
+	clone.size = set.size
+	return clone
+}
+
+// DeepCopy implements interface for deep copy of current type.
+func (set *MultiSet[T]) DeepCopy() Collection[T] {
+	if set == nil {
+		return nil
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	clone := NewMultiSet[T](set.mu.IsSafe())
+	for k, n := range set.data {
+		clone.data[deepcopy.Copy(k).(T)] += n
+	}
+	clone.size = set.size
+	return clone
+}
+
+// ForEach iterates each occurrence of the items readonly with given callback function `f`,
+// in which an item is repeated by its count.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (set *MultiSet[T]) ForEach(f func(v T) bool) {
+	set.ForEachCount(func(v T, count int) bool {
+		for i := 0; i < count; i++ {
+			if !f(v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ForEachCount iterates the distinct items with their counts readonly with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (set *MultiSet[T]) ForEachCount(f func(v T, count int) bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for k, n := range set.data {
+		if !f(k, n) {
+			break
+		}
+	}
+}
+
+// Slice returns all the occurrences of the items as slice, in which an item is repeated by its count.
+func (set *MultiSet[T]) Slice() []T {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	items := make([]T, 0, set.size)
+	for k, n := range set.data {
+		for i := 0; i < n; i++ {
+			items = append(items, k)
+		}
+	}
+	return items
+}
+
+// Equals checks whether the set contains the same items with the same counts as collection `another`.
+func (set *MultiSet[T]) Equals(another Collection[T]) bool {
+	if set == another {
+		return true
+	}
+	counts := countsOf(another)
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if len(set.data) != len(counts) {
+		return false
+	}
+	for k, n := range set.data {
+		if counts[k] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new multiset with the items in `set` or `other`,
+// of which the count is the larger one of the counts in `set` and `other`.
+func (set *MultiSet[T]) Union(other *MultiSet[T]) (newSet *MultiSet[T]) {
+	counts := countsOf[T](other)
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	newSet = NewMultiSet[T]()
+	for k, n := range set.data {
+		newSet.doAddWithoutLock(k, max(n, counts[k]))
+	}
+	for k, n := range counts {
+		if _, ok := set.data[k]; !ok {
+			newSet.doAddWithoutLock(k, n)
+		}
+	}
+	return
+}
+
+// Intersect returns a new multiset with the items in both `set` and `other`,
+// of which the count is the smaller one of the counts in `set` and `other`.
+func (set *MultiSet[T]) Intersect(other *MultiSet[T]) (newSet *MultiSet[T]) {
+	counts := countsOf[T](other)
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	newSet = NewMultiSet[T]()
+	for k, n := range set.data {
+		newSet.doAddWithoutLock(k, min(n, counts[k]))
+	}
+	return
+}
+
+// Diff returns a new multiset with the items in `set` more than in `other`,
+// of which the count is the count in `set` minus the count in `other`.
+func (set *MultiSet[T]) Diff(other *MultiSet[T]) (newSet *MultiSet[T]) {
+	counts := countsOf[T](other)
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	newSet = NewMultiSet[T]()
+	for k, n := range set.data {
+		newSet.doAddWithoutLock(k, n-counts[k])
+	}
+	return
+}
+
+// Join joins all the occurrences of the items with a string `glue`.
+func (set *MultiSet[T]) Join(glue string) string {
+	buffer := bytes.NewBuffer(nil)
+	first := true
+	set.ForEach(func(v T) bool {
+		if !first {
+			buffer.WriteString(glue)
+		}
+		first = false
+		buffer.WriteString(gconv.String(v))
+		return true
+	})
+	return buffer.String()
+}
+
+// String returns all the occurrences of the items as a string, which implements like json.Marshal does.
+func (set *MultiSet[T]) String() string {
+	if set == nil {
+		return ""
+	}
+	b, _ := set.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the items with their counts in the pretty
+// form limited by FormatOptions, and the other verbs print them formatted with the verb.
+func (set *MultiSet[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, set)
+}
+
+// formatEntries implements the interface formattable.
+func (set *MultiSet[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = set.DistinctSize()
+	return collectFormatPairs(set.ForEachCount, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the items with their counts using `seed`,
+// which is insensitive to the order of items.
+func (set *MultiSet[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, set.ForEachCount)
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (set *MultiSet[T]) SetMarshalOptions(options JSONOptions) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal,
+// which marshals all the occurrences of the items as an array.
+func (set *MultiSet[T]) MarshalJSON() ([]byte, error) {
+	set.mu.RLock()
+	options := set.jsonOptions
+	set.mu.RUnlock()
+	return marshalJSONSlice(options, set.Slice())
+}
+
+// UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal,
+// which adds each occurrence of the items in the array.
+func (set *MultiSet[T]) UnmarshalJSON(b []byte) error {
+	var array []T
+	if err := json.UnmarshalUseNumber(b, &array); err != nil {
+		return err
+	}
+	set.Add(array...)
+	return nil
+}
@@ -0,0 +1,159 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestMultiSet_Var(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var s g.MultiSet[string]
+		t.Assert(s.IsEmpty(), true)
+		t.Assert(s.Add("a", "b", "a"), true)
+		t.Assert(s.Count("a"), 2)
+		t.Assert(s.Count("b"), 1)
+		t.Assert(s.Count("c"), 0)
+		t.Assert(s.Size(), 3)
+		t.Assert(s.DistinctSize(), 2)
+		t.Assert(s.Contains("b"), true)
+		t.Assert(s.Contains("c"), false)
+	})
+}
+
+func TestMultiSet_AddRemove(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewMultiSet[int]()
+		t.Assert(s.AddN(1, 3), 3)
+		t.Assert(s.AddN(1, 2), 5)
+		t.Assert(s.AddN(2, 0), 0)
+		t.Assert(s.Contains(2), false)
+		t.Assert(s.Remove(1, 2), true)
+		t.Assert(s.Count(1), 4)
+		t.Assert(s.Remove(2), false)
+		t.Assert(s.RemoveN(1, 3), 3)
+		t.Assert(s.RemoveN(1, 3), 1)
+		t.Assert(s.Contains(1), false)
+		t.Assert(s.Size(), 0)
+
+		t.Assert(s.SetCount(5, 2), 0)
+		t.Assert(s.SetCount(5, 4), 2)
+		t.Assert(s.Size(), 4)
+		t.Assert(s.SetCount(5, 0), 4)
+		t.Assert(s.Contains(5), false)
+		t.Assert(s.IsEmpty(), true)
+
+		s.Add(1, 1, 2, 3)
+		t.Assert(s.AddAll(g.NewArrayListFrom([]int{1, 3})), true)
+		t.Assert(s.Count(1), 3)
+		t.Assert(s.Count(3), 2)
+		t.Assert(s.RemoveAll(g.NewArrayListFrom([]int{1, 4})), true)
+		t.Assert(s.RemoveAll(g.NewArrayListFrom([]int{4})), false)
+		t.Assert(s.Contains(1), false)
+		t.Assert(s.Size(), 3)
+		s.Clear()
+		t.Assert(s.Size(), 0)
+		t.Assert(s.DistinctSize(), 0)
+	})
+}
+
+func TestMultiSet_Iteration(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewMultiSetFrom([]int{3, 1, 3, 2, 3})
+		slice := s.Slice()
+		slices.Sort(slice)
+		t.Assert(slice, []int{1, 2, 3, 3, 3})
+		distinct := s.Distinct()
+		slices.Sort(distinct)
+		t.Assert(distinct, []int{1, 2, 3})
+
+		total := 0
+		s.ForEachCount(func(v int, count int) bool {
+			total += v * count
+			return true
+		})
+		t.Assert(total, 12)
+		n := 0
+		s.ForEach(func(v int) bool {
+			n++
+			return n < 4
+		})
+		t.Assert(n, 4)
+	})
+}
+
+func TestMultiSet_Compare(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewMultiSetFrom([]int{1, 1, 2})
+		t.Assert(s.Equals(g.NewMultiSetFrom([]int{2, 1, 1})), true)
+		t.Assert(s.Equals(g.NewArrayListFrom([]int{1, 2, 1})), true)
+		t.Assert(s.Equals(g.NewMultiSetFrom([]int{1, 2})), false)
+		t.Assert(s.Equals(g.NewMultiSetFrom([]int{1, 1, 3})), false)
+		t.Assert(s.ContainsAll(g.NewArrayListFrom([]int{1, 1})), true)
+		t.Assert(s.ContainsAll(g.NewArrayListFrom([]int{2, 2})), false)
+		t.Assert(s.Equals(s.Clone()), true)
+		t.Assert(s.Equals(s.DeepCopy()), true)
+	})
+}
+
+func TestMultiSet_SetOperations(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s1 := g.NewMultiSetFrom([]string{"a", "a", "a", "b", "c"})
+		s2 := g.NewMultiSetFrom([]string{"a", "b", "b", "d"})
+
+		union := s1.Union(s2)
+		t.Assert(union.Count("a"), 3)
+		t.Assert(union.Count("b"), 2)
+		t.Assert(union.Count("c"), 1)
+		t.Assert(union.Count("d"), 1)
+		t.Assert(union.Size(), 7)
+
+		intersect := s1.Intersect(s2)
+		t.Assert(intersect.Count("a"), 1)
+		t.Assert(intersect.Count("b"), 1)
+		t.Assert(intersect.Contains("c"), false)
+		t.Assert(intersect.DistinctSize(), 2)
+		t.Assert(intersect.Size(), 2)
+
+		diff := s1.Diff(s2)
+		t.Assert(diff.Count("a"), 2)
+		t.Assert(diff.Contains("b"), false)
+		t.Assert(diff.Count("c"), 1)
+		t.Assert(diff.Size(), 3)
+
+		t.Assert(s1.Union(s1).Equals(s1), true)
+		t.Assert(s1.Diff(s1).IsEmpty(), true)
+	})
+}
+
+func TestMultiSet_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewMultiSetFrom([]string{"a", "a"})
+		t.Assert(s.String(), `["a","a"]`)
+		t.Assert(s.Join(","), "a,a")
+		t.Assert(fmt.Sprint(s), s.String())
+
+		b, err := json.Marshal(s)
+		t.AssertNil(err)
+		t.Assert(string(b), `["a","a"]`)
+		var s2 g.MultiSet[string]
+		t.AssertNil(json.Unmarshal([]byte(`["x","y","x"]`), &s2))
+		t.Assert(s2.Count("x"), 2)
+		t.Assert(s2.Size(), 3)
+
+		var nilSet *g.MultiSet[int]
+		t.Assert(nilSet.String(), "")
+	})
+}
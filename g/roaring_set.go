@@ -0,0 +1,566 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+	"slices"
+	"strconv"
+
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+)
+
+const (
+	// roaringArrayMaxSize is the max cardinality of a container stored as sorted array,
+	// beyond which the bitmap of 8KB costs less memory than the array.
+	roaringArrayMaxSize = 4096
+
+	// roaringBitmapWords is the number of words of the bitmap of a container for 2^16 values.
+	roaringBitmapWords = 1 << 16 / 64
+)
+
+// RoaringSet implements the Set interface for unsigned integers as a compressed bitmap,
+// following the design of Roaring bitmaps.
+// The items are partitioned into containers by their high bits, and the low 16 bits of the items
+// are stored in each container, either as a sorted array if it's sparse or as a bitmap if it's dense.
+// The containers cost at most 2 bytes per item plus a small overhead per container, which suits large IDs
+// like user IDs that are sparse in the whole domain. It iterates the items in ascending order.
+type RoaringSet[T ~uint32 | ~uint64] struct {
+	mu          rwmutex.RWMutex
+	keys        []uint64            // Sorted high bits of the containers.
+	containers  []*roaringContainer // Containers for each of the keys.
+	size        int
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// roaringContainer contains the low 16 bits of the items with the same high bits.
+type roaringContainer struct {
+	array  []uint16 // Sorted values, used if bitmap is nil.
+	bitmap []uint64 // Bitmap of roaringBitmapWords words, or nil.
+	n      int      // Cardinality.
+}
+
+// NewRoaringSet creates and returns an empty compressed bitmap set.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewRoaringSet[T ~uint32 | ~uint64](safe ...bool) *RoaringSet[T] {
+	return &RoaringSet[T]{
+		mu: rwmutex.Create(safe...),
+	}
+}
+
+// NewRoaringSetFrom creates and returns a compressed bitmap set from `items`.
+// The parameter `safe` is used to specify whether using set in concurrent-safety, which is false in default.
+func NewRoaringSetFrom[T ~uint32 | ~uint64](items []T, safe ...bool) *RoaringSet[T] {
+	set := NewRoaringSet[T](safe...)
+	for _, item := range items {
+		set.doAddWithoutLock(item)
+	}
+	return set
+}
+
+// contains checks whether the container contains `low`.
+func (c *roaringContainer) contains(low uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[low/64]&(1<<(low%64)) != 0
+	}
+	_, found := slices.BinarySearch(c.array, low)
+	return found
+}
+
+// add adds `low` to the container, and returns true if it was not in the container.
+func (c *roaringContainer) add(low uint16) bool {
+	if c.bitmap != nil {
+		if c.bitmap[low/64]&(1<<(low%64)) != 0 {
+			return false
+		}
+		c.bitmap[low/64] |= 1 << (low % 64)
+		c.n++
+		return true
+	}
+	i, found := slices.BinarySearch(c.array, low)
+	if found {
+		return false
+	}
+	if c.n >= roaringArrayMaxSize {
+		c.toBitmap()
+		return c.add(low)
+	}
+	c.array = slices.Insert(c.array, i, low)
+	c.n++
+	return true
+}
+
+// remove removes `low` from the container, and returns true if it was in the container.
+func (c *roaringContainer) remove(low uint16) bool {
+	if c.bitmap != nil {
+		if c.bitmap[low/64]&(1<<(low%64)) == 0 {
+			return false
+		}
+		c.bitmap[low/64] &^= 1 << (low % 64)
+		c.n--
+		// Converts back to array only if it's well below the threshold, to avoid converting repeatedly.
+		if c.n <= roaringArrayMaxSize/2 {
+			c.toArray()
+		}
+		return true
+	}
+	i, found := slices.BinarySearch(c.array, low)
+	if !found {
+		return false
+	}
+	c.array = slices.Delete(c.array, i, i+1)
+	c.n--
+	return true
+}
+
+// toBitmap converts the container to bitmap.
+func (c *roaringContainer) toBitmap() {
+	c.bitmap = make([]uint64, roaringBitmapWords)
+	for _, low := range c.array {
+		c.bitmap[low/64] |= 1 << (low % 64)
+	}
+	c.array = nil
+}
+
+// toArray converts the container to sorted array.
+func (c *roaringContainer) toArray() {
+	array := make([]uint16, 0, c.n)
+	c.forEach(func(low uint16) bool {
+		array = append(array, low)
+		return true
+	})
+	c.array, c.bitmap = array, nil
+}
+
+// forEach calls `f` with the values of the container in ascending order, and returns false if `f` stops.
+func (c *roaringContainer) forEach(f func(low uint16) bool) bool {
+	if c.bitmap == nil {
+		for _, low := range c.array {
+			if !f(low) {
+				return false
+			}
+		}
+		return true
+	}
+	for i, w := range c.bitmap {
+		for w != 0 {
+			if !f(uint16(i*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+// clone returns a copy of the container.
+func (c *roaringContainer) clone() *roaringContainer {
+	return &roaringContainer{
+		array:  slices.Clone(c.array),
+		bitmap: slices.Clone(c.bitmap),
+		n:      c.n,
+	}
+}
+
+// unionContainers returns a new container with the values in `a` or `b`.
+func unionContainers(a, b *roaringContainer) *roaringContainer {
+	if a.bitmap == nil && b.bitmap == nil {
+		c := &roaringContainer{array: make([]uint16, 0, a.n+b.n)}
+		i, j := 0, 0
+		for i < len(a.array) && j < len(b.array) {
+			switch {
+			case a.array[i] < b.array[j]:
+				c.array = append(c.array, a.array[i])
+				i++
+			case a.array[i] > b.array[j]:
+				c.array = append(c.array, b.array[j])
+				j++
+			default:
+				c.array = append(c.array, a.array[i])
+				i++
+				j++
+			}
+		}
+		c.array = append(c.array, a.array[i:]...)
+		c.array = append(c.array, b.array[j:]...)
+		c.n = len(c.array)
+		if c.n > roaringArrayMaxSize {
+			c.toBitmap()
+		}
+		return c
+	}
+	if a.bitmap == nil {
+		a, b = b, a
+	}
+	c := a.clone()
+	if b.bitmap == nil {
+		for _, low := range b.array {
+			c.add(low)
+		}
+		return c
+	}
+	c.n = 0
+	for i, w := range b.bitmap {
+		c.bitmap[i] |= w
+		c.n += bits.OnesCount64(c.bitmap[i])
+	}
+	return c
+}
+
+// intersectContainers returns a new container with the values in both `a` and `b`, or nil if there is none.
+func intersectContainers(a, b *roaringContainer) *roaringContainer {
+	c := &roaringContainer{}
+	switch {
+	case a.bitmap == nil && b.bitmap == nil:
+		i, j := 0, 0
+		for i < len(a.array) && j < len(b.array) {
+			switch {
+			case a.array[i] < b.array[j]:
+				i++
+			case a.array[i] > b.array[j]:
+				j++
+			default:
+				c.array = append(c.array, a.array[i])
+				i++
+				j++
+			}
+		}
+		c.n = len(c.array)
+	case a.bitmap == nil || b.bitmap == nil:
+		if a.bitmap != nil {
+			a, b = b, a
+		}
+		for _, low := range a.array {
+			if b.contains(low) {
+				c.array = append(c.array, low)
+			}
+		}
+		c.n = len(c.array)
+	default:
+		c.bitmap = make([]uint64, roaringBitmapWords)
+		for i := range c.bitmap {
+			c.bitmap[i] = a.bitmap[i] & b.bitmap[i]
+			c.n += bits.OnesCount64(c.bitmap[i])
+		}
+		if c.n <= roaringArrayMaxSize {
+			c.toArray()
+		}
+	}
+	if c.n == 0 {
+		return nil
+	}
+	return c
+}
+
+// splitRoaring splits `item` into the key of its container and the value in the container.
+func splitRoaring[T ~uint32 | ~uint64](item T) (key uint64, low uint16) {
+	return uint64(item) >> 16, uint16(item)
+}
+
+// doAddWithoutLock adds `item` to the set, and returns true if it was not in the set.
+func (set *RoaringSet[T]) doAddWithoutLock(item T) bool {
+	key, low := splitRoaring(item)
+	i, found := slices.BinarySearch(set.keys, key)
+	if !found {
+		set.keys = slices.Insert(set.keys, i, key)
+		set.containers = slices.Insert(set.containers, i, &roaringContainer{})
+	}
+	if set.containers[i].add(low) {
+		set.size++
+		return true
+	}
+	return false
+}
+
+// doRemoveWithoutLock removes `item` from the set, and returns true if it was in the set.
+func (set *RoaringSet[T]) doRemoveWithoutLock(item T) bool {
+	key, low := splitRoaring(item)
+	i, found := slices.BinarySearch(set.keys, key)
+	if !found || !set.containers[i].remove(low) {
+		return false
+	}
+	set.size--
+	if set.containers[i].n == 0 {
+		set.keys = slices.Delete(set.keys, i, i+1)
+		set.containers = slices.Delete(set.containers, i, i+1)
+	}
+	return true
+}
+
+// doContainsWithoutLock checks whether the set contains `item`.
+func (set *RoaringSet[T]) doContainsWithoutLock(item T) bool {
+	key, low := splitRoaring(item)
+	i, found := slices.BinarySearch(set.keys, key)
+	return found && set.containers[i].contains(low)
+}
+
+// Add adds `items` to the set, and returns true if any of them was not in the set.
+func (set *RoaringSet[T]) Add(items ...T) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	changed := false
+	for _, item := range items {
+		if set.doAddWithoutLock(item) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// AddAll adds all the elements in collection `items` to the set.
+// It returns true if the set changed as a result of the call.
+func (set *RoaringSet[T]) AddAll(items Collection[T]) bool {
+	return set.Add(items.Slice()...)
+}
+
+// Remove deletes `items` from the set, and returns true if any of them was in the set.
+func (set *RoaringSet[T]) Remove(items ...T) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	changed := false
+	for _, item := range items {
+		if set.doRemoveWithoutLock(item) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// RemoveAll removes all the elements contained in collection `items` from the set.
+// It returns true if the set changed as a result of the call.
+func (set *RoaringSet[T]) RemoveAll(items Collection[T]) bool {
+	return set.Remove(items.Slice()...)
+}
+
+// Contains checks whether the set contains `item`.
+func (set *RoaringSet[T]) Contains(item T) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.doContainsWithoutLock(item)
+}
+
+// ContainsAll returns true if the set contains all the elements in collection `items`.
+func (set *RoaringSet[T]) ContainsAll(items Collection[T]) bool {
+	values := items.Slice()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for _, v := range values {
+		if !set.doContainsWithoutLock(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Size returns the number of items of the set.
+func (set *RoaringSet[T]) Size() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.size
+}
+
+// IsEmpty returns true if the set contains no items.
+func (set *RoaringSet[T]) IsEmpty() bool {
+	return set.Size() == 0
+}
+
+// Clear deletes all items of the set.
+func (set *RoaringSet[T]) Clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.keys, set.containers, set.size = nil, nil, 0
+}
+
+// Clone returns a new set, which is a copy of current set.
+func (set *RoaringSet[T]) Clone() Collection[T] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	clone := NewRoaringSet[T](set.mu.IsSafe())
+	clone.keys = slices.Clone(set.keys)
+	clone.containers = make([]*roaringContainer, len(set.containers))
+	for i, c := range set.containers {
+		clone.containers[i] = c.clone()
+	}
+	clone.size = set.size
+	return clone
+}
+
+// DeepCopy implements interface for deep copy of current type.
+func (set *RoaringSet[T]) DeepCopy() Collection[T] {
+	if set == nil {
+		return nil
+	}
+	return set.Clone()
+}
+
+// Equals checks whether the set contains the same items as collection `another`.
+func (set *RoaringSet[T]) Equals(another Collection[T]) bool {
+	if set == another {
+		return true
+	}
+	return set.Size() == another.Size() && set.ContainsAll(another)
+}
+
+// ForEach iterates the items in ascending order readonly with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (set *RoaringSet[T]) ForEach(f func(v T) bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	for i, key := range set.keys {
+		if !set.containers[i].forEach(func(low uint16) bool {
+			return f(T(key<<16 | uint64(low)))
+		}) {
+			return
+		}
+	}
+}
+
+// Slice returns the items of the set in ascending order as slice.
+func (set *RoaringSet[T]) Slice() []T {
+	items := make([]T, 0, set.Size())
+	set.ForEach(func(v T) bool {
+		items = append(items, v)
+		return true
+	})
+	return items
+}
+
+// Union returns a new set with the items in `set` or `other`,
+// which merges the containers of the same keys instead of adding the items one by one.
+func (set *RoaringSet[T]) Union(other *RoaringSet[T]) (newSet *RoaringSet[T]) {
+	newSet = NewRoaringSet[T]()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if set != other {
+		other.mu.RLock()
+		defer other.mu.RUnlock()
+	}
+	i, j := 0, 0
+	for i < len(set.keys) || j < len(other.keys) {
+		var (
+			key uint64
+			c   *roaringContainer
+		)
+		switch {
+		case j == len(other.keys) || i < len(set.keys) && set.keys[i] < other.keys[j]:
+			key, c = set.keys[i], set.containers[i].clone()
+			i++
+		case i == len(set.keys) || set.keys[i] > other.keys[j]:
+			key, c = other.keys[j], other.containers[j].clone()
+			j++
+		default:
+			key, c = set.keys[i], unionContainers(set.containers[i], other.containers[j])
+			i++
+			j++
+		}
+		newSet.keys = append(newSet.keys, key)
+		newSet.containers = append(newSet.containers, c)
+		newSet.size += c.n
+	}
+	return
+}
+
+// Intersect returns a new set with the items in both `set` and `other`,
+// which intersects only the containers of the same keys.
+func (set *RoaringSet[T]) Intersect(other *RoaringSet[T]) (newSet *RoaringSet[T]) {
+	newSet = NewRoaringSet[T]()
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	if set != other {
+		other.mu.RLock()
+		defer other.mu.RUnlock()
+	}
+	i, j := 0, 0
+	for i < len(set.keys) && j < len(other.keys) {
+		switch {
+		case set.keys[i] < other.keys[j]:
+			i++
+		case set.keys[i] > other.keys[j]:
+			j++
+		default:
+			if c := intersectContainers(set.containers[i], other.containers[j]); c != nil {
+				newSet.keys = append(newSet.keys, set.keys[i])
+				newSet.containers = append(newSet.containers, c)
+				newSet.size += c.n
+			}
+			i++
+			j++
+		}
+	}
+	return
+}
+
+// Join joins items in ascending order with a string `glue`.
+func (set *RoaringSet[T]) Join(glue string) string {
+	buffer := bytes.NewBuffer(nil)
+	first := true
+	set.ForEach(func(v T) bool {
+		if !first {
+			buffer.WriteString(glue)
+		}
+		first = false
+		buffer.WriteString(strconv.FormatUint(uint64(v), 10))
+		return true
+	})
+	return buffer.String()
+}
+
+// String returns items as a string, which implements like json.Marshal does.
+func (set *RoaringSet[T]) String() string {
+	if set == nil {
+		return ""
+	}
+	return "[" + set.Join(",") + "]"
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (set *RoaringSet[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, set)
+}
+
+// formatEntries implements the interface formattable.
+func (set *RoaringSet[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = set.Size()
+	return collectFormatValues(set.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the items using `seed`.
+func (set *RoaringSet[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnordered(seed, set.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (set *RoaringSet[T]) SetMarshalOptions(options JSONOptions) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal,
+// which marshals the items in ascending order as an array.
+func (set *RoaringSet[T]) MarshalJSON() ([]byte, error) {
+	set.mu.RLock()
+	options := set.jsonOptions
+	set.mu.RUnlock()
+	return marshalJSONSlice(options, set.Slice())
+}
+
+// UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
+func (set *RoaringSet[T]) UnmarshalJSON(b []byte) error {
+	var array []T
+	if err := json.UnmarshalUseNumber(b, &array); err != nil {
+		return err
+	}
+	set.Add(array...)
+	return nil
+}
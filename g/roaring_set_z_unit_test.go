@@ -0,0 +1,105 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestRoaringSet_Basic(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var s g.RoaringSet[uint64]
+		t.Assert(s.IsEmpty(), true)
+		t.Assert(s.Add(1<<40, 5, 70000, 5), true)
+		t.Assert(s.Add(5), false)
+		t.Assert(s.Size(), 3)
+		t.Assert(s.Contains(70000), true)
+		t.Assert(s.Contains(70001), false)
+		t.Assert(s.Contains(1<<40), true)
+		t.Assert(s.Slice(), []uint64{5, 70000, 1 << 40})
+		t.Assert(s.Remove(70000, 6), true)
+		t.Assert(s.Remove(70000), false)
+		t.Assert(s.Slice(), []uint64{5, 1 << 40})
+		s.Clear()
+		t.Assert(s.IsEmpty(), true)
+	})
+}
+
+func TestRoaringSet_Dense(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		// The container is converted to bitmap beyond 4096 items, and back to array when it gets sparse.
+		s := g.NewRoaringSet[uint32]()
+		for i := uint32(0); i < 10000; i++ {
+			s.Add(i * 3)
+		}
+		t.Assert(s.Size(), 10000)
+		t.Assert(s.Contains(29997), true)
+		t.Assert(s.Contains(29998), false)
+		for i := uint32(0); i < 9000; i++ {
+			s.Remove(i * 3)
+		}
+		t.Assert(s.Size(), 1000)
+		t.Assert(s.Contains(27000), true)
+		t.Assert(s.Contains(26997), false)
+		slice := s.Slice()
+		t.Assert(slices.IsSorted(slice), true)
+		t.Assert(slice[0], 27000)
+	})
+}
+
+func TestRoaringSet_UnionIntersect(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		r := rand.New(rand.NewSource(1))
+		var (
+			s1, s2 = g.NewRoaringSet[uint64](), g.NewRoaringSet[uint64]()
+			h1, h2 = g.NewHashSet[uint64](), g.NewHashSet[uint64]()
+		)
+		for i := 0; i < 20000; i++ {
+			// Mixes dense containers in the low range and sparse ones in the high range.
+			v1, v2 := uint64(r.Intn(1<<17)), uint64(r.Intn(1<<17))
+			if i%2 == 0 {
+				v1, v2 = uint64(r.Int63n(1<<40)), uint64(r.Int63n(1<<40))
+			}
+			s1.Add(v1)
+			h1.Add(v1)
+			s2.Add(v2)
+			h2.Add(v2)
+		}
+		t.Assert(s1.Equals(h1), true)
+		t.Assert(s1.Union(s2).Equals(h1.Union(h2)), true)
+		t.Assert(s1.Intersect(s2).Equals(h1.Intersect(h2)), true)
+		t.Assert(s1.Union(s2).Size(), h1.Union(h2).Size())
+		t.Assert(s1.Intersect(s2).Size(), h1.Intersect(h2).Size())
+		t.Assert(s1.Union(s1).Equals(s1), true)
+		t.Assert(s1.Intersect(s1).Equals(s1), true)
+		t.Assert(s1.Intersect(g.NewRoaringSet[uint64]()).IsEmpty(), true)
+		t.Assert(s1.Clone().Equals(s1), true)
+	})
+}
+
+func TestRoaringSet_String(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewRoaringSetFrom([]uint32{100000, 2})
+		t.Assert(s.String(), "[2,100000]")
+		t.Assert(fmt.Sprint(s), s.String())
+		b, err := json.Marshal(s)
+		t.AssertNil(err)
+		t.Assert(string(b), `[2,100000]`)
+		var s2 g.RoaringSet[uint32]
+		t.AssertNil(json.Unmarshal(b, &s2))
+		t.Assert(s2.Equals(s), true)
+	})
+}
@@ -0,0 +1,55 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// forEachCombination calls `f` with every combination of `k` items of `items` in lexicographic order
+// of their indices, and returns false if `f` stops the iteration.
+// The slice passed to `f` is reused between the calls.
+func forEachCombination[T any](items []T, k int, f func(combination []T) bool) bool {
+	n := len(items)
+	if k < 0 || k > n {
+		return true
+	}
+	var (
+		indices     = make([]int, k)
+		combination = make([]T, k)
+	)
+	for i := range indices {
+		indices[i] = i
+	}
+	for {
+		for i, index := range indices {
+			combination[i] = items[index]
+		}
+		if !f(combination) {
+			return false
+		}
+		// Finds the rightmost index which can be increased, and resets the ones after it.
+		i := k - 1
+		for i >= 0 && indices[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return true
+		}
+		indices[i]++
+		for j := i + 1; j < k; j++ {
+			indices[j] = indices[j-1] + 1
+		}
+	}
+}
+
+// forEachSubset calls `f` with every subset of `items` created by `newSet`, in ascending order of their sizes.
+func forEachSubset[T comparable](items []T, newSet func(items []T) Set[T], f func(subset Set[T]) bool) {
+	for k := 0; k <= len(items); k++ {
+		if !forEachCombination(items, k, func(combination []T) bool {
+			return f(newSet(combination))
+		}) {
+			return
+		}
+	}
+}
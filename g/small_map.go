@@ -0,0 +1,563 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/deepcopy"
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/empty"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+const (
+	// defaultSmallMapThreshold is the default max number of entries stored in the inline slice of SmallMap.
+	defaultSmallMapThreshold = 8
+)
+
+// SmallMap implements the Map interface, which is optimized for maps that usually contain only a few entries.
+// It stores up to `threshold` entries in an inline slice which is searched linearly, and transparently
+// switches to a golang map when it grows beyond the threshold. Once switched, it keeps using the map
+// until it is cleared or replaced.
+//
+// For maps with only a few entries, the inline slice costs much less memory than a golang map,
+// and iterating it is faster. Entries in the inline slice are iterated in insertion order,
+// while it makes no guarantees as to the iteration order after switching to the map.
+type SmallMap[K comparable, V any] struct {
+	mu          rwmutex.RWMutex
+	threshold   int                   // Max number of entries stored in the inline slice.
+	entries     []smallMapEntry[K, V] // Inline storage, used when data is nil.
+	data        map[K]V               // Map storage, used after the map is upgraded.
+	jsonOptions *JSONOptions          // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// smallMapEntry is a key-value pair stored in the inline slice of SmallMap.
+type smallMapEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewSmallMap creates and returns an empty small map using the default threshold.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewSmallMap[K comparable, V any](safe ...bool) *SmallMap[K, V] {
+	return NewSmallMapSize[K, V](defaultSmallMapThreshold, safe...)
+}
+
+// NewSmallMapSize creates and returns an empty small map which stores up to `threshold` entries
+// in the inline slice before switching to golang map. The default threshold is used if `threshold` <= 0.
+// The parameter `safe` is used to specify whether using map in concurrent-safety,
+// which is false in default.
+func NewSmallMapSize[K comparable, V any](threshold int, safe ...bool) *SmallMap[K, V] {
+	if threshold <= 0 {
+		threshold = defaultSmallMapThreshold
+	}
+	return &SmallMap[K, V]{
+		mu:        rwmutex.Create(safe...),
+		threshold: threshold,
+	}
+}
+
+// NewSmallMapFrom creates and returns a small map from given map `data` using the default threshold.
+// Different from NewHashMapFrom, the entries of `data` are copied into the small map.
+func NewSmallMapFrom[K comparable, V any](data map[K]V, safe ...bool) *SmallMap[K, V] {
+	m := NewSmallMap[K, V](safe...)
+	m.doReplaceWithoutLock(data)
+	return m
+}
+
+// getThreshold returns the threshold of the map, which is the default threshold for zero value of SmallMap.
+func (m *SmallMap[K, V]) getThreshold() int {
+	if m.threshold <= 0 {
+		return defaultSmallMapThreshold
+	}
+	return m.threshold
+}
+
+// indexOf returns the index of `key` in the inline slice, or -1 if not found.
+func (m *SmallMap[K, V]) indexOf(key K) int {
+	for i := range m.entries {
+		if m.entries[i].key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// doSearchWithoutLock searches the map with given `key` without lock.
+func (m *SmallMap[K, V]) doSearchWithoutLock(key K) (value V, found bool) {
+	if m.data != nil {
+		value, found = m.data[key]
+		return
+	}
+	if index := m.indexOf(key); index != -1 {
+		return m.entries[index].value, true
+	}
+	return
+}
+
+// doPutWithoutLock sets key-value to the map without lock, and switches the map to golang map storage
+// if the inline slice is full.
+func (m *SmallMap[K, V]) doPutWithoutLock(key K, value V) {
+	if m.data != nil {
+		m.data[key] = value
+		return
+	}
+	if index := m.indexOf(key); index != -1 {
+		m.entries[index].value = value
+		return
+	}
+	if len(m.entries) < m.getThreshold() {
+		m.entries = append(m.entries, smallMapEntry[K, V]{key: key, value: value})
+		return
+	}
+	// Upgrade to map storage.
+	m.data = make(map[K]V, len(m.entries)*2+1)
+	for _, e := range m.entries {
+		m.data[e.key] = e.value
+	}
+	m.data[key] = value
+	m.entries = nil
+}
+
+// doRemoveWithoutLock deletes value from map by given `key` without lock.
+func (m *SmallMap[K, V]) doRemoveWithoutLock(key K) (value V, removed bool) {
+	if m.data != nil {
+		if value, removed = m.data[key]; removed {
+			delete(m.data, key)
+		}
+		return
+	}
+	index := m.indexOf(key)
+	if index == -1 {
+		return
+	}
+	value = m.entries[index].value
+	// Shifts the remaining entries to keep the insertion order.
+	copy(m.entries[index:], m.entries[index+1:])
+	m.entries[len(m.entries)-1] = smallMapEntry[K, V]{}
+	m.entries = m.entries[:len(m.entries)-1]
+	return value, true
+}
+
+// doSizeWithoutLock returns the size of the map without lock.
+func (m *SmallMap[K, V]) doSizeWithoutLock() int {
+	if m.data != nil {
+		return len(m.data)
+	}
+	return len(m.entries)
+}
+
+// doForEachWithoutLock iterates the map with given callback function `f` without lock.
+func (m *SmallMap[K, V]) doForEachWithoutLock(f func(key K, value V) bool) {
+	if m.data != nil {
+		for k, v := range m.data {
+			if !f(k, v) {
+				return
+			}
+		}
+		return
+	}
+	for _, e := range m.entries {
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// doReplaceWithoutLock replaces the data of the map with copy of given `data` without lock.
+func (m *SmallMap[K, V]) doReplaceWithoutLock(data map[K]V) {
+	m.entries = nil
+	m.data = nil
+	if len(data) > m.getThreshold() {
+		m.data = make(map[K]V, len(data))
+		for k, v := range data {
+			m.data[k] = v
+		}
+		return
+	}
+	for k, v := range data {
+		m.entries = append(m.entries, smallMapEntry[K, V]{key: k, value: v})
+	}
+}
+
+// IsUpgraded returns true if the map is switched to golang map storage.
+func (m *SmallMap[K, V]) IsUpgraded() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.data != nil
+}
+
+// ForEach iterates the map readonly with custom callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *SmallMap[K, V]) ForEach(f func(key K, value V) bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	m.doForEachWithoutLock(f)
+}
+
+// Entries returns all key-value pairs of the map as a slice of entries, in the same order as ForEach.
+func (m *SmallMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(m.ForEach, m.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (m *SmallMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	m.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *SmallMap[K, V]) IterKeys(f func(key K) bool) {
+	m.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (m *SmallMap[K, V]) IterValues(f func(value V) bool) {
+	m.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
+// Clone returns a new small map with copy of current map data.
+func (m *SmallMap[K, V]) Clone(safe ...bool) Map[K, V] {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := NewSmallMapSize[K, V](m.getThreshold(), safe...)
+	if m.data != nil {
+		newMap.data = make(map[K]V, len(m.data))
+		for k, v := range m.data {
+			newMap.data[k] = v
+		}
+	} else if len(m.entries) > 0 {
+		newMap.entries = make([]smallMapEntry[K, V], len(m.entries))
+		copy(newMap.entries, m.entries)
+	}
+	return newMap
+}
+
+// Map returns a shallow copy of the underlying data of the map.
+func (m *SmallMap[K, V]) Map() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[K]V, m.doSizeWithoutLock())
+	m.doForEachWithoutLock(func(k K, v V) bool {
+		data[k] = v
+		return true
+	})
+	return data
+}
+
+// MapStrAny returns a copy of the underlying data of the map as map[string]any.
+func (m *SmallMap[K, V]) MapStrAny() map[string]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := make(map[string]V, m.doSizeWithoutLock())
+	m.doForEachWithoutLock(func(k K, v V) bool {
+		data[gconv.String(k)] = v
+		return true
+	})
+	return data
+}
+
+// Put sets key-value to the map.
+func (m *SmallMap[K, V]) Put(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.doPutWithoutLock(key, value)
+}
+
+// Puts batch sets key-values to the map.
+func (m *SmallMap[K, V]) Puts(data map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range data {
+		m.doPutWithoutLock(k, v)
+	}
+}
+
+// PutAll batch sets key-values to the map, which is alias of Puts.
+func (m *SmallMap[K, V]) PutAll(data map[K]V) {
+	m.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the map
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the map is locked.
+func (m *SmallMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		m.doPutWithoutLock(entry.Key, entry.Value)
+	}
+}
+
+// Search searches the map with given `key`.
+// Second return parameter `found` is true if key was found, otherwise false.
+func (m *SmallMap[K, V]) Search(key K) (value V, found bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.doSearchWithoutLock(key)
+}
+
+// Get returns the value by given `key`, or empty value of type V if the key is not found in the map.
+func (m *SmallMap[K, V]) Get(key K) (value V) {
+	value, _ = m.Search(key)
+	return
+}
+
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (m *SmallMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := m.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (m *SmallMap[K, V]) MustGet(key K) V {
+	value, found := m.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
+// GetOrPut returns the value by key,
+// or sets value with given `value` if it does not exist and then returns this value.
+func (m *SmallMap[K, V]) GetOrPut(key K, value V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.doSearchWithoutLock(key); ok {
+		return v
+	}
+	if !empty.IsNil(value) {
+		m.doPutWithoutLock(key, value)
+	}
+	return value
+}
+
+// GetOrPutFunc returns the value by key,
+// or sets value with returned value of callback function `f` if it does not exist
+// and then returns this value.
+//
+// Note that the function `f` is executed within writing mutex lock for concurrent safety purpose.
+func (m *SmallMap[K, V]) GetOrPutFunc(key K, f func() V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.doSearchWithoutLock(key); ok {
+		return v
+	}
+	value := f()
+	if !empty.IsNil(value) {
+		m.doPutWithoutLock(key, value)
+	}
+	return value
+}
+
+// PutIfAbsent sets `value` to the map if the `key` does not exist, and then returns true.
+// It returns false if `key` exists, and `value` would be ignored.
+func (m *SmallMap[K, V]) PutIfAbsent(key K, value V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.doSearchWithoutLock(key); ok {
+		return false
+	}
+	if !empty.IsNil(value) {
+		m.doPutWithoutLock(key, value)
+	}
+	return true
+}
+
+// PutIfAbsentFunc sets value with return value of callback function `f`, and then returns true.
+// It returns false if `key` exists, and `value` would be ignored.
+//
+// Note that the function `f` is executed within writing mutex lock for concurrent safety purpose.
+func (m *SmallMap[K, V]) PutIfAbsentFunc(key K, f func() V) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.doSearchWithoutLock(key); ok {
+		return false
+	}
+	value := f()
+	if !empty.IsNil(value) {
+		m.doPutWithoutLock(key, value)
+	}
+	return true
+}
+
+// Remove deletes value from map by given `key`, and return this deleted value.
+func (m *SmallMap[K, V]) Remove(key K) (value V, removed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.doRemoveWithoutLock(key)
+}
+
+// Removes batch deletes values of the map by keys.
+func (m *SmallMap[K, V]) Removes(keys []K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		m.doRemoveWithoutLock(key)
+	}
+}
+
+// Keys returns all keys of the map as a slice.
+func (m *SmallMap[K, V]) Keys() []K {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	keys := make([]K, 0, m.doSizeWithoutLock())
+	m.doForEachWithoutLock(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Values returns all values of the map as a slice.
+func (m *SmallMap[K, V]) Values() []V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	values := make([]V, 0, m.doSizeWithoutLock())
+	m.doForEachWithoutLock(func(_ K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// ContainsKey checks whether a key exists.
+// It returns true if the `key` exists, or else false.
+func (m *SmallMap[K, V]) ContainsKey(key K) bool {
+	_, found := m.Search(key)
+	return found
+}
+
+// Size returns the size of the map.
+func (m *SmallMap[K, V]) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.doSizeWithoutLock()
+}
+
+// IsEmpty checks whether the map is empty.
+// It returns true if map is empty, or else false.
+func (m *SmallMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// Clear deletes all data of the map, and the map is switched back to inline slice storage.
+func (m *SmallMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = nil
+	m.data = nil
+}
+
+// Replace the data of the map with copy of given `data`.
+func (m *SmallMap[K, V]) Replace(data map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.doReplaceWithoutLock(data)
+}
+
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// all done under one lock.
+func (m *SmallMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data != nil {
+		for k, v := range m.data {
+			m.data[k] = f(k, v)
+		}
+		return
+	}
+	for i := range m.entries {
+		e := &m.entries[i]
+		e.value = f(e.key, e.value)
+	}
+}
+
+// String returns the map as a string.
+func (m *SmallMap[K, V]) String() string {
+	if m == nil {
+		return ""
+	}
+	b, _ := m.MarshalJSON()
+	return string(b)
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (m *SmallMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, m)
+}
+
+// formatEntries implements the interface formattable.
+func (m *SmallMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = m.Size()
+	return collectFormatPairs(m.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (m *SmallMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, m.ForEach)
+}
+
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (m *SmallMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (m *SmallMap[K, V]) MarshalJSON() ([]byte, error) {
+	m.mu.RLock()
+	options := m.jsonOptions
+	m.mu.RUnlock()
+	return marshalJSONMap(options, gconv.Map(m.Map()))
+}
+
+// UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
+func (m *SmallMap[K, V]) UnmarshalJSON(b []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var data map[K]V
+	if err := json.UnmarshalUseNumber(b, &data); err != nil {
+		return err
+	}
+	for k, v := range data {
+		m.doPutWithoutLock(k, v)
+	}
+	return nil
+}
+
+// DeepCopy implements interface for deep copy of current type.
+func (m *SmallMap[K, V]) DeepCopy() interface{} {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	newMap := NewSmallMapSize[K, V](m.getThreshold(), m.mu.IsSafe())
+	m.doForEachWithoutLock(func(k K, v V) bool {
+		newMap.doPutWithoutLock(k, deepcopy.Copy(v).(V))
+		return true
+	})
+	return newMap
+}
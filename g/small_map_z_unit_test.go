@@ -0,0 +1,132 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g_test
+
+import (
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func Test_SmallMap_Var(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var m g.SmallMap[string, int]
+		m.Put("a", 1)
+		t.Assert(m.Get("a"), 1)
+		t.Assert(m.Size(), 1)
+		t.Assert(m.IsEmpty(), false)
+		t.Assert(m.GetOrPut("b", 2), 2)
+		t.Assert(m.PutIfAbsent("b", 3), false)
+		t.Assert(m.Get("b"), 2)
+		v, removed := m.Remove("a")
+		t.Assert(v, 1)
+		t.Assert(removed, true)
+		t.Assert(m.ContainsKey("a"), false)
+		m.Clear()
+		t.Assert(m.Size(), 0)
+	})
+}
+
+func Test_SmallMap_Upgrade(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewSmallMapSize[int, int](3)
+		m.Puts(map[int]int{1: 1, 2: 2, 3: 3})
+		t.Assert(m.IsUpgraded(), false)
+		m.Put(3, 30)
+		t.Assert(m.IsUpgraded(), false)
+		t.Assert(m.Get(3), 30)
+		m.Put(4, 4)
+		t.Assert(m.IsUpgraded(), true)
+		t.Assert(m.Size(), 4)
+		t.Assert(m.Map(), map[int]int{1: 1, 2: 2, 3: 30, 4: 4})
+		m.Removes([]int{1, 2})
+		t.Assert(m.Size(), 2)
+		m.Replace(map[int]int{5: 5})
+		t.Assert(m.IsUpgraded(), false)
+		t.Assert(m.Map(), map[int]int{5: 5})
+		m.Replace(map[int]int{1: 1, 2: 2, 3: 3, 4: 4})
+		t.Assert(m.IsUpgraded(), true)
+		m.Clear()
+		t.Assert(m.IsUpgraded(), false)
+	})
+}
+
+func Test_SmallMap_Order(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewSmallMap[string, int](true)
+		m.Put("c", 3)
+		m.Put("a", 1)
+		m.Put("b", 2)
+		m.Remove("a")
+		m.Put("d", 4)
+		t.Assert(m.Keys(), []string{"c", "b", "d"})
+		t.Assert(m.Values(), []int{3, 2, 4})
+		keys := make([]string, 0)
+		m.ForEach(func(k string, v int) bool {
+			keys = append(keys, k)
+			return len(keys) < 2
+		})
+		t.Assert(keys, []string{"c", "b"})
+	})
+}
+
+func Test_SmallMap_Func(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewSmallMap[string, int]()
+		t.Assert(m.GetOrPutFunc("a", func() int { return 1 }), 1)
+		t.Assert(m.GetOrPutFunc("a", func() int { return 2 }), 1)
+		t.Assert(m.PutIfAbsentFunc("b", func() int { return 2 }), true)
+		t.Assert(m.PutIfAbsentFunc("b", func() int { return 3 }), false)
+		t.Assert(m.Get("b"), 2)
+	})
+}
+
+func Test_SmallMap_Clone(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m1 := g.NewSmallMapFrom(map[string]int{"a": 1, "b": 2})
+		m2 := m1.Clone()
+		m2.Put("c", 3)
+		t.Assert(m1.Size(), 2)
+		t.Assert(m2.Size(), 3)
+		m3 := m1.DeepCopy().(*g.SmallMap[string, int])
+		t.Assert(m3.Map(), m1.Map())
+	})
+}
+
+func Test_SmallMap_Json(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m1 := g.NewSmallMapFrom(map[string]int{"a": 1, "b": 2})
+		b, err := json.Marshal(m1)
+		t.AssertNil(err)
+		var m2 g.SmallMap[string, int]
+		err = json.UnmarshalUseNumber(b, &m2)
+		t.AssertNil(err)
+		t.Assert(m2.Map(), map[string]int{"a": 1, "b": 2})
+		t.Assert(m1.MapStrAny(), map[string]int{"a": 1, "b": 2})
+	})
+}
+
+func Test_SmallMap_ReplaceAll(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := g.NewSmallMapSize[int, int](2)
+		m.Put(1, 1)
+		m.Put(2, 2)
+		m.ReplaceAll(func(key int, value int) int {
+			return key + value
+		})
+		t.Assert(m.Map(), map[int]int{1: 2, 2: 4})
+
+		// Upgraded to the map storage.
+		m.Put(3, 3)
+		m.ReplaceAll(func(key int, value int) int {
+			return value * 2
+		})
+		t.Assert(m.Map(), map[int]int{1: 4, 2: 8, 3: 6})
+	})
+}
@@ -0,0 +1,502 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+
+	"github.com/wesleywu/gcontainer/internal/deepcopy"
+	"github.com/wesleywu/gcontainer/internal/json"
+	"github.com/wesleywu/gcontainer/internal/rwmutex"
+	"github.com/wesleywu/gcontainer/utils/empty"
+	"github.com/wesleywu/gcontainer/utils/gconv"
+	"github.com/wesleywu/gcontainer/utils/gstr"
+)
+
+const (
+	// defaultSmallSetThreshold is the default max number of elements stored in the inline slice of SmallSet.
+	defaultSmallSetThreshold = 8
+)
+
+// SmallSet implements the Set interface, which is optimized for sets that usually contain only a few elements.
+// It stores up to `threshold` elements in an inline slice, and transparently upgrades to a golang map
+// when it grows beyond the threshold. Once upgraded, it keeps using the map until it is cleared.
+//
+// Looking up an element in the inline slice is a linear scan, which is faster than hashing
+// and costs much less memory than a map when there are only a few elements.
+// It makes no guarantees as to the iteration order of the set.
+type SmallSet[T comparable] struct {
+	mu          rwmutex.RWMutex
+	threshold   int            // Max number of elements stored in the inline slice.
+	slice       []T            // Inline storage, used when data is nil.
+	data        map[T]struct{} // Map storage, used after the set is upgraded.
+	jsonOptions *JSONOptions   // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
+}
+
+// NewSmallSet creates and returns a new small set using the default threshold.
+// The parameter `safe` is used to specify whether using set in concurrent-safety,
+// which is false in default.
+func NewSmallSet[T comparable](safe ...bool) *SmallSet[T] {
+	return NewSmallSetSize[T](defaultSmallSetThreshold, safe...)
+}
+
+// NewSmallSetSize creates and returns a new small set which stores up to `threshold` elements
+// in the inline slice before upgrading to map. The default threshold is used if `threshold` <= 0.
+// The parameter `safe` is used to specify whether using set in concurrent-safety,
+// which is false in default.
+func NewSmallSetSize[T comparable](threshold int, safe ...bool) *SmallSet[T] {
+	if threshold <= 0 {
+		threshold = defaultSmallSetThreshold
+	}
+	return &SmallSet[T]{
+		mu:        rwmutex.Create(safe...),
+		threshold: threshold,
+	}
+}
+
+// NewSmallSetFrom returns a new small set from `items` using the default threshold.
+func NewSmallSetFrom[T comparable](items []T, safe ...bool) *SmallSet[T] {
+	set := NewSmallSet[T](safe...)
+	for _, item := range items {
+		set.doAddWithoutLock(item)
+	}
+	return set
+}
+
+// getThreshold returns the threshold of the set, which is the default threshold for zero value of SmallSet.
+func (set *SmallSet[T]) getThreshold() int {
+	if set.threshold <= 0 {
+		return defaultSmallSetThreshold
+	}
+	return set.threshold
+}
+
+// indexOf returns the index of `item` in the inline slice, or -1 if not found.
+func (set *SmallSet[T]) indexOf(item T) int {
+	for i, v := range set.slice {
+		if v == item {
+			return i
+		}
+	}
+	return -1
+}
+
+// doContainsWithoutLock checks whether the set contains `item` without lock.
+func (set *SmallSet[T]) doContainsWithoutLock(item T) bool {
+	if set.data != nil {
+		_, ok := set.data[item]
+		return ok
+	}
+	return set.indexOf(item) != -1
+}
+
+// doAddWithoutLock adds `item` to the set without lock, and upgrades the set to map storage
+// if the inline slice is full. It returns true if the item is added.
+func (set *SmallSet[T]) doAddWithoutLock(item T) bool {
+	if empty.IsNil(item) {
+		return false
+	}
+	if set.data != nil {
+		if _, ok := set.data[item]; ok {
+			return false
+		}
+		set.data[item] = struct{}{}
+		return true
+	}
+	if set.indexOf(item) != -1 {
+		return false
+	}
+	if len(set.slice) < set.getThreshold() {
+		set.slice = append(set.slice, item)
+		return true
+	}
+	// Upgrade to map storage.
+	set.data = make(map[T]struct{}, len(set.slice)*2+1)
+	for _, v := range set.slice {
+		set.data[v] = struct{}{}
+	}
+	set.data[item] = struct{}{}
+	set.slice = nil
+	return true
+}
+
+// doRemoveWithoutLock removes `item` from the set without lock.
+// It returns true if the item is removed.
+func (set *SmallSet[T]) doRemoveWithoutLock(item T) bool {
+	if set.data != nil {
+		if _, ok := set.data[item]; !ok {
+			return false
+		}
+		delete(set.data, item)
+		return true
+	}
+	index := set.indexOf(item)
+	if index == -1 {
+		return false
+	}
+	last := len(set.slice) - 1
+	set.slice[index] = set.slice[last]
+	var zero T
+	set.slice[last] = zero
+	set.slice = set.slice[:last]
+	return true
+}
+
+// doSizeWithoutLock returns the size of the set without lock.
+func (set *SmallSet[T]) doSizeWithoutLock() int {
+	if set.data != nil {
+		return len(set.data)
+	}
+	return len(set.slice)
+}
+
+// doForEachWithoutLock iterates the set with given callback function `f` without lock.
+func (set *SmallSet[T]) doForEachWithoutLock(f func(v T) bool) {
+	if set.data != nil {
+		for k := range set.data {
+			if !f(k) {
+				return
+			}
+		}
+		return
+	}
+	for _, v := range set.slice {
+		if !f(v) {
+			return
+		}
+	}
+}
+
+// IsUpgraded returns true if the set is upgraded to map storage.
+func (set *SmallSet[T]) IsUpgraded() bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.data != nil
+}
+
+// ForEach iterates the set readonly with given callback function `f`,
+// if `f` returns true then continue iterating; or false to stop.
+func (set *SmallSet[T]) ForEach(f func(v T) bool) {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	set.doForEachWithoutLock(f)
+}
+
+// Add adds one or multiple items to the set.
+func (set *SmallSet[T]) Add(items ...T) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	var setChanged = false
+	for _, item := range items {
+		if set.doAddWithoutLock(item) {
+			setChanged = true
+		}
+	}
+	return setChanged
+}
+
+// AddAll adds all the elements in the specified collection to this set.
+func (set *SmallSet[T]) AddAll(items Collection[T]) bool {
+	return set.Add(items.Slice()...)
+}
+
+// Contains checks whether the set contains `item`.
+func (set *SmallSet[T]) Contains(item T) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.doContainsWithoutLock(item)
+}
+
+// ContainsAll returns true if this collection contains all the elements in the specified collection.
+func (set *SmallSet[T]) ContainsAll(items Collection[T]) bool {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	allFound := true
+	items.ForEach(func(v T) bool {
+		if !set.doContainsWithoutLock(v) {
+			allFound = false
+			return false
+		}
+		return true
+	})
+	return allFound
+}
+
+// IsEmpty returns true if this collection contains no elements.
+func (set *SmallSet[T]) IsEmpty() bool {
+	return set.Size() == 0
+}
+
+// Remove deletes `items` from set.
+func (set *SmallSet[T]) Remove(items ...T) bool {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	dataChanged := false
+	for _, item := range items {
+		if set.doRemoveWithoutLock(item) {
+			dataChanged = true
+		}
+	}
+	return dataChanged
+}
+
+// RemoveAll removes all of this collection's elements that are also contained in the specified collection
+func (set *SmallSet[T]) RemoveAll(items Collection[T]) bool {
+	return set.Remove(items.Slice()...)
+}
+
+// RemoveIf removes the items for which `predicate` returns true in a single pass with the set locked,
+// and returns the number of removed items.
+func (set *SmallSet[T]) RemoveIf(predicate func(item T) bool) (removed int) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	if set.data != nil {
+		for k := range set.data {
+			if predicate(k) {
+				delete(set.data, k)
+				removed++
+			}
+		}
+		return
+	}
+	kept := set.slice[:0]
+	for _, v := range set.slice {
+		if !predicate(v) {
+			kept = append(kept, v)
+		}
+	}
+	removed = len(set.slice) - len(kept)
+	clear(set.slice[len(kept):])
+	set.slice = kept
+	return
+}
+
+// Size returns the size of the set.
+func (set *SmallSet[T]) Size() int {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	return set.doSizeWithoutLock()
+}
+
+// Clear deletes all items of the set, and the set is downgraded to inline slice storage.
+func (set *SmallSet[T]) Clear() {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.slice = nil
+	set.data = nil
+}
+
+// Clone returns a new set, which is a copy of current set.
+func (set *SmallSet[T]) Clone() Collection[T] {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	newSet := NewSmallSetSize[T](set.getThreshold(), set.mu.IsSafe())
+	if set.data != nil {
+		newSet.data = make(map[T]struct{}, len(set.data))
+		for k := range set.data {
+			newSet.data[k] = struct{}{}
+		}
+	} else if len(set.slice) > 0 {
+		newSet.slice = make([]T, len(set.slice), set.getThreshold())
+		copy(newSet.slice, set.slice)
+	}
+	return newSet
+}
+
+// Slice returns all items of the set as slice.
+func (set *SmallSet[T]) Slice() []T {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	ret := make([]T, 0, set.doSizeWithoutLock())
+	set.doForEachWithoutLock(func(v T) bool {
+		ret = append(ret, v)
+		return true
+	})
+	return ret
+}
+
+// Any returns true if `predicate` returns true for any item of the set, which stops at the first match.
+// It returns false for an empty set.
+func (set *SmallSet[T]) Any(predicate func(v T) bool) bool {
+	return anyMatch(set.ForEach, predicate)
+}
+
+// All returns true if `predicate` returns true for all items of the set, which stops at the first mismatch.
+// It returns true for an empty set.
+func (set *SmallSet[T]) All(predicate func(v T) bool) bool {
+	return !anyMatch(set.ForEach, func(v T) bool {
+		return !predicate(v)
+	})
+}
+
+// None returns true if `predicate` returns false for all items of the set, which stops at the first match.
+// It returns true for an empty set.
+func (set *SmallSet[T]) None(predicate func(v T) bool) bool {
+	return !anyMatch(set.ForEach, predicate)
+}
+
+// CountIf returns the number of items of the set for which `predicate` returns true.
+func (set *SmallSet[T]) CountIf(predicate func(v T) bool) int {
+	return countMatch(set.ForEach, predicate)
+}
+
+// Combinations calls `f` with every combination of `k` items of the set, until `f` returns false.
+// The slice passed to `f` is reused between the calls, so it must be copied to be retained.
+func (set *SmallSet[T]) Combinations(k int, f func(combination []T) bool) {
+	forEachCombination(set.Slice(), k, f)
+}
+
+// PowerSet calls `f` with every subset of the set as a new SmallSet, in ascending order of their sizes,
+// until `f` returns false.
+func (set *SmallSet[T]) PowerSet(f func(subset Set[T]) bool) {
+	forEachSubset(set.Slice(), func(items []T) Set[T] {
+		return NewSmallSetFrom(items)
+	}, f)
+}
+
+// Join joins items with a string `glue`.
+func (set *SmallSet[T]) Join(glue string) string {
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	var (
+		i      = 0
+		buffer = bytes.NewBuffer(nil)
+	)
+	set.doForEachWithoutLock(func(v T) bool {
+		if i > 0 {
+			buffer.WriteString(glue)
+		}
+		buffer.WriteString(gconv.String(v))
+		i++
+		return true
+	})
+	return buffer.String()
+}
+
+// String returns items as a string, which implements like json.Marshal does.
+func (set *SmallSet[T]) String() string {
+	if set == nil {
+		return ""
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	var (
+		s      string
+		i      = 0
+		buffer = bytes.NewBuffer(nil)
+	)
+	buffer.WriteByte('[')
+	set.doForEachWithoutLock(func(v T) bool {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		s = gconv.String(v)
+		if gstr.IsNumeric(s) {
+			buffer.WriteString(s)
+		} else {
+			buffer.WriteString(`"` + gstr.QuoteMeta(s, `"\`) + `"`)
+		}
+		i++
+		return true
+	})
+	buffer.WriteByte(']')
+	return buffer.String()
+}
+
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (set *SmallSet[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, set)
+}
+
+// formatEntries implements the interface formattable.
+func (set *SmallSet[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = set.Size()
+	return collectFormatValues(set.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is insensitive to the order of elements.
+func (set *SmallSet[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnordered(seed, set.ForEach)
+}
+
+// Equals checks whether the two sets equal.
+func (set *SmallSet[T]) Equals(another Collection[T]) bool {
+	if set == another {
+		return true
+	}
+	var (
+		ano *SmallSet[T]
+		ok  bool
+	)
+	if ano, ok = another.(*SmallSet[T]); !ok {
+		return false
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	ano.mu.RLock()
+	defer ano.mu.RUnlock()
+	if set.doSizeWithoutLock() != ano.doSizeWithoutLock() {
+		return false
+	}
+	equals := true
+	set.doForEachWithoutLock(func(v T) bool {
+		if !ano.doContainsWithoutLock(v) {
+			equals = false
+			return false
+		}
+		return true
+	})
+	return equals
+}
+
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (set *SmallSet[T]) SetMarshalOptions(options JSONOptions) {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	set.jsonOptions = newJSONOptions(options)
+}
+
+// MarshalJSON implements the interface MarshalJSON for json.Marshal.
+func (set *SmallSet[T]) MarshalJSON() ([]byte, error) {
+	set.mu.RLock()
+	options := set.jsonOptions
+	set.mu.RUnlock()
+	return marshalJSONSlice(options, set.Slice())
+}
+
+// UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
+func (set *SmallSet[T]) UnmarshalJSON(b []byte) error {
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	var array []T
+	if err := json.UnmarshalUseNumber(b, &array); err != nil {
+		return err
+	}
+	for _, v := range array {
+		set.doAddWithoutLock(v)
+	}
+	return nil
+}
+
+// DeepCopy implements interface for deep copy of current type.
+func (set *SmallSet[T]) DeepCopy() Collection[T] {
+	if set == nil {
+		return nil
+	}
+	set.mu.RLock()
+	defer set.mu.RUnlock()
+	newSet := NewSmallSetSize[T](set.getThreshold(), set.mu.IsSafe())
+	set.doForEachWithoutLock(func(v T) bool {
+		newSet.doAddWithoutLock(deepcopy.Copy(v).(T))
+		return true
+	})
+	return newSet
+}
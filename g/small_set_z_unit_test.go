@@ -0,0 +1,155 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go
+
+package g_test
+
+import (
+	"testing"
+
+	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/internal/json"
+)
+
+func TestSmallSet_Var(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var s g.SmallSet[int]
+		s.Add(1, 1, 2)
+		s.Add([]int{3, 4}...)
+		t.Assert(s.Size(), 4)
+		t.Assert(s.Contains(4), true)
+		t.Assert(s.Contains(5), false)
+		s.Remove(1)
+		t.Assert(s.Size(), 3)
+		s.Clear()
+		t.Assert(s.Size(), 0)
+	})
+}
+
+func TestSmallSet_Upgrade(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewSmallSetSize[int](3)
+		s.Add(1, 2, 3)
+		t.Assert(s.IsUpgraded(), false)
+		t.Assert(s.Size(), 3)
+		s.Add(3)
+		t.Assert(s.IsUpgraded(), false)
+		s.Add(4)
+		t.Assert(s.IsUpgraded(), true)
+		t.Assert(s.Size(), 4)
+		for _, v := range []int{1, 2, 3, 4} {
+			t.Assert(s.Contains(v), true)
+		}
+		s.Remove(1, 2)
+		t.Assert(s.IsUpgraded(), true)
+		t.Assert(s.Size(), 2)
+		s.Clear()
+		t.Assert(s.IsUpgraded(), false)
+		t.Assert(s.IsEmpty(), true)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewSmallSetFrom([]string{"a", "b", "c"}, true)
+		t.Assert(s.Remove("b"), true)
+		t.Assert(s.Remove("b"), false)
+		t.Assert(s.Size(), 2)
+		t.AssertIN("a", s.Slice())
+		t.AssertIN("c", s.Slice())
+	})
+}
+
+func TestSmallSet_Equals(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s1 := g.NewSmallSetSize[int](2)
+		s1.Add(1, 2, 3)
+		s2 := g.NewSmallSet[int]()
+		s2.Add(3, 2, 1)
+		t.Assert(s1.IsUpgraded(), true)
+		t.Assert(s2.IsUpgraded(), false)
+		t.Assert(s1.Equals(s2), true)
+		t.Assert(s2.Equals(s1), true)
+		s2.Add(4)
+		t.Assert(s1.Equals(s2), false)
+		t.Assert(s1.ContainsAll(g.NewHashSetFrom([]int{1, 3})), true)
+		t.Assert(s1.ContainsAll(g.NewHashSetFrom([]int{1, 4})), false)
+	})
+}
+
+func TestSmallSet_Clone(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s1 := g.NewSmallSetFrom([]int{1, 2})
+		s2 := s1.Clone().(*g.SmallSet[int])
+		s2.Add(3)
+		t.Assert(s1.Size(), 2)
+		t.Assert(s2.Size(), 3)
+		s3 := s1.DeepCopy()
+		t.Assert(s3.Equals(s1), true)
+	})
+}
+
+func TestSmallSet_Json(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s1 := g.NewSmallSetFrom([]string{"a", "b"})
+		b, err := json.Marshal(s1)
+		t.AssertNil(err)
+		var s2 g.SmallSet[string]
+		err = json.UnmarshalUseNumber(b, &s2)
+		t.AssertNil(err)
+		t.Assert(s2.Size(), 2)
+		t.Assert(s2.Contains("a"), true)
+		t.Assert(s2.Contains("b"), true)
+		t.Assert(s1.Join(","), "a,b")
+		t.Assert(s1.String(), `["a","b"]`)
+	})
+}
+
+func TestSmallSet_PowerSet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewSmallSetFrom([]int{1, 2, 3})
+		pairs := 0
+		s.Combinations(2, func(combination []int) bool {
+			t.Assert(combination[0] != combination[1], true)
+			pairs++
+			return true
+		})
+		t.Assert(pairs, 3)
+		sizes := make([]int, 0)
+		s.PowerSet(func(subset g.Set[int]) bool {
+			sizes = append(sizes, subset.Size())
+			return true
+		})
+		t.Assert(sizes, []int{0, 1, 1, 1, 2, 2, 2, 3})
+	})
+}
+
+func TestSmallSet_Predicates(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewSmallSetFrom([]string{"a", "bb", "ccc"})
+		t.Assert(s.Any(func(v string) bool { return len(v) == 2 }), true)
+		t.Assert(s.All(func(v string) bool { return len(v) < 3 }), false)
+		t.Assert(s.None(func(v string) bool { return v == "d" }), true)
+		t.Assert(s.CountIf(func(v string) bool { return len(v) > 1 }), 2)
+	})
+}
+
+func TestSmallSet_RemoveIf(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewSmallSetFrom([]int{1, 2, 3, 4})
+		t.Assert(s.IsUpgraded(), false)
+		t.Assert(s.RemoveIf(func(v int) bool { return v%2 == 1 }), 2)
+		t.Assert(s.Size(), 2)
+		t.Assert(s.Contains(2), true)
+		t.Assert(s.Contains(3), false)
+
+		upgraded := g.NewSmallSetSize[int](2)
+		upgraded.Add(1, 2, 3, 4)
+		t.Assert(upgraded.IsUpgraded(), true)
+		t.Assert(upgraded.RemoveIf(func(v int) bool { return v < 3 }), 2)
+		t.Assert(upgraded.Size(), 2)
+		t.Assert(upgraded.Contains(4), true)
+	})
+}
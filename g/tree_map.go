@@ -9,7 +9,9 @@ package g
 import (
 	"bytes"
 	json2 "encoding/json"
+	"errors"
 	"fmt"
+	"hash/maphash"
 
 	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/rwmutex"
@@ -23,12 +25,20 @@ const (
 	black, red color = true, false
 )
 
+// ErrConcurrentModification is returned by the checked iterating functions of TreeMap,
+// if the tree is structurally modified during the iteration.
+// The other iterating functions stop iterating in this case without an error.
+var ErrConcurrentModification = errors.New("tree is structurally modified during iteration")
+
 // TreeMap implements the red-black tree.
 type TreeMap[K comparable, V any] struct {
-	mu         rwmutex.RWMutex
-	root       *RedBlackTreeNode[K, V]
-	size       int
-	comparator comparators.Comparator[K]
+	mu          rwmutex.RWMutex
+	root        *RedBlackTreeNode[K, V]
+	size        int
+	modCount    int // Number of structural modifications, used by iterators to fail fast.
+	comparator  comparators.Comparator[K]
+	arena       *nodeArena[RedBlackTreeNode[K, V]] // Optional node allocator, nil if disabled.
+	jsonOptions *JSONOptions                       // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // RedBlackTreeNode is a single element within the tree.
@@ -115,6 +125,10 @@ func (tree *TreeMap[K, V]) SetComparator(comparator comparators.Comparator[K]) {
 		// Resort the tree if comparators is changed.
 		tree.root = nil
 		tree.size = 0
+		tree.modCount++
+		if tree.arena != nil {
+			tree.arena.drop()
+		}
 		for k, v := range data {
 			tree.insertEntry(k, v)
 		}
@@ -164,14 +178,43 @@ func (tree *TreeMap[K, V]) Puts(data map[K]V) {
 	}
 }
 
+// PutAll batch sets key-values to the tree, which is alias of Puts.
+func (tree *TreeMap[K, V]) PutAll(data map[K]V) {
+	tree.Puts(data)
+}
+
+// PutAllFrom batch sets the key-value pairs of `other`, which can be any implementation of Map, to the tree
+// like Puts, in the order of other.ForEach. The pairs are taken from `other` before the tree is locked.
+func (tree *TreeMap[K, V]) PutAllFrom(other Map[K, V]) {
+	entries := mapEntries(other)
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	for _, entry := range entries {
+		tree.insertEntry(entry.Key, entry.Value)
+	}
+}
+
+// newNode creates a black node, which is allocated from the node arena if it is enabled.
+func (tree *TreeMap[K, V]) newNode(key K, value V, parent *RedBlackTreeNode[K, V]) *RedBlackTreeNode[K, V] {
+	if tree.arena == nil {
+		return &RedBlackTreeNode[K, V]{key: key, value: value, parent: parent, color: black}
+	}
+	node := tree.arena.alloc()
+	node.key = key
+	node.value = value
+	node.parent = parent
+	node.color = black
+	return node
+}
+
 func (tree *TreeMap[K, V]) insertEntry(key K, value V) (putValue V) {
 	t := tree.root
 	if t == nil {
 		tree.Comparator()(key, key) // type (and possibly nil) check
 
-		tree.root = &RedBlackTreeNode[K, V]{key: key, value: value, color: black}
+		tree.root = tree.newNode(key, value, nil)
 		tree.size = 1
-		//modCount++
+		tree.modCount++
 		return
 	}
 	var cmp int
@@ -193,7 +236,7 @@ func (tree *TreeMap[K, V]) insertEntry(key K, value V) (putValue V) {
 			break
 		}
 	}
-	e := &RedBlackTreeNode[K, V]{key: key, value: value, parent: parent, color: black}
+	e := tree.newNode(key, value, parent)
 	if cmp < 0 {
 		parent.left = e
 	} else {
@@ -201,7 +244,7 @@ func (tree *TreeMap[K, V]) insertEntry(key K, value V) (putValue V) {
 	}
 	tree.fixAfterInsertion(e)
 	tree.size++
-	//modCount++
+	tree.modCount++
 	return
 }
 
@@ -211,6 +254,24 @@ func (tree *TreeMap[K, V]) Get(key K) (value V) {
 	return
 }
 
+// GetOrDefault returns the value by given `key`, or `defaultValue` if the key is not found in the map.
+func (tree *TreeMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, found := tree.Search(key); found {
+		return value
+	}
+	return defaultValue
+}
+
+// MustGet returns the value by given `key`, and panics if the key is not found in the map,
+// which tells the missing keys from the ones mapped to empty values, unlike Get.
+func (tree *TreeMap[K, V]) MustGet(key K) V {
+	value, found := tree.Search(key)
+	if !found {
+		panic(fmt.Sprintf(`key not found: %v`, key))
+	}
+	return value
+}
+
 // doSetWithLockCheck checks whether value of the key exists with mutex.Lock,
 // if not exists, set value to the map with given `key`,
 // or else just return the existing value.
@@ -306,6 +367,7 @@ func (tree *TreeMap[K, V]) ContainsKey(key K) bool {
 
 func (tree *TreeMap[K, V]) deleteEntry(p *RedBlackTreeNode[K, V]) {
 	tree.size--
+	tree.modCount++
 
 	// If strictly internal, copy successor's element to p and then make p
 	// point to successor.
@@ -532,6 +594,57 @@ func (tree *TreeMap[K, V]) PollLastEntry() MapEntry[K, V] {
 	return node
 }
 
+// Compute computes the new value of `key` with `f` in one operation with the map locked, so it's atomic
+// to the other goroutines. The function `f` is called with the current value and whether the key exists,
+// and the key is set to the returned `newValue` if `f` returns true as `keep`, or else removed.
+// It returns the value of `key` after the call, and whether the key exists.
+func (tree *TreeMap[K, V]) Compute(key K, f func(value V, exists bool) (newValue V, keep bool)) (value V, exists bool) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	var oldValue V
+	node := tree.getEntry(key)
+	if node != nil {
+		oldValue = node.value
+	}
+	newValue, keep := f(oldValue, node != nil)
+	switch {
+	case !keep:
+		if node != nil {
+			tree.deleteEntry(node)
+		}
+		return
+	case node != nil:
+		node.value = newValue
+	default:
+		tree.insertEntry(key, newValue)
+	}
+	return newValue, true
+}
+
+// ComputeIfPresent computes the new value of `key` with `f` like Compute, but only if the key exists.
+// It returns the value of `key` after the call, and whether the key exists.
+func (tree *TreeMap[K, V]) ComputeIfPresent(key K, f func(value V) (newValue V, keep bool)) (value V, exists bool) {
+	return tree.Compute(key, func(value V, exists bool) (V, bool) {
+		if !exists {
+			return value, false
+		}
+		return f(value)
+	})
+}
+
+// MergeValue sets `value` to `key` if the key does not exist, or else sets the result of `f` called with
+// the current value and `value`, like counting with `m.MergeValue(key, 1, func(a, b int) int { return a + b })`.
+// It's done with the map locked, and returns the new value of `key`.
+func (tree *TreeMap[K, V]) MergeValue(key K, value V, f func(oldValue, value V) V) V {
+	newValue, _ := tree.Compute(key, func(oldValue V, exists bool) (V, bool) {
+		if !exists {
+			return value, true
+		}
+		return f(oldValue, value), true
+	})
+	return newValue
+}
+
 // Remove removes the node from the tree by `key`.
 func (tree *TreeMap[K, V]) Remove(key K) (value V, removed bool) {
 	tree.mu.Lock()
@@ -878,6 +991,34 @@ func (tree *TreeMap[K, V]) ForEach(f func(key K, value V) bool) {
 	tree.ForEachAsc(f)
 }
 
+// Entries returns all key-value pairs of the map as a slice of entries, in ascending order of the keys.
+func (tree *TreeMap[K, V]) Entries() []Entry[K, V] {
+	return collectEntries(tree.ForEach, tree.Size())
+}
+
+// ForEachEntry iterates the map readonly like ForEach, with each key-value pair passed as an entry.
+func (tree *TreeMap[K, V]) ForEachEntry(f func(entry Entry[K, V]) bool) {
+	tree.ForEach(func(key K, value V) bool {
+		return f(Entry[K, V]{Key: key, Value: value})
+	})
+}
+
+// IterKeys iterates the keys of the map readonly like ForEach, without allocating the slice of Keys.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (tree *TreeMap[K, V]) IterKeys(f func(key K) bool) {
+	tree.ForEach(func(key K, _ V) bool {
+		return f(key)
+	})
+}
+
+// IterValues iterates the values of the map readonly like ForEach, without allocating the slice of Values.
+// If `f` returns true, then it continues iterating; or false to stop.
+func (tree *TreeMap[K, V]) IterValues(f func(value V) bool) {
+	tree.ForEach(func(_ K, value V) bool {
+		return f(value)
+	})
+}
+
 // IteratorFrom is alias of IteratorAscFrom.
 func (tree *TreeMap[K, V]) IteratorFrom(key K, inclusive bool, f func(key K, value V) bool) {
 	tree.IteratorAscFrom(key, inclusive, f)
@@ -885,16 +1026,18 @@ func (tree *TreeMap[K, V]) IteratorFrom(key K, inclusive bool, f func(key K, val
 
 // ForEachAsc iterates the tree readonly in ascending order with given callback function `f`.
 // If `f` returns true, then it continues iterating; or false to stop.
+// It stops iterating if the tree is structurally modified by `f`, use ForEachAscChecked to get the error.
 func (tree *TreeMap[K, V]) ForEachAsc(f func(key K, value V) bool) {
 	tree.mu.RLock()
 	defer tree.mu.RUnlock()
-	tree.doIteratorAsc(tree.leftNode(), f)
+	_ = tree.doIteratorAsc(tree.leftNode(), f)
 }
 
 // IteratorAscFrom iterates the tree readonly in ascending order with given callback function `f`.
 // The parameter `key` specifies the start entry for iterating. The `match` specifies whether
 // starting iterating if the `key` is fully matched, or else using index searching iterating.
 // If `f` returns true, then it continues iterating; or false to stop.
+// It stops iterating if the tree is structurally modified by `f`, use IteratorAscFromChecked to get the error.
 func (tree *TreeMap[K, V]) IteratorAscFrom(key K, inclusive bool, f func(key K, value V) bool) {
 	tree.mu.RLock()
 	defer tree.mu.RUnlock()
@@ -907,16 +1050,116 @@ func (tree *TreeMap[K, V]) IteratorAscFrom(key K, inclusive bool, f func(key K,
 	if entry == nil {
 		return
 	}
-	tree.doIteratorAsc(entry.(*RedBlackTreeNode[K, V]), f)
+	_ = tree.doIteratorAsc(entry.(*RedBlackTreeNode[K, V]), f)
+}
+
+// ForEachAscChecked iterates the tree readonly in ascending order with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+//
+// Different from ForEachAsc, the tree is not locked while `f` is called, so `f` may modify the tree even if
+// it is concurrent-safe. It returns ErrConcurrentModification if the tree is structurally modified during
+// the iteration, by `f` or other goroutines, for example a new key is put or an existing key is removed,
+// as the iterating cannot be continued correctly then.
+func (tree *TreeMap[K, V]) ForEachAscChecked(f func(key K, value V) bool) error {
+	return tree.doIteratorChecked(func() *RedBlackTreeNode[K, V] {
+		tree.mu.RLock()
+		defer tree.mu.RUnlock()
+		return tree.leftNode()
+	}, successor[K, V], f)
+}
+
+// ForEachDescChecked iterates the tree readonly in descending order with given callback function `f`.
+// If `f` returns true, then it continues iterating; or false to stop.
+//
+// Different from ForEachDesc, it returns ErrConcurrentModification if the tree is structurally
+// modified during the iteration. See ForEachAscChecked.
+func (tree *TreeMap[K, V]) ForEachDescChecked(f func(key K, value V) bool) error {
+	return tree.doIteratorChecked(func() *RedBlackTreeNode[K, V] {
+		tree.mu.RLock()
+		defer tree.mu.RUnlock()
+		return tree.rightNode()
+	}, predecessor[K, V], f)
+}
+
+// IteratorAscFromChecked iterates the tree readonly in ascending order from `key` like IteratorAscFrom.
+// Different from IteratorAscFrom, it returns ErrConcurrentModification if the tree is structurally
+// modified during the iteration. See ForEachAscChecked.
+func (tree *TreeMap[K, V]) IteratorAscFromChecked(key K, inclusive bool, f func(key K, value V) bool) error {
+	return tree.doIteratorChecked(func() *RedBlackTreeNode[K, V] {
+		var entry MapEntry[K, V]
+		if inclusive {
+			entry = tree.CeilingEntry(key)
+		} else {
+			entry = tree.HigherEntry(key)
+		}
+		node, _ := entry.(*RedBlackTreeNode[K, V])
+		return node
+	}, successor[K, V], f)
+}
+
+// IteratorDescFromChecked iterates the tree readonly in descending order from `key` like IteratorDescFrom.
+// Different from IteratorDescFrom, it returns ErrConcurrentModification if the tree is structurally
+// modified during the iteration. See ForEachAscChecked.
+func (tree *TreeMap[K, V]) IteratorDescFromChecked(key K, inclusive bool, f func(key K, value V) bool) error {
+	return tree.doIteratorChecked(func() *RedBlackTreeNode[K, V] {
+		var entry MapEntry[K, V]
+		if inclusive {
+			entry = tree.FloorEntry(key)
+		} else {
+			entry = tree.LowerEntry(key)
+		}
+		node, _ := entry.(*RedBlackTreeNode[K, V])
+		return node
+	}, predecessor[K, V], f)
+}
+
+// doIteratorChecked iterates the tree from the node returned by `start`, moving to the next node by `next`.
+// The modification count is taken before `start`, and checked under the read lock before each step,
+// while `f` is called without lock.
+func (tree *TreeMap[K, V]) doIteratorChecked(
+	start func() *RedBlackTreeNode[K, V],
+	next func(node *RedBlackTreeNode[K, V]) *RedBlackTreeNode[K, V],
+	f func(key K, value V) bool,
+) error {
+	tree.mu.RLock()
+	expectedModCount := tree.modCount
+	tree.mu.RUnlock()
+	node := start()
+	for node != nil {
+		tree.mu.RLock()
+		if tree.modCount != expectedModCount {
+			tree.mu.RUnlock()
+			return ErrConcurrentModification
+		}
+		key, value := node.key, node.value
+		tree.mu.RUnlock()
+		if !f(key, value) {
+			return nil
+		}
+		tree.mu.RLock()
+		if tree.modCount != expectedModCount {
+			tree.mu.RUnlock()
+			return ErrConcurrentModification
+		}
+		node = next(node)
+		tree.mu.RUnlock()
+	}
+	return nil
 }
 
-func (tree *TreeMap[K, V]) doIteratorAsc(node *RedBlackTreeNode[K, V], f func(key K, value V) bool) {
+// doIteratorAsc iterates the tree in ascending order from `node`.
+// It stops iterating and returns ErrConcurrentModification if the tree is structurally modified by `f`.
+func (tree *TreeMap[K, V]) doIteratorAsc(node *RedBlackTreeNode[K, V], f func(key K, value V) bool) error {
+	expectedModCount := tree.modCount
 loop:
 	if node == nil {
-		return
+		return nil
 	}
 	if !f(node.key, node.value) {
-		return
+		return nil
+	}
+	if tree.modCount != expectedModCount {
+		return ErrConcurrentModification
 	}
 	if node.right != nil {
 		node = node.right
@@ -934,20 +1177,23 @@ loop:
 			}
 		}
 	}
+	return nil
 }
 
 // ForEachDesc iterates the tree readonly in descending order with given callback function `f`.
 // If `f` returns true, then it continues iterating; or false to stop.
+// It stops iterating if the tree is structurally modified by `f`, use ForEachDescChecked to get the error.
 func (tree *TreeMap[K, V]) ForEachDesc(f func(key K, value V) bool) {
 	tree.mu.RLock()
 	defer tree.mu.RUnlock()
-	tree.doIteratorDesc(tree.rightNode(), f)
+	_ = tree.doIteratorDesc(tree.rightNode(), f)
 }
 
 // IteratorDescFrom iterates the tree readonly in descending order with given callback function `f`.
 // The parameter `key` specifies the start entry for iterating. The `match` specifies whether
 // starting iterating if the `key` is fully matched, or else using index searching iterating.
 // If `f` returns true, then it continues iterating; or false to stop.
+// It stops iterating if the tree is structurally modified by `f`, use IteratorDescFromChecked to get the error.
 func (tree *TreeMap[K, V]) IteratorDescFrom(key K, inclusive bool, f func(key K, value V) bool) {
 	tree.mu.RLock()
 	defer tree.mu.RUnlock()
@@ -960,16 +1206,22 @@ func (tree *TreeMap[K, V]) IteratorDescFrom(key K, inclusive bool, f func(key K,
 	if entry == nil {
 		return
 	}
-	tree.doIteratorDesc(entry.(*RedBlackTreeNode[K, V]), f)
+	_ = tree.doIteratorDesc(entry.(*RedBlackTreeNode[K, V]), f)
 }
 
-func (tree *TreeMap[K, V]) doIteratorDesc(node *RedBlackTreeNode[K, V], f func(key K, value V) bool) {
+// doIteratorDesc iterates the tree in descending order from `node`.
+// It stops iterating and returns ErrConcurrentModification if the tree is structurally modified by `f`.
+func (tree *TreeMap[K, V]) doIteratorDesc(node *RedBlackTreeNode[K, V], f func(key K, value V) bool) error {
+	expectedModCount := tree.modCount
 loop:
 	if node == nil {
-		return
+		return nil
 	}
 	if !f(node.key, node.value) {
-		return
+		return nil
+	}
+	if tree.modCount != expectedModCount {
+		return ErrConcurrentModification
 	}
 	if node.left != nil {
 		node = node.left
@@ -987,6 +1239,7 @@ loop:
 			}
 		}
 	}
+	return nil
 }
 
 func (tree *TreeMap[K, V]) LastEntry() MapEntry[K, V] {
@@ -1057,6 +1310,40 @@ func (tree *TreeMap[K, V]) Clear() {
 	defer tree.mu.Unlock()
 	tree.root = nil
 	tree.size = 0
+	tree.modCount++
+	if tree.arena != nil {
+		tree.arena.drop()
+	}
+}
+
+// SetNodeArena enables allocating nodes from an arena, which allocates `chunkSize` nodes at a time
+// instead of one by one. It reduces the allocations of trees holding a huge number of nodes.
+// The arena is disabled if `chunkSize` <= 0.
+//
+// The arena is meant for the trees built and then dropped by Reset as a whole. The memory of the removed
+// nodes is not reused until Reset, so that the nodes previously returned by the tree, like Left or Floor,
+// are never reused for other keys, which means the chunks keep growing with the insertions and removals
+// in between, and a node still in the tree keeps its whole chunk alive.
+// Use Clear or Replace instead of Reset, or don't enable the arena, for long-lived trees with churn.
+func (tree *TreeMap[K, V]) SetNodeArena(chunkSize int) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.arena = newNodeArena[RedBlackTreeNode[K, V]](chunkSize)
+}
+
+// Reset removes all nodes from the tree. If the node arena is enabled, all nodes are returned
+// to the arena at once and their memory is reused by later insertions, or else it is the same as Clear.
+//
+// Note that any node previously returned by the tree, like Left or Right, must not be used after Reset.
+func (tree *TreeMap[K, V]) Reset() {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.root = nil
+	tree.size = 0
+	tree.modCount++
+	if tree.arena != nil {
+		tree.arena.reset()
+	}
 }
 
 // Replace the data of the tree with given `data`.
@@ -1065,11 +1352,26 @@ func (tree *TreeMap[K, V]) Replace(data map[K]V) {
 	defer tree.mu.Unlock()
 	tree.root = nil
 	tree.size = 0
+	tree.modCount++
+	if tree.arena != nil {
+		tree.arena.drop()
+	}
 	for k, v := range data {
 		tree.insertEntry(k, v)
 	}
 }
 
+// ReplaceAll replaces the value of every key with the result of `f` called with the key and its value,
+// in ascending order of the keys and under one lock.
+// It's not a structural modification, so it doesn't fail the iterations.
+func (tree *TreeMap[K, V]) ReplaceAll(f func(key K, value V) V) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	for node := tree.leftNode(); node != nil; node = successor(node) {
+		node.value = f(node.key, node.value)
+	}
+}
+
 // String returns a string representation of container.
 func (tree *TreeMap[K, V]) String() string {
 	if tree == nil {
@@ -1084,6 +1386,24 @@ func (tree *TreeMap[K, V]) String() string {
 	return str
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (tree *TreeMap[K, V]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, tree)
+}
+
+// formatEntries implements the interface formattable.
+func (tree *TreeMap[K, V]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = tree.Size()
+	return collectFormatPairs(tree.ForEach, size, limit), true, size
+}
+
+// Hash64 returns the 64-bit hash of the key-value pairs using `seed`, which is insensitive to the order of pairs.
+func (tree *TreeMap[K, V]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnorderedPairs(seed, tree.ForEach)
+}
+
 // Print prints the tree to stdout.
 func (tree *TreeMap[K, V]) Print() {
 	fmt.Println(tree.String())
@@ -1238,15 +1558,25 @@ func (tree *TreeMap[K, V]) fixAfterInsertion(x *RedBlackTreeNode[K, V]) {
 	tree.root.color = black
 }
 
+// SetMarshalOptions sets the options applied to the values by MarshalJSON, see JSONOptions.
+func (tree *TreeMap[K, V]) SetMarshalOptions(options JSONOptions) {
+	tree.mu.Lock()
+	defer tree.mu.Unlock()
+	tree.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (tree TreeMap[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 	if tree.root == nil {
 		return []byte("null"), nil
 	}
+	tree.mu.RLock()
+	options := tree.jsonOptions
+	tree.mu.RUnlock()
 	buffer := bytes.NewBuffer(nil)
 	buffer.WriteByte('{')
 	tree.ForEach(func(key K, value V) bool {
-		valueBytes, valueJsonErr := json.Marshal(value)
+		valueBytes, valueJsonErr := marshalJSONValue(options, value)
 		if valueJsonErr != nil {
 			err = valueJsonErr
 			return false
@@ -1258,6 +1588,9 @@ func (tree TreeMap[K, V]) MarshalJSON() (jsonBytes []byte, err error) {
 		return true
 	})
 	buffer.WriteByte('}')
+	if err != nil {
+		return nil, err
+	}
 	return buffer.Bytes(), nil
 }
 
@@ -0,0 +1,65 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package g
+
+// nodeArena allocates tree nodes from contiguous chunks of memory instead of one heap allocation per node,
+// which reduces the allocations of building huge trees.
+//
+// It is designed for the build-then-reset workloads only. Nodes are never reused one by one, as the nodes
+// removed from a tree may still be referenced by the callers which got them as entries, so the chunks grow
+// without bound if nodes are inserted and removed repeatedly between resets.
+// All nodes are returned at once by reset, which keeps the chunks for reuse.
+// Note that the garbage collector still scans the whole chunks if the nodes hold pointers,
+// and any referenced node keeps its whole chunk alive.
+// nodeArena is not concurrent-safe, it relies on the lock of the tree it belongs to.
+type nodeArena[N any] struct {
+	chunkSize int   // Number of nodes in each chunk.
+	chunks    [][]N // Allocated chunks.
+	chunk     int   // Index of the chunk currently allocating from.
+	offset    int   // Index of the next unused node in current chunk.
+}
+
+// newNodeArena creates and returns a node arena allocating `chunkSize` nodes in each chunk.
+// It returns nil if `chunkSize` <= 0, which means the arena is disabled.
+func newNodeArena[N any](chunkSize int) *nodeArena[N] {
+	if chunkSize <= 0 {
+		return nil
+	}
+	return &nodeArena[N]{chunkSize: chunkSize}
+}
+
+// alloc returns a zero node from the arena.
+func (a *nodeArena[N]) alloc() *N {
+	if a.chunk < len(a.chunks) && a.offset == len(a.chunks[a.chunk]) {
+		a.chunk++
+		a.offset = 0
+	}
+	if a.chunk == len(a.chunks) {
+		a.chunks = append(a.chunks, make([]N, a.chunkSize))
+	}
+	node := &a.chunks[a.chunk][a.offset]
+	a.offset++
+	return node
+}
+
+// reset returns all nodes to the arena at once.
+// The chunks are zeroed and kept for reusing, so all nodes previously allocated become invalid.
+func (a *nodeArena[N]) reset() {
+	for i := 0; i < len(a.chunks) && i <= a.chunk; i++ {
+		clear(a.chunks[i])
+	}
+	a.chunk = 0
+	a.offset = 0
+}
+
+// drop discards all chunks of the arena, leaving them to the garbage collector.
+// Different from reset, nodes previously allocated remain valid as long as they are referenced.
+func (a *nodeArena[N]) drop() {
+	a.chunks = nil
+	a.chunk = 0
+	a.offset = 0
+}
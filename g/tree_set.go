@@ -2,6 +2,8 @@ package g
 
 import (
 	"bytes"
+	"fmt"
+	"hash/maphash"
 
 	"github.com/wesleywu/gcontainer/internal/deepcopy"
 	"github.com/wesleywu/gcontainer/internal/json"
@@ -17,8 +19,9 @@ import (
 // It contains a concurrent-safe/unsafe switch, which should be set
 // when its initialization and cannot be changed then.
 type TreeSet[T comparable] struct {
-	mu   rwmutex.RWMutex
-	tree *TreeMap[T, struct{}]
+	mu          rwmutex.RWMutex
+	tree        *TreeMap[T, struct{}]
+	jsonOptions *JSONOptions // Options of MarshalJSON set by SetMarshalOptions, nil for the default.
 }
 
 // NewTreeSet creates and returns an empty sorted set.
@@ -391,6 +394,26 @@ func (t *TreeSet[T]) RemoveAll(elements Collection[T]) bool {
 	return changed
 }
 
+// RemoveIf removes the items for which `predicate` returns true with the set locked,
+// and returns the number of removed items.
+func (t *TreeSet[T]) RemoveIf(predicate func(element T) bool) (removed int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lazyInit()
+	// The matched keys are collected first, as the tree cannot be modified while iterating.
+	var matched []T
+	t.tree.ForEach(func(key T, _ struct{}) bool {
+		if predicate(key) {
+			matched = append(matched, key)
+		}
+		return true
+	})
+	for _, key := range matched {
+		t.tree.Remove(key)
+	}
+	return len(matched)
+}
+
 func (t *TreeSet[T]) Size() int {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -405,6 +428,47 @@ func (t *TreeSet[T]) Slice() []T {
 	return t.tree.Keys()
 }
 
+// Combinations calls `f` with every combination of `k` items of the set in lexicographic order,
+// until `f` returns false. The slice passed to `f` is sorted, and it is reused between the calls.
+// The items are taken as a snapshot, so `f` can modify the set.
+func (t *TreeSet[T]) Combinations(k int, f func(combination []T) bool) {
+	forEachCombination(t.Slice(), k, f)
+}
+
+// PowerSet calls `f` with every subset of the set as a new TreeSet with the same comparator,
+// in ascending order of their sizes, until `f` returns false. It makes 2^n calls for a set of size n.
+func (t *TreeSet[T]) PowerSet(f func(subset Set[T]) bool) {
+	comparator := t.Comparator()
+	forEachSubset(t.Slice(), func(items []T) Set[T] {
+		return NewTreeSetFrom(items, comparator)
+	}, f)
+}
+
+// Any returns true if `predicate` returns true for any item of the set, which iterates in ascending order
+// and stops at the first match. It returns false for an empty set.
+func (t *TreeSet[T]) Any(predicate func(v T) bool) bool {
+	return anyMatch(t.ForEach, predicate)
+}
+
+// All returns true if `predicate` returns true for all items of the set, which stops at the first mismatch.
+// It returns true for an empty set.
+func (t *TreeSet[T]) All(predicate func(v T) bool) bool {
+	return !anyMatch(t.ForEach, func(v T) bool {
+		return !predicate(v)
+	})
+}
+
+// None returns true if `predicate` returns false for all items of the set, which stops at the first match.
+// It returns true for an empty set.
+func (t *TreeSet[T]) None(predicate func(v T) bool) bool {
+	return !anyMatch(t.ForEach, predicate)
+}
+
+// CountIf returns the number of items of the set for which `predicate` returns true.
+func (t *TreeSet[T]) CountIf(predicate func(v T) bool) int {
+	return countMatch(t.ForEach, predicate)
+}
+
 func (t *TreeSet[T]) String() string {
 	if t == nil {
 		return ""
@@ -439,6 +503,24 @@ func (t *TreeSet[T]) String() string {
 	return buffer.String()
 }
 
+// Format implements the interface fmt.Formatter.
+// The verbs `%v` and `%s` print the same as String, `%+v` prints the pretty form limited by FormatOptions,
+// and the other verbs print the elements formatted with the verb.
+func (t *TreeSet[T]) Format(s fmt.State, verb rune) {
+	formatContainer(s, verb, t)
+}
+
+// formatEntries implements the interface formattable.
+func (t *TreeSet[T]) formatEntries(limit int) (entries []formatEntry, isMap bool, size int) {
+	size = t.Size()
+	return collectFormatValues(t.ForEach, size, limit), false, size
+}
+
+// Hash64 returns the 64-bit hash of the elements using `seed`, which is insensitive to the order of elements.
+func (t *TreeSet[T]) Hash64(seed maphash.Seed) uint64 {
+	return hashUnordered(seed, t.ForEach)
+}
+
 func (t *TreeSet[T]) SubSet(fromElement T, fromInclusive bool, toElement T, toInclusive bool) SortedSet[T] {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -460,9 +542,19 @@ func (t *TreeSet[T]) TailSet(fromElement T, inclusive bool) SortedSet[T] {
 	return result
 }
 
+// SetMarshalOptions sets the options applied to the elements by MarshalJSON, see JSONOptions.
+func (t *TreeSet[T]) SetMarshalOptions(options JSONOptions) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.jsonOptions = newJSONOptions(options)
+}
+
 // MarshalJSON implements the interface MarshalJSON for json.Marshal.
 func (t TreeSet[T]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(t.Slice())
+	t.mu.RLock()
+	options := t.jsonOptions
+	t.mu.RUnlock()
+	return marshalJSONSlice(options, t.Slice())
 }
 
 // UnmarshalJSON implements the interface UnmarshalJSON for json.Unmarshal.
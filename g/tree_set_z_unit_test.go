@@ -141,6 +141,79 @@ func TestTreeSet_Clone(t *testing.T) {
 	})
 }
 
+func TestTreeSet_Combinations(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewTreeSetFrom([]int{4, 1, 3, 2}, comparators.ComparatorInt)
+		var combinations [][]int
+		s.Combinations(2, func(combination []int) bool {
+			combinations = append(combinations, append([]int(nil), combination...))
+			return true
+		})
+		t.Assert(combinations, [][]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}})
+
+		n := 0
+		s.Combinations(3, func(combination []int) bool {
+			n++
+			return n < 2
+		})
+		t.Assert(n, 2)
+		s.Combinations(0, func(combination []int) bool {
+			t.Assert(len(combination), 0)
+			n++
+			return true
+		})
+		t.Assert(n, 3)
+		s.Combinations(5, func(combination []int) bool {
+			n++
+			return true
+		})
+		t.Assert(n, 3)
+	})
+}
+
+func TestTreeSet_PowerSet(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewTreeSetFrom([]int{3, 1, 2}, comparators.ComparatorInt)
+		var subsets []string
+		s.PowerSet(func(subset g.Set[int]) bool {
+			subsets = append(subsets, subset.Join(","))
+			return true
+		})
+		t.Assert(subsets, []string{"", "1", "2", "3", "1,2", "1,3", "2,3", "1,2,3"})
+
+		n := 0
+		s.PowerSet(func(subset g.Set[int]) bool {
+			n++
+			return subset.Size() < 1
+		})
+		t.Assert(n, 2)
+	})
+}
+
+func TestTreeSet_Predicates(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewTreeSetFrom([]int{5, 1, 3}, comparators.ComparatorInt)
+		var visited []int
+		t.Assert(s.Any(func(v int) bool {
+			visited = append(visited, v)
+			return v > 2
+		}), true)
+		t.Assert(visited, []int{1, 3})
+		t.Assert(s.All(func(v int) bool { return v%2 == 1 }), true)
+		t.Assert(s.None(func(v int) bool { return v > 5 }), true)
+		t.Assert(s.CountIf(func(v int) bool { return v >= 3 }), 2)
+	})
+}
+
+func TestTreeSet_RemoveIf(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		s := g.NewTreeSetFrom([]int{5, 1, 4, 2, 3}, comparators.ComparatorInt)
+		t.Assert(s.RemoveIf(func(v int) bool { return v > 2 }), 3)
+		t.Assert(s.Slice(), []int{1, 2})
+		t.Assert(s.RemoveIf(func(v int) bool { return false }), 0)
+	})
+}
+
 func TestTreeSet_Contains(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
 		s := g.NewTreeSetDefault[int](true)
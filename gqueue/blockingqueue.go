@@ -18,7 +18,10 @@
 package gqueue
 
 import (
+	"context"
 	"math"
+	"sync"
+	"time"
 
 	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/gtype"
@@ -26,16 +29,26 @@ import (
 
 // BlockingQueue is a concurrent-safe queue built on doubly linked list and channel.
 type BlockingQueue[T any] struct {
-	limit  int              // Limit for queue size.
-	list   *g.LinkedList[T] // Underlying list structure for data maintaining.
-	closed *gtype.Bool      // Whether queue is closed.
-	events chan struct{}    // Events for data writing.
-	C      chan T           // Underlying channel for data reading.
+	limit      int                       // Limit for queue size.
+	list       *g.LinkedList[T]          // Underlying list structure for data maintaining.
+	closed     *gtype.Bool               // Whether queue is closed.
+	deliveryMu sync.Mutex                // Guards inflight and ackTimeout.
+	inflight   map[*Delivery[T]]struct{} // Deliveries popped but not yet acked or nacked.
+	ackTimeout time.Duration             // Deadline for acking the deliveries, or 0 if there is none.
+	moving     *gtype.Int32              // Number of items popped from list but not yet sent to channel.
+	emptyMu    sync.Mutex                // Guards emptyWait.
+	emptyWait  chan struct{}             // Closed to wake up WaitUntilEmpty when items are popped, or nil if none waits.
+	waiters    *gtype.Int32              // Number of goroutines blocked in WaitUntilEmpty.
+	done       chan struct{}             // Closed when the queue is closed.
+	events     chan struct{}             // Events for data writing.
+	C          chan T                    // Underlying channel for data reading.
 }
 
 const (
 	defaultQueueSize = 10000 // Size for queue buffer.
 	defaultBatchSize = 10    // Max batch size per-fetching from list.
+
+	emptyCheckInterval = 50 * time.Millisecond // Interval for checking whether queue is empty, for the items received from C directly.
 )
 
 // New returns an empty queue object.
@@ -43,7 +56,11 @@ const (
 // When `limit` is given, the queue will be static and high performance which is any with stdlib channel.
 func New[T any](limit ...int) *BlockingQueue[T] {
 	q := &BlockingQueue[T]{
-		closed: gtype.NewBool(),
+		closed:   gtype.NewBool(),
+		inflight: make(map[*Delivery[T]]struct{}),
+		moving:   gtype.NewInt32(),
+		waiters:  gtype.NewInt32(),
+		done:     make(chan struct{}),
 	}
 	if len(limit) > 0 && limit[0] > 0 {
 		q.limit = limit[0]
@@ -57,6 +74,18 @@ func New[T any](limit ...int) *BlockingQueue[T] {
 	return q
 }
 
+// NewWithContext returns an empty queue object bound to `ctx`, which is closed automatically when `ctx` is done.
+// The parameter `limit` is used to limit the size of the queue, which is unlimited if `limit` <= 0.
+//
+// If the optional parameter `drain` is true, the queue is closed after all the remaining items are popped
+// when `ctx` is done, or else it is closed immediately, discarding the items not yet popped.
+// Note that producers should stop pushing when `ctx` is done, as Push panics after the queue is closed.
+func NewWithContext[T any](ctx context.Context, limit int, drain ...bool) *BlockingQueue[T] {
+	q := New[T](limit)
+	go q.closeWhenDone(ctx, len(drain) > 0 && drain[0])
+	return q
+}
+
 // Push pushes the data `v` into the queue.
 // Note that it would panic if Push is called after the queue is closed.
 func (q *BlockingQueue[T]) Push(v T) {
@@ -73,12 +102,15 @@ func (q *BlockingQueue[T]) Push(v T) {
 // MustPop pops an item from the queue in FIFO way.
 // Note that it would return empty value of T or nil if T is a pointer, when Pop is called after the queue is closed.
 func (q *BlockingQueue[T]) MustPop() T {
-	return <-q.C
+	v := <-q.C
+	q.notifyPopped()
+	return v
 }
 
 // Pop pops an item from the queue in FIFO way, and a bool value indicating whether the channel is still open.
 func (q *BlockingQueue[T]) Pop() (result T, ok bool) {
 	result, ok = <-q.C
+	q.notifyPopped()
 	return
 }
 
@@ -89,6 +121,9 @@ func (q *BlockingQueue[T]) Close() {
 	if !q.closed.Cas(false, true) {
 		return
 	}
+	close(q.done)
+	// The items left in the list of unlimited queue are discarded, which might make the queue empty.
+	defer q.notifyPopped()
 	if q.events != nil {
 		close(q.events)
 	}
@@ -112,6 +147,106 @@ func (q *BlockingQueue[T]) Len() (length int64) {
 	return int64(q.list.Size()) + bufferedSize
 }
 
+// WaitUntilEmpty blocks until all items pushed into the queue have been popped, or `ctx` is done.
+// It returns the error of `ctx` if `ctx` is done before the queue becomes empty.
+//
+// Note that items popped by PopDelivery are considered popped, no matter whether they are acked,
+// use Unacked to check the in-flight deliveries.
+//
+// It is woken up by Pop, MustPop and PopDelivery immediately. The items received from C directly
+// are not notified, which are noticed by checking the queue every 50 milliseconds.
+func (q *BlockingQueue[T]) WaitUntilEmpty(ctx context.Context) error {
+	q.waiters.Add(1)
+	defer q.waiters.Add(-1)
+	var timer *time.Timer
+	for {
+		// The signal channel is fetched before checking, so that the items popped after the check are not missed.
+		q.emptyMu.Lock()
+		if q.emptyWait == nil {
+			q.emptyWait = make(chan struct{})
+		}
+		wait := q.emptyWait
+		q.emptyMu.Unlock()
+		if q.isEmpty() {
+			break
+		}
+		if timer == nil {
+			timer = time.NewTimer(emptyCheckInterval)
+			defer timer.Stop()
+		} else {
+			timer.Reset(emptyCheckInterval)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wait:
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// notifyPopped wakes up the goroutines blocked in WaitUntilEmpty to check whether the queue is empty.
+func (q *BlockingQueue[T]) notifyPopped() {
+	if q.waiters.Val() == 0 {
+		return
+	}
+	q.emptyMu.Lock()
+	defer q.emptyMu.Unlock()
+	if q.emptyWait != nil {
+		close(q.emptyWait)
+		q.emptyWait = nil
+	}
+}
+
+// Join blocks until the queue is closed and all the remaining items have been popped,
+// which works like sync.WaitGroup.Wait for the consumers draining the queue.
+//
+// Note that the items not yet transferred from list to channel are discarded when
+// an unlimited queue is closed, so they are not waited for.
+func (q *BlockingQueue[T]) Join() {
+	<-q.done
+	_ = q.WaitUntilEmpty(context.Background())
+}
+
+// closeWhenDone closes the queue when `ctx` is done, after the queue is drained if `drain` is true.
+// It returns immediately if the queue is closed by Close before that.
+func (q *BlockingQueue[T]) closeWhenDone(ctx context.Context, drain bool) {
+	select {
+	case <-q.done:
+		return
+	case <-ctx.Done():
+	}
+	if drain {
+		drainCtx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-q.done:
+				cancel()
+			case <-drainCtx.Done():
+			}
+		}()
+		_ = q.WaitUntilEmpty(drainCtx)
+		cancel()
+	}
+	q.Close()
+}
+
+// isEmpty checks whether all items pushed into the queue have been popped.
+// The list, the moving items and the channel are checked in the same order as items flow,
+// so that an item being transferred is never missed.
+func (q *BlockingQueue[T]) isEmpty() bool {
+	if q.limit <= 0 {
+		if !q.closed.Val() && q.list.Len() > 0 {
+			return false
+		}
+		if q.moving.Val() > 0 {
+			return false
+		}
+	}
+	return len(q.C) == 0
+}
+
 // Size is alias of Len.
 // Deprecated: use Len instead.
 func (q *BlockingQueue[T]) Size() int64 {
@@ -133,9 +268,13 @@ func (q *BlockingQueue[T]) asyncLoopFromListToChannel() {
 				// When q.C is closed, it will panic here, especially q.C is being blocked for writing.
 				// If any error occurs here, it will be caught by recover and be ignored.
 				for i := 0; i < bufferLength; i++ {
+					q.moving.Add(1)
 					if front, ok := q.list.PopFront(); ok {
 						q.C <- front
 					}
+					q.moving.Add(-1)
+					// The item might be popped before it is counted out of moving.
+					q.notifyPopped()
 				}
 			} else {
 				break
@@ -0,0 +1,191 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gqueue
+
+import (
+	"time"
+
+	"github.com/wesleywu/gcontainer/gtype"
+)
+
+// Delivery is a handle of an item popped from the queue in acknowledged-delivery mode.
+// The item is considered in-flight until the handle is finalized by Ack, or returned
+// to the queue by Nack, Recover or the expiry of the ack timeout.
+//
+// The items are returned to the queue without blocking. If a queue with limit is full, the item
+// stays in-flight, so that it is returned again by Recover or the next expiry of the ack timeout.
+type Delivery[T any] struct {
+	queue    *BlockingQueue[T] // The queue that the item is popped from.
+	value    T                 // The delivered item.
+	finished *gtype.Bool       // Whether the delivery is already finalized.
+	timer    *time.Timer       // Timer of the ack timeout, or nil if there is none.
+}
+
+// SetAckTimeout sets the deadline for acking the deliveries popped afterwards.
+// A delivery neither acked nor nacked within `timeout` is requeued to the end of the queue, so that the item
+// is redelivered if the consumer crashes or never acks it, and a later Ack or Nack of it returns false.
+// If the queue is full at the deadline, the delivery stays in-flight with the deadline extended by `timeout`.
+// The deadline is disabled if `timeout` is not positive, which is the default.
+func (q *BlockingQueue[T]) SetAckTimeout(timeout time.Duration) {
+	q.deliveryMu.Lock()
+	defer q.deliveryMu.Unlock()
+	q.ackTimeout = max(timeout, 0)
+}
+
+// PopDelivery pops an item from the queue in FIFO way and returns it as a delivery handle,
+// and a bool value indicating whether the channel is still open.
+// The caller should call Ack on the returned handle after the item is processed,
+// or Nack to return the item to the queue, so that the item is not lost if the
+// processing fails between popping and processing.
+// The items of unfinished deliveries are requeued by Recover, or automatically after the ack timeout
+// if it is set by SetAckTimeout.
+func (q *BlockingQueue[T]) PopDelivery() (delivery *Delivery[T], ok bool) {
+	value, ok := <-q.C
+	q.notifyPopped()
+	if !ok {
+		return nil, false
+	}
+	delivery = &Delivery[T]{
+		queue:    q,
+		value:    value,
+		finished: gtype.NewBool(),
+	}
+	q.deliveryMu.Lock()
+	defer q.deliveryMu.Unlock()
+	delivery.trackWithoutLock()
+	return delivery, true
+}
+
+// Unacked returns the number of deliveries that are popped but neither acked nor nacked.
+func (q *BlockingQueue[T]) Unacked() int64 {
+	q.deliveryMu.Lock()
+	defer q.deliveryMu.Unlock()
+	return int64(len(q.inflight))
+}
+
+// Recover requeues the items of all the deliveries that are popped but neither acked nor nacked,
+// which is used to redeliver the items of consumers known to be gone, and returns the number of them.
+// A later Ack or Nack of the recovered deliveries returns false.
+// The deliveries which can't be requeued as the queue is full stay in-flight and are not counted.
+func (q *BlockingQueue[T]) Recover() int {
+	q.deliveryMu.Lock()
+	deliveries := make([]*Delivery[T], 0, len(q.inflight))
+	for d := range q.inflight {
+		deliveries = append(deliveries, d)
+	}
+	q.deliveryMu.Unlock()
+	count := 0
+	for _, d := range deliveries {
+		if d.finish() && d.requeue() {
+			count++
+		}
+	}
+	return count
+}
+
+// Value returns the delivered item.
+func (d *Delivery[T]) Value() T {
+	return d.value
+}
+
+// Ack finalizes the delivery, which means the item is processed and will never be redelivered.
+// It returns false if the delivery is already acked, nacked or requeued for the ack timeout or Recover.
+func (d *Delivery[T]) Ack() bool {
+	return d.finish()
+}
+
+// Nack finalizes the delivery as not processed.
+// If `requeue` is true, the item is pushed back to the end of the queue after `delay`,
+// or immediately if `delay` is not positive; or else the item is discarded.
+// It returns false if the delivery is already acked, nacked or requeued for the ack timeout or Recover.
+//
+// If a queue with limit is full, Nack with no delay returns false and the delivery stays in-flight,
+// so that it can be nacked again later; the delivery nacked with delay becomes in-flight again.
+// Note that the requeued item is discarded if the queue is closed before it is pushed back.
+func (d *Delivery[T]) Nack(requeue bool, delay time.Duration) bool {
+	if !d.finish() {
+		return false
+	}
+	if !requeue {
+		return true
+	}
+	if delay <= 0 {
+		return d.requeue()
+	}
+	time.AfterFunc(delay, func() {
+		d.requeue()
+	})
+	return true
+}
+
+// finish marks the delivery finalized and removes it from the in-flight deliveries.
+// It returns false if the delivery is already finalized.
+func (d *Delivery[T]) finish() bool {
+	if !d.finished.Cas(false, true) {
+		return false
+	}
+	d.queue.deliveryMu.Lock()
+	defer d.queue.deliveryMu.Unlock()
+	delete(d.queue.inflight, d)
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	return true
+}
+
+// trackWithoutLock adds the delivery to the in-flight deliveries, and starts the timer of the ack timeout
+// if it is set. It must be called with deliveryMu locked.
+func (d *Delivery[T]) trackWithoutLock() {
+	q := d.queue
+	q.inflight[d] = struct{}{}
+	if q.ackTimeout > 0 {
+		d.timer = time.AfterFunc(q.ackTimeout, func() {
+			if d.finish() {
+				d.requeue()
+			}
+		})
+	}
+}
+
+// requeue pushes the item of the finalized delivery back into the queue without blocking.
+// If the queue is full, the delivery is made in-flight again and it returns false.
+func (d *Delivery[T]) requeue() bool {
+	if d.queue.tryRequeue(d.value) {
+		return true
+	}
+	d.queue.deliveryMu.Lock()
+	defer d.queue.deliveryMu.Unlock()
+	d.trackWithoutLock()
+	d.finished.Set(false)
+	return false
+}
+
+// tryRequeue pushes `v` back into the queue if the queue is not closed, without blocking.
+// It returns false if the queue with limit is full, or true if `v` is pushed or discarded as the queue is closed.
+func (q *BlockingQueue[T]) tryRequeue(v T) (ok bool) {
+	defer func() {
+		// The queue might be closed concurrently while pushing.
+		if q.closed.Val() {
+			if recover() != nil {
+				ok = true
+			}
+		}
+	}()
+	if q.closed.Val() {
+		return true
+	}
+	if q.limit <= 0 {
+		q.Push(v)
+		return true
+	}
+	select {
+	case q.C <- v:
+		return true
+	default:
+		return false
+	}
+}
@@ -9,6 +9,7 @@
 package gqueue_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -102,3 +103,254 @@ func Test_Issue2509(t *testing.T) {
 		t.Assert(q.Len(), 0)
 	})
 }
+
+func TestBlockingQueue_Delivery(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int]()
+		q.Push(1)
+		q.Push(2)
+		d, ok := q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(d.Value(), 1)
+		t.Assert(q.Unacked(), 1)
+		t.Assert(d.Ack(), true)
+		t.Assert(d.Ack(), false)
+		t.Assert(d.Nack(true, 0), false)
+		t.Assert(q.Unacked(), 0)
+
+		d, ok = q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(d.Value(), 2)
+		t.Assert(d.Nack(true, 0), true)
+		t.Assert(q.Unacked(), 0)
+		d, ok = q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(d.Value(), 2)
+		t.Assert(d.Nack(false, 0), true)
+		t.Assert(q.Len(), 0)
+		q.Close()
+	})
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int](10)
+		q.Push(1)
+		d, ok := q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(d.Nack(true, 10*time.Millisecond), true)
+		t.Assert(q.Len(), 0)
+		time.Sleep(100 * time.Millisecond)
+		t.Assert(q.Len(), 1)
+		t.Assert(q.MustPop(), 1)
+		q.Close()
+		_, ok = q.PopDelivery()
+		t.Assert(ok, false)
+	})
+}
+
+func TestBlockingQueue_DeliveryRedelivery(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int]()
+		q.SetAckTimeout(20 * time.Millisecond)
+		q.Push(1)
+		d, ok := q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(q.Unacked(), 1)
+
+		// The consumer never acks, so the item is redelivered after the timeout.
+		d2, ok := q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(d2.Value(), 1)
+		t.Assert(d.Ack(), false)
+		t.Assert(d2.Ack(), true)
+		t.Assert(q.Unacked(), 0)
+		q.Close()
+	})
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int](10)
+		q.Push(1)
+		q.Push(2)
+		d1, _ := q.PopDelivery()
+		d2, _ := q.PopDelivery()
+		t.Assert(d1.Ack(), true)
+		t.Assert(q.Recover(), 1)
+		t.Assert(q.Unacked(), 0)
+		t.Assert(d2.Nack(true, 0), false)
+		d, ok := q.PopDelivery()
+		t.Assert(ok, true)
+		t.Assert(d.Value(), 2)
+		t.Assert(d.Ack(), true)
+		t.Assert(q.Recover(), 0)
+		t.Assert(q.Len(), 0)
+		q.Close()
+	})
+}
+
+func TestBlockingQueue_DeliveryFull(t *testing.T) {
+	// The items are requeued without blocking, and stay in-flight if the queue is full.
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int](1)
+		defer q.Close()
+		q.Push(1)
+		d, _ := q.PopDelivery()
+		q.Push(2)
+
+		nacked := make(chan bool, 1)
+		go func() {
+			nacked <- d.Nack(true, 0)
+		}()
+		select {
+		case ok := <-nacked:
+			t.Assert(ok, false)
+		case <-time.After(time.Second):
+			t.Fatal("Nack blocks on a full queue")
+		}
+		t.Assert(q.Unacked(), 1)
+		t.Assert(q.Recover(), 0)
+		t.Assert(q.Unacked(), 1)
+
+		t.Assert(q.MustPop(), 2)
+		t.Assert(d.Nack(true, 0), true)
+		t.Assert(q.Unacked(), 0)
+		t.Assert(q.MustPop(), 1)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int](1)
+		defer q.Close()
+		q.SetAckTimeout(20 * time.Millisecond)
+		q.Push(1)
+		d, _ := q.PopDelivery()
+		q.Push(2)
+		time.Sleep(100 * time.Millisecond)
+		t.Assert(q.Unacked(), 1)
+
+		// The delivery is requeued at the next deadline after the queue has room.
+		t.Assert(q.MustPop(), 2)
+		t.Assert(q.MustPop(), 1)
+		t.Assert(d.Ack(), false)
+		t.Assert(q.Unacked(), 0)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int](1)
+		defer q.Close()
+		q.Push(1)
+		d, _ := q.PopDelivery()
+		q.Push(2)
+		t.Assert(d.Nack(true, 10*time.Millisecond), true)
+		time.Sleep(50 * time.Millisecond)
+		t.Assert(q.Unacked(), 1)
+		t.Assert(q.MustPop(), 2)
+		t.Assert(d.Nack(true, 0), true)
+		t.Assert(q.MustPop(), 1)
+	})
+}
+
+func TestBlockingQueue_WaitUntilEmpty(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for _, q := range []*gqueue.BlockingQueue[int]{gqueue.New[int](), gqueue.New[int](100)} {
+			for i := 0; i < 100; i++ {
+				q.Push(i)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			t.Assert(q.WaitUntilEmpty(ctx), context.DeadlineExceeded)
+			cancel()
+
+			go func() {
+				for range q.C {
+					time.Sleep(time.Microsecond)
+				}
+			}()
+			t.AssertNil(q.WaitUntilEmpty(context.Background()))
+			t.Assert(q.Len(), 0)
+			q.Close()
+		}
+	})
+}
+
+func TestBlockingQueue_WaitUntilEmpty_Pop(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		for _, q := range []*gqueue.BlockingQueue[int]{gqueue.New[int](), gqueue.New[int](100)} {
+			for i := 0; i < 3; i++ {
+				q.Push(i)
+				emptied := make(chan struct{})
+				go func() {
+					_ = q.WaitUntilEmpty(context.Background())
+					close(emptied)
+				}()
+				// The waiter is woken up by Pop at once, instead of at the next check.
+				time.Sleep(100 * time.Millisecond)
+				q.MustPop()
+				select {
+				case <-emptied:
+				case <-time.After(5 * time.Millisecond):
+					t.Error("WaitUntilEmpty is not woken up by Pop")
+				}
+				<-emptied
+			}
+			q.Close()
+		}
+	})
+}
+
+func TestBlockingQueue_Join(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		q := gqueue.New[int](10)
+		for i := 0; i < 10; i++ {
+			q.Push(i)
+		}
+		joined := make(chan struct{})
+		go func() {
+			q.Join()
+			close(joined)
+		}()
+		select {
+		case <-joined:
+			t.Error("Join returned before queue is closed")
+		case <-time.After(10 * time.Millisecond):
+		}
+		q.Close()
+		sum := 0
+		for v := range q.C {
+			sum += v
+		}
+		<-joined
+		t.Assert(sum, 45)
+	})
+}
+
+func TestBlockingQueue_NewWithContext(t *testing.T) {
+	// Closed when context is canceled.
+	gtest.C(t, func(t *gtest.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		q := gqueue.NewWithContext[int](ctx, 0)
+		q.Push(1)
+		t.Assert(q.MustPop(), 1)
+		cancel()
+		_, ok := q.Pop()
+		t.Assert(ok, false)
+	})
+	// Drained before closed.
+	gtest.C(t, func(t *gtest.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		q := gqueue.NewWithContext[int](ctx, 10, true)
+		for i := 0; i < 10; i++ {
+			q.Push(i)
+		}
+		cancel()
+		sum := 0
+		for v := range q.C {
+			sum += v
+			time.Sleep(time.Millisecond)
+		}
+		t.Assert(sum, 45)
+	})
+	// Closed manually before context is done.
+	gtest.C(t, func(t *gtest.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := gqueue.NewWithContext[int](ctx, 10, true)
+		q.Push(1)
+		q.Close()
+		t.Assert(q.MustPop(), 1)
+		q.Join()
+		cancel()
+	})
+}
@@ -8,9 +8,10 @@ package gtimer
 
 import (
 	"context"
+	"errors"
+	"sync"
 	"time"
 
-	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/gtype"
 	"github.com/wesleywu/gcontainer/utils/gerror"
 )
@@ -26,7 +27,9 @@ type Entry struct {
 	isSingleton *gtype.Bool     // Singleton mode.
 	nextTicks   *gtype.Int64    // Next run ticks of the job.
 	infinite    *gtype.Bool     // No times limit.
-	errors      *g.LinkedList[*JobError]
+	timeout     *gtype.Int64    // Timeout in nanoseconds for each run of the job, no timeout if <= 0.
+	errorsMu    sync.Mutex      // Guards errors.
+	errors      []*JobError     // Errors of the past runs, in the order they occur.
 }
 
 type JobError struct {
@@ -34,9 +37,23 @@ type JobError struct {
 	occurs time.Time
 }
 
+// Unwrap returns the error occurred in the job execution, which supports errors.Is and errors.As.
+func (e *JobError) Unwrap() error {
+	return e.error
+}
+
 // JobFunc is the timing called job function in timer.
 type JobFunc = func(ctx context.Context) error
 
+// ErrJobTimeout is the error recorded when a run of the job exceeds the timeout of its entry.
+var ErrJobTimeout = errors.New("job run timeout")
+
+// jobResult is the result of one run of the job, which is either an error or a recovered panic.
+type jobResult struct {
+	err       error
+	exception interface{}
+}
+
 // Status returns the status of the job.
 func (entry *Entry) Status() int {
 	return entry.status.Val()
@@ -70,16 +87,54 @@ func (entry *Entry) Run() {
 				entry.SetStatus(StatusReady)
 			}
 		}()
-		err := entry.job(entry.ctx)
+		err := entry.doRunJob()
 		if err != nil {
-			entry.errors.Add(&JobError{
+			entry.errorsMu.Lock()
+			entry.errors = append(entry.errors, &JobError{
 				error:  err,
 				occurs: time.Now(),
 			})
+			entry.errorsMu.Unlock()
 		}
 	}()
 }
 
+// doRunJob runs the job and returns its error.
+// If the entry has a timeout, the job runs with a context deadline, and it returns an error
+// wrapping ErrJobTimeout without waiting for the job any longer when the deadline exceeds.
+// The panic of the job is propagated to the caller.
+func (entry *Entry) doRunJob() error {
+	timeout := entry.Timeout()
+	if timeout <= 0 {
+		return entry.job(entry.ctx)
+	}
+	ctx, cancel := context.WithTimeout(entry.ctx, timeout)
+	defer cancel()
+	resultChan := make(chan jobResult, 1)
+	go func() {
+		defer func() {
+			if exception := recover(); exception != nil {
+				resultChan <- jobResult{exception: exception}
+			}
+		}()
+		resultChan <- jobResult{err: entry.job(ctx)}
+	}()
+	var result jobResult
+	select {
+	case result = <-resultChan:
+	case <-ctx.Done():
+		if entry.ctx.Err() == nil {
+			return gerror.Wrapf(ErrJobTimeout, `job exceeded timeout of %s`, timeout)
+		}
+		// The parent context is done, it is up to the job how to exit.
+		result = <-resultChan
+	}
+	if result.exception != nil {
+		panic(result.exception)
+	}
+	return result.err
+}
+
 // doCheckAndRunByTicks checks the if job can run in given timer ticks,
 // it runs asynchronously if the given `currentTimerTicks` meets or else
 // it increments its ticks and waits for next running check.
@@ -159,12 +214,33 @@ func (entry *Entry) SetTimes(times int) {
 	entry.infinite.Set(false)
 }
 
+// WithTimeout sets the timeout for each run of the job, and returns the entry itself.
+// Each run of the job is given a context with deadline of `timeout`, and if the job overruns
+// the deadline, an error wrapping ErrJobTimeout is recorded in Errors and the run is considered
+// finished, so that a hung job does not block the following runs of a singleton job.
+// The timeout is disabled if `timeout` <= 0.
+//
+// Note that the overrun job is not killed, it should exit when its context is done.
+func (entry *Entry) WithTimeout(timeout time.Duration) *Entry {
+	entry.timeout.Set(int64(timeout))
+	return entry
+}
+
+// Timeout returns the timeout for each run of the job, which is 0 if no timeout is set.
+func (entry *Entry) Timeout() time.Duration {
+	return time.Duration(entry.timeout.Val())
+}
+
 // HasErrors indicates whether past job executions has errors
 func (entry *Entry) HasErrors() bool {
-	return entry.errors.Len() > 0
+	entry.errorsMu.Lock()
+	defer entry.errorsMu.Unlock()
+	return len(entry.errors) > 0
 }
 
 // Errors returns errors occurred during past job executions
 func (entry *Entry) Errors() []*JobError {
-	return entry.errors.FrontAll()
+	entry.errorsMu.Lock()
+	defer entry.errorsMu.Unlock()
+	return append([]*JobError(nil), entry.errors...)
 }
@@ -10,7 +10,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/wesleywu/gcontainer/g"
 	"github.com/wesleywu/gcontainer/gtype"
 )
 
@@ -207,7 +206,7 @@ func (t *Timer) createEntry(in createEntryInput) *Entry {
 			isSingleton: gtype.NewBool(in.IsSingleton),
 			nextTicks:   gtype.NewInt64(nextTicks),
 			infinite:    gtype.NewBool(infinite),
-			errors:      g.NewLinkedList[*JobError](true),
+			timeout:     gtype.NewInt64(),
 		}
 	)
 	t.queue.Push(entry, nextTicks)
@@ -10,6 +10,7 @@ package gtimer_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -111,3 +112,31 @@ func TestJob_Run(t *testing.T) {
 		t.Assert(array.Len(), 1)
 	})
 }
+
+func TestJob_WithTimeout(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		timer := gtimer.New()
+		array := g.NewArrayList[int](true)
+		job := timer.AddSingleton(ctx, 100*time.Millisecond, func(ctx context.Context) error {
+			array.Add(1)
+			time.Sleep(10 * time.Second)
+			return nil
+		}).WithTimeout(50 * time.Millisecond)
+		t.Assert(job.Timeout(), 50*time.Millisecond)
+		time.Sleep(350 * time.Millisecond)
+		t.AssertGE(array.Len(), 2)
+		t.Assert(job.HasErrors(), true)
+		t.Assert(errors.Is(job.Errors()[0], gtimer.ErrJobTimeout), true)
+		job.Close()
+	})
+	gtest.C(t, func(t *gtest.T) {
+		timer := gtimer.New()
+		done := make(chan error, 1)
+		timer.AddOnce(ctx, 100*time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			done <- ctx.Err()
+			return nil
+		}).WithTimeout(50 * time.Millisecond)
+		t.Assert(<-done, context.DeadlineExceeded)
+	})
+}
@@ -8,46 +8,47 @@ package gtimer
 
 import (
 	"context"
+	"math/rand"
 	"testing"
 	"time"
 
-	"github.com/wesleywu/gcontainer/g"
+	"github.com/wesleywu/gcontainer/gtype"
 	"github.com/wesleywu/gcontainer/internal/gtest"
 )
 
 func TestTimer_Proceed(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
-		array := g.NewArrayList[int](true)
+		count := gtype.NewInt()
 		timer := New(TimerOptions{
 			Interval: time.Hour,
 		})
 		timer.Add(ctx, 10000*time.Hour, func(ctx context.Context) error {
-			array.Add(1)
+			count.Add(1)
 			return nil
 		})
 		timer.proceed(10001)
 		time.Sleep(10 * time.Millisecond)
-		t.Assert(array.Len(), 1)
+		t.Assert(count.Val(), 1)
 		timer.proceed(20001)
 		time.Sleep(10 * time.Millisecond)
-		t.Assert(array.Len(), 2)
+		t.Assert(count.Val(), 2)
 	})
 	gtest.C(t, func(t *gtest.T) {
-		array := g.NewArrayList[int](true)
+		count := gtype.NewInt()
 		timer := New(TimerOptions{
 			Interval: time.Millisecond * 100,
 		})
 		timer.Add(ctx, 10000*time.Hour, func(ctx context.Context) error {
-			array.Add(1)
+			count.Add(1)
 			return nil
 		})
 		ticks := int64((10000 * time.Hour) / (time.Millisecond * 100))
 		timer.proceed(ticks + 1)
 		time.Sleep(10 * time.Millisecond)
-		t.Assert(array.Len(), 1)
+		t.Assert(count.Val(), 1)
 		timer.proceed(2*ticks + 1)
 		time.Sleep(10 * time.Millisecond)
-		t.Assert(array.Len(), 2)
+		t.Assert(count.Val(), 2)
 	})
 }
 
@@ -69,16 +70,12 @@ func TestTimer_PriorityQueue(t *testing.T) {
 
 func TestTimer_PriorityQueue_FirstOneInArrayIsTheLeast(t *testing.T) {
 	gtest.C(t, func(t *gtest.T) {
-		var (
-			size  = 1000000
-			array = g.NewArrayListRange(0, size, 1)
-		)
-		array.Shuffle()
+		size := 1000000
 		queue := newPriorityQueue()
-		array.ForEach(func(v int) bool {
+		// The values are 0 to size, pushed in random order.
+		for _, v := range rand.Perm(size + 1) {
 			queue.Push(v, int64(v))
-			return true
-		})
+		}
 		for i := 0; i < size; i++ {
 			t.Assert(queue.Pop(), i)
 			t.Assert(queue.heap.array[0].priority, i+1)
@@ -24,9 +24,7 @@ func SliceAny[T any](val interface{}) []T {
 	switch value := val.(type) {
 	case []T:
 		array = make([]T, len(value))
-		for k, v := range value {
-			array[k] = v
-		}
+		copy(array, value)
 	case []interface{}:
 		array = make([]T, len(value))
 		for k, v := range value {
@@ -44,6 +42,63 @@ func SliceAny[T any](val interface{}) []T {
 	return array
 }
 
+// SliceTo converts `any` to []T.
+//
+// Different from SliceAny, it returns `any` directly without copying if it is already []T,
+// and it converts the elements if their types are different from T. The common element types
+// like int, int64, uint64, float64 and string use the fast paths without reflection,
+// and the other types are converted element by element using Convert.
+func SliceTo[T any](any interface{}) []T {
+	if any == nil {
+		return nil
+	}
+	if array, ok := any.([]T); ok {
+		return array
+	}
+	var (
+		zero   T
+		result interface{}
+	)
+	switch interface{}(&zero).(type) {
+	case *interface{}:
+		result = Interfaces(any)
+	case *string:
+		result = Strings(any)
+	case *int:
+		result = Ints(any)
+	case *int32:
+		result = Int32s(any)
+	case *int64:
+		result = Int64s(any)
+	case *uint:
+		result = Uints(any)
+	case *uint32:
+		result = Uint32s(any)
+	case *uint64:
+		result = Uint64s(any)
+	case *float32:
+		result = Float32s(any)
+	case *float64:
+		result = Float64s(any)
+	}
+	if result != nil {
+		return result.([]T)
+	}
+	var (
+		items    = Interfaces(any)
+		array    = make([]T, len(items))
+		typeName = reflect.TypeOf(&zero).Elem().String()
+	)
+	for i, item := range items {
+		if v, ok := item.(T); ok {
+			array[i] = v
+		} else {
+			array[i], _ = Convert(item, typeName).(T)
+		}
+	}
+	return array
+}
+
 // Interfaces converts `any` to []interface{}.
 func Interfaces(any interface{}) []interface{} {
 	if any == nil {
@@ -103,8 +158,9 @@ func Interfaces(any interface{}) []interface{} {
 			array[k] = v
 		}
 	case []uint32:
-		for _, v := range value {
-			array = append(array, v)
+		array = make([]interface{}, len(value))
+		for k, v := range value {
+			array[k] = v
 		}
 	case []uint64:
 		array = make([]interface{}, len(value))
@@ -8,6 +8,7 @@ package gconv
 
 import (
 	"reflect"
+	"strconv"
 
 	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/reflection"
@@ -174,7 +175,7 @@ func Float64s(any interface{}) []float64 {
 	case []string:
 		array = make([]float64, len(value))
 		for k, v := range value {
-			array[k] = Float64(v)
+			array[k], _ = strconv.ParseFloat(v, 64)
 		}
 	case []int:
 		array = make([]float64, len(value))
@@ -8,6 +8,7 @@ package gconv
 
 import (
 	"reflect"
+	"strconv"
 
 	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/reflection"
@@ -40,7 +41,12 @@ func Ints(any interface{}) []int {
 	case []string:
 		array = make([]int, len(value))
 		for k, v := range value {
-			array[k] = Int(v)
+			// Fast path for decimal numbers, which avoids boxing the string.
+			if n, err := strconv.ParseInt(v, 10, 0); err == nil {
+				array[k] = int(n)
+			} else {
+				array[k] = Int(v)
+			}
 		}
 	case []int:
 		array = value
@@ -298,7 +304,12 @@ func Int64s(any interface{}) []int64 {
 	case []string:
 		array = make([]int64, len(value))
 		for k, v := range value {
-			array[k] = Int64(v)
+			// Fast path for decimal numbers, which avoids boxing the string.
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				array[k] = n
+			} else {
+				array[k] = Int64(v)
+			}
 		}
 	case []int:
 		array = make([]int64, len(value))
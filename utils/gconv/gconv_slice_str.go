@@ -8,6 +8,7 @@ package gconv
 
 import (
 	"reflect"
+	"strconv"
 
 	"github.com/wesleywu/gcontainer/internal/json"
 	"github.com/wesleywu/gcontainer/internal/reflection"
@@ -30,32 +31,32 @@ func Strings(any interface{}) []string {
 	case []int:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.Itoa(v)
 		}
 	case []int8:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.Itoa(int(v))
 		}
 	case []int16:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.Itoa(int(v))
 		}
 	case []int32:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.Itoa(int(v))
 		}
 	case []int64:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatInt(v, 10)
 		}
 	case []uint:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatUint(uint64(v), 10)
 		}
 	case []uint8:
 		if json.Valid(value) {
@@ -69,32 +70,32 @@ func Strings(any interface{}) []string {
 	case []uint16:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatUint(uint64(v), 10)
 		}
 	case []uint32:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatUint(uint64(v), 10)
 		}
 	case []uint64:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatUint(v, 10)
 		}
 	case []bool:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatBool(v)
 		}
 	case []float32:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatFloat(float64(v), 'f', -1, 32)
 		}
 	case []float64:
 		array = make([]string, len(value))
 		for k, v := range value {
-			array[k] = String(v)
+			array[k] = strconv.FormatFloat(v, 'f', -1, 64)
 		}
 	case []interface{}:
 		array = make([]string, len(value))
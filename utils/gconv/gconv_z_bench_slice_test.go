@@ -0,0 +1,65 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// go test *.go -bench=".*" -benchmem
+
+package gconv_test
+
+import (
+	"testing"
+
+	"github.com/wesleywu/gcontainer/utils/gconv"
+)
+
+var (
+	benchInts    = make([]int, 1000)
+	benchStrings = make([]string, 1000)
+	benchAnys    = make([]interface{}, 1000)
+)
+
+func init() {
+	for i := range benchInts {
+		benchInts[i] = i
+		benchStrings[i] = gconv.String(i)
+		benchAnys[i] = i
+	}
+}
+
+func Benchmark_SliceAny_Typed(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gconv.SliceAny[int](benchInts)
+	}
+}
+
+func Benchmark_SliceTo_Typed(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gconv.SliceTo[int](benchInts)
+	}
+}
+
+func Benchmark_SliceTo_IntsFromInterfaces(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gconv.SliceTo[int](benchAnys)
+	}
+}
+
+func Benchmark_SliceTo_IntsFromStrings(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gconv.SliceTo[int](benchStrings)
+	}
+}
+
+func Benchmark_Strings_FromInts(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gconv.Strings(benchInts)
+	}
+}
+
+func Benchmark_Interfaces_FromInts(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		gconv.Interfaces(benchInts)
+	}
+}
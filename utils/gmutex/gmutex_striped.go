@@ -0,0 +1,229 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+// Package gmutex provides mutex utilities for building concurrent-safe structures.
+package gmutex
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"reflect"
+	"sync"
+)
+
+// cacheLinePadSize is the padding size to place each stripe in its own cache line,
+// which avoids false sharing between stripes. The size of sync.RWMutex is 24 bytes.
+const cacheLinePadSize = 64 - 24
+
+// StripedMutex is a fixed set of sync.RWMutex, which hashes a key to one of the mutexes.
+// It is used for sharded locking, where operations on different keys mostly acquire
+// different mutexes and do not contend with each other.
+type StripedMutex[K comparable] struct {
+	seed    maphash.Seed
+	mask    uint64
+	stripes []stripe
+	hasher  func(key K) uint64 // Custom hash function, nil for the default one.
+}
+
+// stripe is a sync.RWMutex padded to the size of a cache line.
+type stripe struct {
+	sync.RWMutex
+	_ [cacheLinePadSize]byte
+}
+
+// NewStripedMutex creates and returns a StripedMutex with `n` stripes,
+// which is rounded up to the power of two. It has one stripe if `n` <= 1.
+func NewStripedMutex[K comparable](n int) *StripedMutex[K] {
+	size := 1
+	if n > 1 {
+		size = 1 << bits.Len(uint(n-1))
+	}
+	return &StripedMutex[K]{
+		seed:    maphash.MakeSeed(),
+		mask:    uint64(size - 1),
+		stripes: make([]stripe, size),
+	}
+}
+
+// NewStripedMutexFunc creates and returns a StripedMutex with `n` stripes like NewStripedMutex,
+// which hashes the keys with `hasher`. The `hasher` must return the same value for the equal keys,
+// and must not depend on anything that may change between Lock and Unlock of a key.
+func NewStripedMutexFunc[K comparable](n int, hasher func(key K) uint64) *StripedMutex[K] {
+	m := NewStripedMutex[K](n)
+	m.hasher = hasher
+	return m
+}
+
+// Stripes returns the number of stripes.
+func (m *StripedMutex[K]) Stripes() int {
+	return len(m.stripes)
+}
+
+// Index returns the index of the stripe that `key` is hashed to.
+func (m *StripedMutex[K]) Index(key K) int {
+	return int(m.hash(key) & m.mask)
+}
+
+// Get returns the mutex that `key` is hashed to.
+func (m *StripedMutex[K]) Get(key K) *sync.RWMutex {
+	return &m.stripes[m.Index(key)].RWMutex
+}
+
+// At returns the mutex of stripe `index`, which is used with Index for locking a stripe
+// without hashing the key again. It panics if `index` is out of range.
+func (m *StripedMutex[K]) At(index int) *sync.RWMutex {
+	return &m.stripes[index].RWMutex
+}
+
+// Lock locks the mutex of `key` for writing.
+func (m *StripedMutex[K]) Lock(key K) {
+	m.Get(key).Lock()
+}
+
+// Unlock unlocks the mutex of `key` for writing.
+func (m *StripedMutex[K]) Unlock(key K) {
+	m.Get(key).Unlock()
+}
+
+// RLock locks the mutex of `key` for reading.
+func (m *StripedMutex[K]) RLock(key K) {
+	m.Get(key).RLock()
+}
+
+// RUnlock unlocks the mutex of `key` for reading.
+func (m *StripedMutex[K]) RUnlock(key K) {
+	m.Get(key).RUnlock()
+}
+
+// LockAll locks all the stripes for writing in order, which is used for whole-structure operations.
+func (m *StripedMutex[K]) LockAll() {
+	for i := range m.stripes {
+		m.stripes[i].Lock()
+	}
+}
+
+// UnlockAll unlocks all the stripes for writing.
+func (m *StripedMutex[K]) UnlockAll() {
+	for i := len(m.stripes) - 1; i >= 0; i-- {
+		m.stripes[i].Unlock()
+	}
+}
+
+// RLockAll locks all the stripes for reading in order.
+func (m *StripedMutex[K]) RLockAll() {
+	for i := range m.stripes {
+		m.stripes[i].RLock()
+	}
+}
+
+// RUnlockAll unlocks all the stripes for reading.
+func (m *StripedMutex[K]) RUnlockAll() {
+	for i := len(m.stripes) - 1; i >= 0; i-- {
+		m.stripes[i].RUnlock()
+	}
+}
+
+// hash returns the hash value of `key`.
+// Strings and integers are hashed directly, and other types are hashed by their identity as compared
+// with ==, e.g. pointers by address rather than the values they point to, so that a key is always hashed
+// to the same stripe while its pointee changes.
+func (m *StripedMutex[K]) hash(key K) uint64 {
+	if m.hasher != nil {
+		return m.hasher(key)
+	}
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(m.seed, k)
+	case int:
+		return mixHash(uint64(k))
+	case int64:
+		return mixHash(uint64(k))
+	case int32:
+		return mixHash(uint64(k))
+	case uint:
+		return mixHash(uint64(k))
+	case uint64:
+		return mixHash(k)
+	case uint32:
+		return mixHash(uint64(k))
+	default:
+		var h maphash.Hash
+		h.SetSeed(m.seed)
+		writeComparable(&h, reflect.ValueOf(&key).Elem())
+		return h.Sum64()
+	}
+}
+
+// writeComparable writes comparable value `v` to `h`, so that the values equal with == are written the same.
+func writeComparable(h *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			_ = h.WriteByte(1)
+		} else {
+			_ = h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(h, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat(h, real(c))
+		writeFloat(h, imag(c))
+	case reflect.String:
+		writeUint64(h, uint64(v.Len()))
+		_, _ = h.WriteString(v.String())
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Chan:
+		writeUint64(h, uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			_ = h.WriteByte(0)
+			return
+		}
+		elem := v.Elem()
+		_, _ = h.WriteString(elem.Type().String())
+		writeComparable(h, elem)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeComparable(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeComparable(h, v.Field(i))
+		}
+	}
+}
+
+// writeFloat writes float `f` to `h`, in which -0 is written as 0 as they are equal.
+func writeFloat(h *maphash.Hash, f float64) {
+	if f == 0 {
+		f = 0
+	}
+	writeUint64(h, math.Float64bits(f))
+}
+
+// writeUint64 writes `v` to `h` in little endian.
+func writeUint64(h *maphash.Hash, v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	_, _ = h.Write(b[:])
+}
+
+// mixHash scrambles the bits of integer `x`, so that sequential integers spread over stripes.
+// It is the finalizer of the splitmix64 generator.
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
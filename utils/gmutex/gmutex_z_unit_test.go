@@ -0,0 +1,122 @@
+// Copyright GoFrame Author(https://goframe.org). All Rights Reserved.
+//
+// This Source Code Form is subject to the terms of the MIT License.
+// If a copy of the MIT was not distributed with this file,
+// You can obtain one at https://github.com/gogf/gf.
+
+package gmutex_test
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/wesleywu/gcontainer/internal/gtest"
+	"github.com/wesleywu/gcontainer/utils/gmutex"
+)
+
+func Test_StripedMutex_Stripes(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		t.Assert(gmutex.NewStripedMutex[int](0).Stripes(), 1)
+		t.Assert(gmutex.NewStripedMutex[int](1).Stripes(), 1)
+		t.Assert(gmutex.NewStripedMutex[int](5).Stripes(), 8)
+		t.Assert(gmutex.NewStripedMutex[int](16).Stripes(), 16)
+	})
+}
+
+func Test_StripedMutex_Index(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := gmutex.NewStripedMutex[string](8)
+		used := make(map[int]struct{})
+		for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+			index := m.Index(key)
+			t.AssertGE(index, 0)
+			t.AssertLT(index, 8)
+			t.Assert(m.Index(key), index)
+			t.Assert(m.Get(key) == m.At(index), true)
+			used[index] = struct{}{}
+		}
+		t.AssertGT(len(used), 1)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		type key struct {
+			a int
+			b string
+		}
+		m := gmutex.NewStripedMutex[key](4)
+		t.Assert(m.Index(key{1, "a"}), m.Index(key{1, "a"}))
+	})
+}
+
+func Test_StripedMutex_Lock(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		var (
+			m      = gmutex.NewStripedMutex[int](4)
+			wg     sync.WaitGroup
+			counts = make(map[int]int)
+		)
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				m.LockAll()
+				counts[i%10]++
+				m.UnlockAll()
+			}(i)
+		}
+		wg.Wait()
+		m.RLockAll()
+		for i := 0; i < 10; i++ {
+			t.Assert(counts[i], 10)
+		}
+		m.RUnlockAll()
+
+		m.Lock(1)
+		m.Unlock(1)
+		m.RLock(1)
+		m.RLock(1)
+		m.RUnlock(1)
+		m.RUnlock(1)
+	})
+}
+
+func Test_StripedMutex_IndexIdentity(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		type value struct {
+			N int
+		}
+		m := gmutex.NewStripedMutex[*value](64)
+		p := &value{N: 1}
+		index := m.Index(p)
+		for i := 0; i < 100; i++ {
+			p.N = i
+			t.Assert(m.Index(p), index)
+		}
+		m.Lock(p)
+		p.N = -1
+		m.Unlock(p)
+	})
+	gtest.C(t, func(t *gtest.T) {
+		m := gmutex.NewStripedMutex[float64](64)
+		t.Assert(m.Index(math.Copysign(0, -1)), m.Index(0))
+
+		type key struct {
+			v any
+			f float32
+		}
+		km := gmutex.NewStripedMutex[key](64)
+		t.Assert(km.Index(key{"a", 1}), km.Index(key{"a", 1}))
+		t.Assert(km.Index(key{nil, 0}), km.Index(key{nil, float32(math.Copysign(0, -1))}))
+	})
+}
+
+func Test_StripedMutex_Func(t *testing.T) {
+	gtest.C(t, func(t *gtest.T) {
+		m := gmutex.NewStripedMutexFunc[string](4, func(key string) uint64 {
+			return uint64(len(key))
+		})
+		t.Assert(m.Index("a"), 1)
+		t.Assert(m.Index("abcde"), 1)
+		t.Assert(m.Index("ab"), 2)
+	})
+}